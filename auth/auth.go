@@ -0,0 +1,320 @@
+/*
+Package auth authenticates the users of Revere's web UI and API, and checks
+that they have the role each route needs.
+
+Users are authenticated in one of three modes, set in Revere's environment
+configuration:
+
+	local: Users and their bcrypt password hashes are kept in Revere's
+	database, and are managed with Revere's user mode. Users log in with a
+	form, and scripts use HTTP basic authentication.
+
+	proxy: A reverse proxy in front of Revere authenticates users, and passes
+	on their names, and optionally their roles, in request headers.
+
+	oidc: Users log in with an OpenID Connect provider.
+
+Logged in users are remembered with a signed session cookie. If no mode is
+set, authentication is off and every request is allowed.
+*/
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/juju/errors"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/db"
+)
+
+// Authentication modes.
+const (
+	Local = "local"
+	Proxy = "proxy"
+	OIDC  = "oidc"
+)
+
+// Role is what a user is allowed to do. Each role can do everything the roles
+// before it can.
+type Role int
+
+const (
+	// NoRole can do nothing.
+	NoRole Role = iota
+	// Viewer can view monitors, alerts and everything else but resources and
+	// settings.
+	Viewer
+	// Editor can also change monitors, labels, silences, escalation policies
+	// and schedules, and acknowledge alerts.
+	Editor
+	// Admin can also change resources and settings.
+	Admin
+)
+
+var roleNames = []string{"none", "viewer", "editor", "admin"}
+
+// ParseRole returns the role with the given name, ignoring case.
+func ParseRole(s string) (Role, error) {
+	for r, name := range roleNames {
+		if strings.EqualFold(s, name) {
+			return Role(r), nil
+		}
+	}
+	return NoRole, errors.Errorf("unknown role %q", s)
+}
+
+func (r Role) String() string {
+	if r < 0 || int(r) >= len(roleNames) {
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+	return roleNames[r]
+}
+
+// User is an authenticated user.
+type User struct {
+	Name string
+	Role Role
+}
+
+// AuthJSONModel provides the settings for an Auth. It is used as the
+// structure for configuring authentication in Revere's environment
+// configuration JSON file.
+type AuthJSONModel struct {
+	// Mode is local, proxy or oidc. If it is empty, authentication is off.
+	Mode string
+
+	// SessionKey is the secret session cookies are signed with. If it is
+	// empty, a random key is used, and users have to log in again whenever
+	// Revere restarts.
+	SessionKey string
+	// SessionHours is how long users stay logged in. It defaults to 12.
+	SessionHours int
+	// SecureCookies should be set when Revere is served over HTTPS, so that
+	// session cookies are never sent over plain HTTP.
+	SecureCookies bool
+
+	// Roles gives the roles of proxy and OIDC users by name, taking
+	// precedence over the roles passed on by the proxy or provider.
+	// DefaultRole is the role of everyone else. Local users have the role
+	// they were given when they were added.
+	Roles       map[string]string
+	DefaultRole string
+
+	Proxy ProxyJSONModel
+	OIDC  OIDCJSONModel
+}
+
+// Auth authenticates requests according to its mode.
+type Auth struct {
+	mode string
+	DB   *db.DB
+
+	sessionKey    []byte
+	sessionLength time.Duration
+	secureCookies bool
+
+	roles       map[string]Role
+	defaultRole Role
+
+	proxy ProxyJSONModel
+	oidc  *oidcProvider
+}
+
+type userKey struct{}
+
+// New validates conf and creates an Auth that looks up local users in DB.
+func New(conf AuthJSONModel, DB *db.DB) (*Auth, error) {
+	a := &Auth{
+		mode:          conf.Mode,
+		DB:            DB,
+		sessionKey:    []byte(conf.SessionKey),
+		sessionLength: time.Duration(conf.SessionHours) * time.Hour,
+		secureCookies: conf.SecureCookies,
+		roles:         make(map[string]Role),
+	}
+
+	switch a.mode {
+	case "", Local:
+	case Proxy:
+		a.proxy = conf.Proxy
+		if a.proxy.UserHeader == "" {
+			a.proxy.UserHeader = "X-Forwarded-User"
+		}
+	case OIDC:
+		var err error
+		a.oidc, err = newOIDCProvider(conf.OIDC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	default:
+		return nil, errors.Errorf("unknown auth mode %q", a.mode)
+	}
+
+	if len(a.sessionKey) == 0 {
+		a.sessionKey = randomBytes(32)
+	}
+	if a.sessionLength <= 0 {
+		a.sessionLength = 12 * time.Hour
+	}
+
+	for name, role := range conf.Roles {
+		r, err := ParseRole(role)
+		if err != nil {
+			return nil, errors.Maskf(err, "role of %s", name)
+		}
+		a.roles[name] = r
+	}
+	if conf.DefaultRole != "" {
+		r, err := ParseRole(conf.DefaultRole)
+		if err != nil {
+			return nil, errors.Maskf(err, "default role")
+		}
+		a.defaultRole = r
+	}
+
+	return a, nil
+}
+
+// Enabled returns whether requests are authenticated at all.
+func (a *Auth) Enabled() bool {
+	return a.mode != ""
+}
+
+// Mode returns how users are authenticated.
+func (a *Auth) Mode() string {
+	return a.mode
+}
+
+// Require wraps handlers so that they are only run for users with at least
+// the given role. The user is available to the handler through UserFrom.
+// Other requests are sent to the login page, or refused if they come from
+// scripts.
+func (a *Auth) Require(role Role) func(httprouter.Handle) httprouter.Handle {
+	return func(h httprouter.Handle) httprouter.Handle {
+		if !a.Enabled() {
+			return h
+		}
+
+		return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+			u, err := a.authenticate(req)
+			if err != nil {
+				log.WithError(err).Error("Unable to authenticate request.")
+				deny(w, req, http.StatusInternalServerError,
+					fmt.Sprintf("Unable to authenticate: %s", err.Error()))
+				return
+			}
+			if u == nil {
+				a.unauthenticated(w, req)
+				return
+			}
+			if u.Role < role {
+				deny(w, req, http.StatusForbidden,
+					fmt.Sprintf("%s does not have the %s role.", u.Name, role))
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), userKey{}, u)
+			h(w, req.WithContext(ctx), p)
+		}
+	}
+}
+
+// UserFrom returns the user a request was authenticated as, or nil if it was
+// not authenticated.
+func UserFrom(req *http.Request) *User {
+	u, _ := req.Context().Value(userKey{}).(*User)
+	return u
+}
+
+// UserName returns the name of the user a request was authenticated as, or
+// the empty string if it was not authenticated.
+func UserName(req *http.Request) string {
+	if u := UserFrom(req); u != nil {
+		return u.Name
+	}
+	return ""
+}
+
+// authenticate returns the user that made req, or nil if it cannot tell.
+func (a *Auth) authenticate(req *http.Request) (*User, error) {
+	if a.mode == Proxy {
+		return a.proxyUser(req), nil
+	}
+
+	s, err := a.session(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s != nil {
+		if a.mode != Local {
+			return &User{Name: s.Name, Role: s.Role}, nil
+		}
+		// Local users are looked up again so that removing a user or
+		// changing their role takes effect immediately.
+		return a.localUser(s.Name)
+	}
+
+	if name, password, ok := req.BasicAuth(); ok && a.mode == Local {
+		return a.Login(name, password)
+	}
+	return nil, nil
+}
+
+// unauthenticated sends pages to the login page, and refuses everything else.
+func (a *Auth) unauthenticated(w http.ResponseWriter, req *http.Request) {
+	if a.mode != Proxy && req.Method == http.MethodGet && !isAPI(req) {
+		http.Redirect(w, req, "/login?next="+url.QueryEscape(req.URL.RequestURI()), http.StatusFound)
+		return
+	}
+
+	if a.mode == Local {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Revere"`)
+	}
+	deny(w, req, http.StatusUnauthorized, "Log in to continue.")
+}
+
+// roleFor returns the role of a proxy or OIDC user with the given name, who
+// the proxy or provider says has the given roles.
+func (a *Auth) roleFor(name string, roles []string) Role {
+	if r, ok := a.roles[name]; ok {
+		return r
+	}
+
+	best := NoRole
+	for _, role := range roles {
+		r, err := ParseRole(strings.TrimSpace(role))
+		if err == nil && r > best {
+			best = r
+		}
+	}
+	if best != NoRole {
+		return best
+	}
+	return a.defaultRole
+}
+
+func isAPI(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.Path, "/api/")
+}
+
+// deny responds with an error in the form the API uses for API requests, and
+// as plain text otherwise.
+func deny(w http.ResponseWriter, req *http.Request, status int, msg string) {
+	if !isAPI(req) {
+		http.Error(w, msg, status)
+		return
+	}
+
+	body, _ := json.Marshal(map[string][]string{"errors": {msg}})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
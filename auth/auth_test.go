@@ -0,0 +1,289 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func newTestAuth(t *testing.T, conf AuthJSONModel) *Auth {
+	a, err := New(conf, nil)
+	if err != nil {
+		t.Fatalf("Failed to create auth: %s\n", err.Error())
+	}
+	return a
+}
+
+// serve runs req through a handler requiring role, returning the response and
+// the user the handler saw.
+func serve(a *Auth, role Role, req *http.Request) (*httptest.ResponseRecorder, *User) {
+	var seen *User
+	h := a.Require(role)(func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		seen = UserFrom(req)
+	})
+
+	w := httptest.NewRecorder()
+	h(w, req, nil)
+	return w, seen
+}
+
+func TestParseRole(t *testing.T) {
+	for _, r := range []Role{NoRole, Viewer, Editor, Admin} {
+		parsed, err := ParseRole(strings.ToUpper(r.String()))
+		if err != nil || parsed != r {
+			t.Errorf("Expected %s, got %s, %v\n", r, parsed, err)
+		}
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("Expected an error for an unknown role")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, conf := range []AuthJSONModel{
+		{Mode: "ldap"},
+		{Mode: Proxy, DefaultRole: "root"},
+		{Mode: Proxy, Roles: map[string]string{"alice": "root"}},
+		{Mode: OIDC, OIDC: OIDCJSONModel{ClientID: "revere"}},
+	} {
+		if _, err := New(conf, nil); err == nil {
+			t.Errorf("Expected an error for %+v\n", conf)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	a := newTestAuth(t, AuthJSONModel{})
+	w, u := serve(a, Admin, httptest.NewRequest("POST", "/settings", nil))
+	if w.Code != http.StatusOK || u != nil {
+		t.Errorf("Expected the request to be allowed anonymously, got %d, %+v\n", w.Code, u)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	a := newTestAuth(t, AuthJSONModel{
+		Mode:        Proxy,
+		Proxy:       ProxyJSONModel{RoleHeader: "X-Roles"},
+		Roles:       map[string]string{"bob": "admin"},
+		DefaultRole: "viewer",
+	})
+
+	cases := []struct {
+		user, roles string
+		role        Role
+		status      int
+	}{
+		{"", "", Viewer, http.StatusUnauthorized},
+		{"alice", "", Viewer, http.StatusOK},
+		{"alice", "", Editor, http.StatusForbidden},
+		{"alice", "viewer, editor", Editor, http.StatusOK},
+		{"alice", "wizard", Editor, http.StatusForbidden},
+		{"bob", "viewer", Admin, http.StatusOK},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/monitors", nil)
+		req.Header.Set("X-Forwarded-User", c.user)
+		req.Header.Set("X-Roles", c.roles)
+		w, u := serve(a, c.role, req)
+		if w.Code != c.status {
+			t.Errorf("Expected %d for %q with roles %q needing %s, got %d\n", c.status, c.user, c.roles, c.role, w.Code)
+		}
+		if c.status == http.StatusOK && (u == nil || u.Name != c.user) {
+			t.Errorf("Expected the handler to see %q, got %+v\n", c.user, u)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	a := newTestAuth(t, AuthJSONModel{Mode: Proxy})
+	w, _ := serve(a, Viewer, httptest.NewRequest("GET", "/api/v1/monitors", nil))
+	if w.Code != http.StatusUnauthorized || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected a JSON 401, got %d %s\n", w.Code, w.Header().Get("Content-Type"))
+	}
+	var body map[string][]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body["errors"]) != 1 {
+		t.Errorf("Expected an errors list, got %s\n", w.Body.String())
+	}
+}
+
+func TestSession(t *testing.T) {
+	a := newTestAuth(t, AuthJSONModel{Mode: OIDC, OIDC: OIDCJSONModel{
+		Issuer: "https://id.example.com", ClientID: "revere", RedirectURL: "https://revere.example.com/login/callback",
+	}})
+
+	w, _ := serve(a, Viewer, httptest.NewRequest("GET", "/monitors?label=1", nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/login?next=%2Fmonitors%3Flabel%3D1" {
+		t.Errorf("Expected a redirect to the login page, got %d %s\n", w.Code, w.Header().Get("Location"))
+	}
+
+	rec := httptest.NewRecorder()
+	if err := a.StartSession(rec, &User{Name: "alice", Role: Editor}); err != nil {
+		t.Fatalf("Failed to start session: %s\n", err.Error())
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest("POST", "/monitors/1/edit", nil)
+	req.AddCookie(cookie)
+	w, u := serve(a, Editor, req)
+	if w.Code != http.StatusOK || u == nil || u.Name != "alice" {
+		t.Errorf("Expected alice to be allowed, got %d, %+v\n", w.Code, u)
+	}
+
+	tampered := *cookie
+	tampered.Value = strings.Replace(cookie.Value, ".", "x.", 1)
+	req = httptest.NewRequest("POST", "/monitors/1/edit", nil)
+	req.AddCookie(&tampered)
+	if w, _ := serve(a, Editor, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a tampered session to be refused, got %d\n", w.Code)
+	}
+
+	a.sessionLength = -time.Minute
+	rec = httptest.NewRecorder()
+	a.StartSession(rec, &User{Name: "alice", Role: Editor})
+	req = httptest.NewRequest("POST", "/monitors/1/edit", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	if w, _ := serve(a, Editor, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an expired session to be refused, got %d\n", w.Code)
+	}
+}
+
+func idToken(claims map[string]interface{}) string {
+	payload, _ := json.Marshal(claims)
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestOIDCLogin(t *testing.T) {
+	var issuer string
+	var claims map[string]interface{}
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer": %q, "authorization_endpoint": "%s/auth", "token_endpoint": "%s/token"}`,
+				issuer, issuer, issuer)
+		case "/token":
+			if id, secret, _ := req.BasicAuth(); id != "revere" || secret != "s3cret" || req.FormValue("code") != "abc" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_grant"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"id_token": %q}`, idToken(claims))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer provider.Close()
+	issuer = provider.URL
+
+	a := newTestAuth(t, AuthJSONModel{
+		Mode: OIDC,
+		OIDC: OIDCJSONModel{
+			Issuer: issuer, ClientID: "revere", ClientSecret: "s3cret",
+			RedirectURL: "https://revere.example.com/login/callback", RoleClaim: "groups",
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	err := a.StartOIDCLogin(rec, httptest.NewRequest("GET", "/login", nil), "/monitors")
+	if err != nil {
+		t.Fatalf("Failed to start login: %s\n", err.Error())
+	}
+	redirect, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || redirect.Path != "/auth" || redirect.Query().Get("client_id") != "revere" {
+		t.Fatalf("Expected a redirect to the provider, got %s\n", rec.Header().Get("Location"))
+	}
+	state := redirect.Query().Get("state")
+	cookie := rec.Result().Cookies()[0]
+
+	claims = map[string]interface{}{
+		"iss":    issuer,
+		"aud":    []string{"revere"},
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"nonce":  redirect.Query().Get("nonce"),
+		"email":  "alice@example.com",
+		"groups": []string{"staff", "editor"},
+	}
+
+	callback := func(state string) (*User, string, error) {
+		req := httptest.NewRequest("GET", "/login/callback?code=abc&state="+state, nil)
+		req.AddCookie(cookie)
+		return a.FinishOIDCLogin(httptest.NewRecorder(), req)
+	}
+
+	if _, _, err := callback("forged"); err == nil {
+		t.Error("Expected an error for the wrong state")
+	}
+
+	u, next, err := callback(state)
+	if err != nil {
+		t.Fatalf("Failed to finish login: %s\n", err.Error())
+	}
+	if u.Name != "alice@example.com" || u.Role != Editor || next != "/monitors" {
+		t.Errorf("Expected alice to log in as an editor, got %+v going to %s\n", u, next)
+	}
+
+	claims["nonce"] = "replayed"
+	if _, _, err := callback(state); err == nil {
+		t.Error("Expected an error for the wrong nonce")
+	}
+}
+
+func TestCheckClaims(t *testing.T) {
+	o := &oidcProvider{OIDCJSONModel: OIDCJSONModel{Issuer: "https://id/", ClientID: "revere"}}
+	now := time.Unix(1000, 0)
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{"iss": "https://id", "aud": "revere", "exp": 1060.0, "nonce": "n"}
+	}
+
+	if err := o.checkClaims(valid(), "n", now); err != nil {
+		t.Errorf("Unexpected error: %s\n", err.Error())
+	}
+
+	cases := map[string]func(map[string]interface{}){
+		"issuer":    func(c map[string]interface{}) { c["iss"] = "https://evil" },
+		"no issuer": func(c map[string]interface{}) { delete(c, "iss") },
+		"audience":  func(c map[string]interface{}) { c["aud"] = []interface{}{"other"} },
+		"expired":   func(c map[string]interface{}) { c["exp"] = 1000.0 },
+		"nonce":     func(c map[string]interface{}) { delete(c, "nonce") },
+	}
+	for name, change := range cases {
+		claims := valid()
+		change(claims)
+		if err := o.checkClaims(claims, "n", now); err == nil {
+			t.Errorf("Expected an error for a bad %s\n", name)
+		}
+	}
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	var issuer string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, `{"issuer": %q, "authorization_endpoint": "%s/auth", "token_endpoint": "%s/token"}`,
+			issuer, issuer, issuer)
+	}))
+	defer provider.Close()
+
+	o, err := newOIDCProvider(OIDCJSONModel{
+		Issuer: provider.URL + "/", ClientID: "revere", RedirectURL: "https://revere.example.com/login/callback",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %s\n", err.Error())
+	}
+
+	issuer = "https://evil.example.com"
+	if _, err := o.discover(); err == nil {
+		t.Error("Expected an error for a configuration from another issuer")
+	}
+
+	issuer = provider.URL
+	if _, err := o.discover(); err != nil {
+		t.Errorf("Unexpected error for the configured issuer: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"github.com/juju/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yext/revere/db"
+)
+
+const minPasswordLength = 8
+
+// dummyHash is compared against when logging in as a user that does not
+// exist, so that doing so takes as long as getting a password wrong.
+var dummyHash = []byte("$2a$10$pkZFlZMI0LUnTdOrjdAVweHfm5jLdVbldCBd6D0LRDmpuXLUs2Otu")
+
+// Login checks the password of a local user, returning nil if there is no such
+// user or the password is wrong.
+func (a *Auth) Login(name, password string) (*User, error) {
+	u, err := a.DB.LoadUser(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u == nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, nil
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
+	if err != nil {
+		return nil, nil
+	}
+	return newLocalUser(u)
+}
+
+// localUser loads the local user with the given name, returning nil if there
+// is no such user.
+func (a *Auth) localUser(name string) (*User, error) {
+	u, err := a.DB.LoadUser(name)
+	if err != nil || u == nil {
+		return nil, errors.Trace(err)
+	}
+	return newLocalUser(u)
+}
+
+func newLocalUser(u *db.User) (*User, error) {
+	role, err := ParseRole(u.Role)
+	if err != nil {
+		return nil, errors.Maskf(err, "load user %s", u.Username)
+	}
+	return &User{Name: u.Username, Role: role}, nil
+}
+
+// SetUser adds a local user, or changes the password and role of an existing
+// one. If password is empty, an existing user keeps their password.
+func (a *Auth) SetUser(name, password string, role Role) error {
+	if name == "" {
+		return errors.New("user name is empty")
+	}
+	if role == NoRole {
+		return errors.New("users need a role")
+	}
+
+	u, err := a.DB.LoadUser(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u == nil {
+		u = &db.User{Username: name}
+	}
+	u.Role = role.String()
+
+	if password != "" || u.PasswordHash == "" {
+		if len(password) < minPasswordLength {
+			return errors.Errorf("passwords must be at least %d characters", minPasswordLength)
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return errors.Maskf(err, "hash password")
+		}
+		u.PasswordHash = string(hash)
+	}
+
+	return errors.Trace(a.DB.SaveUser(u))
+}
+
+// DeleteUser removes a local user.
+func (a *Auth) DeleteUser(name string) error {
+	return errors.Trace(a.DB.DeleteUser(name))
+}
@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	oidcCookie      = "revere-oidc"
+	oidcLoginLength = 10 * time.Minute
+)
+
+// OIDCJSONModel configures the oidc mode. Revere is registered with the
+// provider as a confidential client using the authorization code flow.
+type OIDCJSONModel struct {
+	// Issuer is the provider's issuer URL, which its configuration is
+	// discovered from.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of Revere's /login/callback page, as registered
+	// with the provider.
+	RedirectURL string
+	// UserClaim is the ID token claim users are named by. It defaults to
+	// email.
+	UserClaim string
+	// RoleClaim optionally names an ID token claim holding the user's role,
+	// or a list of roles of which the highest is used.
+	RoleClaim string
+}
+
+type oidcProvider struct {
+	OIDCJSONModel
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *oidcEndpoints
+}
+
+// oidcEndpoints is the part of the provider's configuration Revere uses.
+type oidcEndpoints struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// oidcLogin is the content of the cookie that ties a login's callback to the
+// browser that started it.
+type oidcLogin struct {
+	State   string
+	Nonce   string
+	Next    string
+	Expires int64
+}
+
+func newOIDCProvider(conf OIDCJSONModel) (*oidcProvider, error) {
+	if conf.Issuer == "" || conf.ClientID == "" || conf.RedirectURL == "" {
+		return nil, errors.New("oidc mode needs an issuer, client ID and redirect URL")
+	}
+	if conf.UserClaim == "" {
+		conf.UserClaim = "email"
+	}
+	return &oidcProvider{
+		OIDCJSONModel: conf,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// StartOIDCLogin sends the user to the provider to log in. Once they have,
+// the provider sends them back to the redirect URL, where FinishOIDCLogin
+// completes the login and returns next.
+func (a *Auth) StartOIDCLogin(w http.ResponseWriter, req *http.Request, next string) error {
+	e, err := a.oidc.discover()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	expires := time.Now().Add(oidcLoginLength)
+	login := oidcLogin{
+		State:   randomString(),
+		Nonce:   randomString(),
+		Next:    next,
+		Expires: expires.Unix(),
+	}
+	value, err := a.sign(login)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.setCookie(w, oidcCookie, value, expires)
+
+	u, err := url.Parse(e.AuthorizationEndpoint)
+	if err != nil {
+		return errors.Maskf(err, "parse authorization endpoint")
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", a.oidc.ClientID)
+	q.Set("redirect_uri", a.oidc.RedirectURL)
+	q.Set("scope", "openid email profile")
+	q.Set("state", login.State)
+	q.Set("nonce", login.Nonce)
+	u.RawQuery = q.Encode()
+
+	http.Redirect(w, req, u.String(), http.StatusFound)
+	return nil
+}
+
+// FinishOIDCLogin handles the provider's redirect back to Revere, returning
+// the user who logged in and the page they were going to.
+func (a *Auth) FinishOIDCLogin(w http.ResponseWriter, req *http.Request) (*User, string, error) {
+	c, err := req.Cookie(oidcCookie)
+	if err != nil {
+		return nil, "", errors.New("The login has expired. Please log in again.")
+	}
+	a.setCookie(w, oidcCookie, "", time.Unix(1, 0))
+
+	var login oidcLogin
+	if !a.verify(c.Value, &login) || time.Now().Unix() >= login.Expires {
+		return nil, "", errors.New("The login has expired. Please log in again.")
+	}
+	if e := req.FormValue("error"); e != "" {
+		return nil, "", errors.Errorf("The provider refused the login: %s %s", e, req.FormValue("error_description"))
+	}
+	if req.FormValue("state") != login.State {
+		return nil, "", errors.New("The login did not come from this browser. Please log in again.")
+	}
+
+	claims, err := a.oidc.exchange(req.FormValue("code"))
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	err = a.oidc.checkClaims(claims, login.Nonce, time.Now())
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+
+	name, _ := claims[a.oidc.UserClaim].(string)
+	if name == "" {
+		return nil, "", errors.Errorf("The ID token has no %s claim.", a.oidc.UserClaim)
+	}
+	var roles []string
+	switch r := claims[a.oidc.RoleClaim].(type) {
+	case string:
+		roles = []string{r}
+	case []interface{}:
+		for _, role := range r {
+			if s, ok := role.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+	}
+
+	return &User{Name: name, Role: a.roleFor(name, roles)}, login.Next, nil
+}
+
+// discover loads the provider's configuration, the first time it is needed.
+func (o *oidcProvider) discover() (*oidcEndpoints, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.endpoints != nil {
+		return o.endpoints, nil
+	}
+
+	u := strings.TrimSuffix(o.Issuer, "/") + "/.well-known/openid-configuration"
+	resp, err := o.client.Get(u)
+	if err != nil {
+		return nil, errors.Maskf(err, "discover OIDC provider")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("discover OIDC provider: %s returned %s", u, resp.Status)
+	}
+
+	var e oidcEndpoints
+	err = json.NewDecoder(resp.Body).Decode(&e)
+	if err != nil {
+		return nil, errors.Maskf(err, "discover OIDC provider")
+	}
+	if e.AuthorizationEndpoint == "" || e.TokenEndpoint == "" {
+		return nil, errors.Errorf("discover OIDC provider: %s is missing endpoints", u)
+	}
+	// The configuration must be for the configured issuer, or whoever serves
+	// it could name themselves the issuer of the tokens Revere accepts.
+	if !sameIssuer(e.Issuer, o.Issuer) {
+		return nil, errors.Errorf("discover OIDC provider: %s is for issuer %q, not %q", u, e.Issuer, o.Issuer)
+	}
+	o.endpoints = &e
+	return o.endpoints, nil
+}
+
+// exchange redeems an authorization code for an ID token, and returns the
+// token's claims.
+//
+// The token's signature is not checked. It comes straight from the provider's
+// token endpoint, which the OIDC spec allows to be trusted on the strength of
+// the TLS connection instead.
+func (o *oidcProvider) exchange(code string) (map[string]interface{}, error) {
+	e, err := o.discover()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {o.RedirectURL},
+	}
+	req, err := http.NewRequest(http.MethodPost, e.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Maskf(err, "redeem authorization code")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, errors.Maskf(err, "redeem authorization code")
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return nil, errors.Maskf(err, "redeem authorization code")
+	}
+	if token.Error != "" || resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("redeem authorization code: %s %s %s",
+			resp.Status, token.Error, token.ErrorDescription)
+	}
+
+	return parseIDToken(token.IDToken)
+}
+
+func parseIDToken(idToken string) (map[string]interface{}, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, errors.Maskf(err, "decode ID token")
+	}
+
+	var claims map[string]interface{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, errors.Maskf(err, "decode ID token")
+	}
+	return claims, nil
+}
+
+// checkClaims checks that an ID token was issued by the configured issuer to
+// Revere for the login with the given nonce, and has not expired.
+func (o *oidcProvider) checkClaims(claims map[string]interface{}, nonce string, now time.Time) error {
+	if iss, _ := claims["iss"].(string); !sameIssuer(iss, o.Issuer) {
+		return errors.Errorf("The ID token was issued by %q, not %q.", iss, o.Issuer)
+	}
+
+	var audience []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audience = []string{aud}
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audience = append(audience, s)
+			}
+		}
+	}
+	found := false
+	for _, a := range audience {
+		found = found || a == o.ClientID
+	}
+	if !found {
+		return errors.New("The ID token was not issued to Revere.")
+	}
+
+	exp, _ := claims["exp"].(float64)
+	if now.Unix() >= int64(exp) {
+		return errors.New("The ID token has expired.")
+	}
+	if n, _ := claims["nonce"].(string); n != nonce {
+		return errors.New("The ID token is not for this login.")
+	}
+	return nil
+}
+
+// sameIssuer returns whether two issuer URLs name the same issuer, ignoring
+// trailing slashes.
+func sameIssuer(a, b string) bool {
+	return a != "" && strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+}
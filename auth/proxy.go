@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ProxyJSONModel configures the proxy mode. Revere trusts these headers
+// completely, so in this mode it must only be reachable through the proxy.
+type ProxyJSONModel struct {
+	// UserHeader is the header holding the user's name. It defaults to
+	// X-Forwarded-User.
+	UserHeader string
+	// RoleHeader optionally names a header holding the user's role, or a
+	// comma-separated list of roles of which the highest is used.
+	RoleHeader string
+}
+
+// proxyUser returns the user the proxy says made req, or nil if it did not
+// say.
+func (a *Auth) proxyUser(req *http.Request) *User {
+	name := strings.TrimSpace(req.Header.Get(a.proxy.UserHeader))
+	if name == "" {
+		return nil
+	}
+
+	var roles []string
+	if a.proxy.RoleHeader != "" {
+		roles = strings.Split(req.Header.Get(a.proxy.RoleHeader), ",")
+	}
+	return &User{Name: name, Role: a.roleFor(name, roles)}
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const sessionCookie = "revere-session"
+
+// session is the content of a session cookie.
+type session struct {
+	Name    string
+	Role    Role
+	Expires int64
+}
+
+// StartSession logs u in, by setting a session cookie on w.
+func (a *Auth) StartSession(w http.ResponseWriter, u *User) error {
+	expires := time.Now().Add(a.sessionLength)
+	value, err := a.sign(session{Name: u.Name, Role: u.Role, Expires: expires.Unix()})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	a.setCookie(w, sessionCookie, value, expires)
+	return nil
+}
+
+// EndSession logs the user out, by removing their session cookie.
+func (a *Auth) EndSession(w http.ResponseWriter) {
+	a.setCookie(w, sessionCookie, "", time.Unix(1, 0))
+}
+
+// session returns the unexpired session in req's session cookie, or nil if
+// there is none.
+func (a *Auth) session(req *http.Request) (*session, error) {
+	c, err := req.Cookie(sessionCookie)
+	if err == http.ErrNoCookie {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var s session
+	if !a.verify(c.Value, &s) || time.Now().Unix() >= s.Expires {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (a *Auth) setCookie(w http.ResponseWriter, name, value string, expires time.Time) {
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   a.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if value == "" {
+		c.MaxAge = -1
+	}
+	http.SetCookie(w, c)
+}
+
+// sign serializes v into a cookie value that cannot be changed without
+// knowing the session key.
+func (a *Auth) sign(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + a.mac(payload), nil
+}
+
+// verify deserializes a cookie value made by sign into v, returning whether
+// it was valid.
+func (a *Auth) verify(value string, v interface{}) bool {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return false
+	}
+	payload, mac := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(mac), []byte(a.mac(payload))) {
+		return false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func (a *Auth) mac(payload string) string {
+	h := hmac.New(sha256.New, a.sessionKey)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.Annotate(err, "read random bytes"))
+	}
+	return b
+}
+
+func randomString() string {
+	return base64.RawURLEncoding.EncodeToString(randomBytes(24))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/env"
+	"github.com/yext/revere/metrics"
 )
 
 // Daemon represents the part of Revere that actually executes monitors and
@@ -18,6 +19,8 @@ type Daemon struct {
 
 	lastMonitorsUpdate time.Time
 
+	deliverer *deliverer
+
 	stop    chan struct{}
 	stopper sync.Once
 	stopped chan struct{}
@@ -32,11 +35,14 @@ func New(env *env.Env) *Daemon {
 		stop:     make(chan struct{}),
 		stopped:  make(chan struct{}),
 		Env:      env,
+
+		deliverer: newDeliverer(env),
 	}
 }
 
 // Start starts running a Daemon.
 func (d *Daemon) Start() {
+	d.deliverer.start()
 	go d.run()
 }
 
@@ -70,6 +76,7 @@ func (d *Daemon) updateMonitors() {
 	infos, err := d.DB.LoadMonitorVersionInfosUpdatedSince(threshold)
 	if err != nil {
 		log.WithError(err).Error("Could not load list of updated monitors.")
+		metrics.MonitorLoadFailures.Inc()
 	}
 
 	for _, info := range infos {
@@ -104,6 +111,7 @@ func (d *Daemon) updateMonitors() {
 			log.WithError(err).WithFields(log.Fields{
 				"monitor": info.MonitorID,
 			}).Error("Load monitor failed.")
+			metrics.MonitorLoadFailures.Inc()
 
 			// TODO(eefi): Put in a placeholder that constantly
 			// reports _:Unknown to Revere admin. For now, just
@@ -116,6 +124,7 @@ func (d *Daemon) updateMonitors() {
 	}
 
 	d.lastMonitorsUpdate = currentUpdateTime
+	metrics.RunningMonitors.Set(float64(len(d.monitors)))
 }
 
 // Stop gracefully stops a Daemon. It tries to allow any in-progress delivery of
@@ -136,6 +145,9 @@ func (d *Daemon) Stop() {
 			m.stop()
 			delete(d.monitors, id)
 		}
+		metrics.RunningMonitors.Set(0)
+
+		d.deliverer.stopAndWait()
 
 		log.Info("Daemon has stopped.")
 	})
@@ -0,0 +1,472 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/env"
+	"github.com/yext/revere/target"
+)
+
+const (
+	defaultDeliveryWorkers = 4
+	deliveryPollPeriod     = 2 * time.Second
+	deliveryBatchSize      = 20
+
+	// defaultDeliveryLease is how long a claimed delivery is hidden from
+	// other pollers. Leases are extended every half lease until the
+	// delivery has been sent.
+	defaultDeliveryLease = 5 * time.Minute
+)
+
+// queuedTargets is the JSON saved with each queued alert describing where it
+// goes.
+type queuedTargets struct {
+	ToAlert  []queuedTarget
+	Inactive []types.JSONText
+}
+
+type queuedTarget struct {
+	TriggerID db.TriggerID
+	Config    types.JSONText
+}
+
+func enqueueAlert(Db *db.DB, a *target.Alert, targetType target.Type, targets queuedTargets) error {
+	delivery, err := newAlertDelivery(a, targetType, targets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	_, err = Db.EnqueueAlertDelivery(delivery)
+	return errors.Trace(err)
+}
+
+// enqueueGroupedAlert adds a to the queued delivery for groupKey, or queues a
+// new delivery for the group to be sent at sendAt if there is none open.
+func enqueueGroupedAlert(
+	Db *db.DB, a *target.Alert, targetType target.Type, targets queuedTargets,
+	groupKey string, sendAt time.Time) error {
+	delivery, err := newAlertDelivery(a, targetType, targets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	delivery.GroupKey = groupKey
+	delivery.NextAttempt = sendAt
+
+	_, err = Db.AddToAlertDeliveryGroup(delivery, delivery.Alert)
+	return errors.Trace(err)
+}
+
+func newAlertDelivery(a *target.Alert, targetType target.Type, targets queuedTargets) (*db.AlertDelivery, error) {
+	alertJSON, err := a.Serialize()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	targetsJSON, err := json.Marshal(targets)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize targets")
+	}
+
+	now := time.Now()
+	return &db.AlertDelivery{
+		MonitorID:   a.MonitorID,
+		SubprobeID:  a.SubprobeID,
+		TargetType:  targetType.ID(),
+		Alert:       alertJSON,
+		Targets:     types.JSONText(targetsJSON),
+		Created:     now,
+		NextAttempt: now,
+	}, nil
+}
+
+// deliverer sends queued alerts with a pool of workers, retrying failures with
+// exponential backoff according to each target type's retry policy.
+type deliverer struct {
+	deliveries chan *db.AlertDelivery
+	workers    int
+	lease      time.Duration
+
+	// claimed holds the deliveries this deliverer has claimed and not yet
+	// finished with, whose leases it keeps extending.
+	claimedMu sync.Mutex
+	claimed   map[db.AlertDeliveryID]bool
+
+	stop    chan struct{}
+	stopped chan struct{}
+	working sync.WaitGroup
+	done    chan struct{}
+
+	*env.Env
+}
+
+func newDeliverer(env *env.Env) *deliverer {
+	d := &deliverer{
+		deliveries: make(chan *db.AlertDelivery),
+		workers:    env.Delivery.Workers,
+		lease:      time.Duration(env.Delivery.LeaseSeconds) * time.Second,
+		claimed:    make(map[db.AlertDeliveryID]bool),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+		done:       make(chan struct{}),
+		Env:        env,
+	}
+	if d.workers == 0 {
+		d.workers = defaultDeliveryWorkers
+	}
+	if d.lease == 0 {
+		d.lease = defaultDeliveryLease
+	}
+	return d
+}
+
+func (d *deliverer) start() {
+	for i := 0; i < d.workers; i++ {
+		d.working.Add(1)
+		go d.work()
+	}
+	go d.extendLeases()
+	go d.run()
+}
+
+func (d *deliverer) run() {
+	defer close(d.stopped)
+	defer close(d.deliveries)
+
+	t := time.NewTicker(deliveryPollPeriod)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if !d.poll() {
+				return
+			}
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// poll hands due deliveries to the workers. It returns false if the
+// deliverer was stopped.
+func (d *deliverer) poll() bool {
+	// Hold the lock while claiming so that leases are only extended for
+	// deliveries that are in claimed.
+	d.claimedMu.Lock()
+	deliveries, err := d.DB.ClaimDueAlertDeliveries(time.Now(), deliveryBatchSize, d.lease)
+	for _, delivery := range deliveries {
+		d.claimed[delivery.AlertDeliveryID] = true
+	}
+	d.claimedMu.Unlock()
+	if err != nil {
+		log.WithError(err).Error("Could not load queued alerts.")
+		return true
+	}
+
+	for _, delivery := range deliveries {
+		select {
+		case d.deliveries <- delivery:
+		case <-d.stop:
+			// Unsent deliveries will be claimed again once their
+			// lease runs out.
+			return false
+		}
+	}
+	return true
+}
+
+// extendLeases keeps extending the leases of claimed deliveries, including
+// those waiting for a worker, so that no other poller claims and sends them
+// again while this deliverer is still on them.
+func (d *deliverer) extendLeases() {
+	t := time.NewTicker(d.lease / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			d.claimedMu.Lock()
+			ids := make([]db.AlertDeliveryID, 0, len(d.claimed))
+			for id := range d.claimed {
+				ids = append(ids, id)
+			}
+			err := d.DB.ExtendAlertDeliveryLeases(ids, time.Now().Add(d.lease))
+			d.claimedMu.Unlock()
+			if err != nil {
+				log.WithError(err).Error("Could not extend leases of queued alerts.")
+			}
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// release stops extending the lease of delivery, before its outcome is saved.
+func (d *deliverer) release(delivery *db.AlertDelivery) {
+	d.claimedMu.Lock()
+	delete(d.claimed, delivery.AlertDeliveryID)
+	d.claimedMu.Unlock()
+}
+
+func (d *deliverer) work() {
+	defer d.working.Done()
+	for delivery := range d.deliveries {
+		d.deliver(delivery)
+	}
+}
+
+func (d *deliverer) deliver(delivery *db.AlertDelivery) {
+	logger := log.WithFields(log.Fields{
+		"delivery":   delivery.AlertDeliveryID,
+		"monitor":    delivery.MonitorID,
+		"subprobe":   delivery.SubprobeID,
+		"targetType": delivery.TargetType,
+		"attempt":    delivery.Attempts + 1,
+	})
+
+	s, err := newDeliverySend(delivery)
+	var failed *queuedTargets
+	if err == nil {
+		failed, err = s.send(d.DB)
+	}
+	if err == nil {
+		d.release(delivery)
+		err = d.DB.DeleteAlertDelivery(delivery.AlertDeliveryID)
+		if err != nil {
+			logger.WithError(err).Error("Could not remove sent alert from queue.")
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if failed != nil {
+		targetsJSON, err := json.Marshal(failed)
+		if err == nil {
+			delivery.Targets = types.JSONText(targetsJSON)
+		}
+	}
+
+	policy := target.RetryPolicy{}
+	if targetType, lookupErr := target.LookupType(delivery.TargetType); lookupErr == nil {
+		policy = targetType.Retries()
+	}
+
+	if failed == nil || delivery.Attempts >= policy.MaxAttempts {
+		delivery.Dead = true
+		if s != nil {
+			// Only now have the failures given up for good.
+			s.recordFailures(d.DB)
+		}
+		logger.WithError(err).Error("Alert could not be sent and has been moved to dead letters.")
+	} else {
+		delivery.NextAttempt = time.Now().Add(policy.Backoff(delivery.Attempts))
+		logger.WithError(err).WithField("nextAttempt", delivery.NextAttempt).
+			Warn("Alert could not be sent. It will be retried.")
+	}
+
+	d.release(delivery)
+	err = d.DB.UpdateAlertDelivery(delivery)
+	if err != nil {
+		logger.WithError(err).Error("Could not save failed alert to queue.")
+	}
+}
+
+// deliverySend is an attempt to send a delivery.
+type deliverySend struct {
+	targetType target.Type
+	alerts     []*target.Alert
+	targets    queuedTargets
+	toAlert    map[db.TriggerID]target.Target
+	configs    map[db.TriggerID]types.JSONText
+	inactive   []target.Target
+
+	// errs are the failures of the attempt, once it has been made.
+	errs []target.ErrorAndTriggerIDs
+}
+
+// newDeliverySend loads what is needed to send delivery. It fails if the
+// delivery cannot be sent at all, e.g. because it is malformed.
+func newDeliverySend(delivery *db.AlertDelivery) (*deliverySend, error) {
+	var err error
+	s := &deliverySend{
+		toAlert: make(map[db.TriggerID]target.Target),
+		configs: make(map[db.TriggerID]types.JSONText),
+	}
+
+	s.targetType, err = target.LookupType(delivery.TargetType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	s.alerts, err = loadDeliveryAlerts(delivery)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = delivery.Targets.Unmarshal(&s.targets)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize targets")
+	}
+
+	for _, t := range s.targets.ToAlert {
+		s.toAlert[t.TriggerID], err = s.targetType.New(t.Config)
+		if err != nil {
+			return nil, errors.Maskf(err, "make target for trigger %d", t.TriggerID)
+		}
+		s.configs[t.TriggerID] = t.Config
+	}
+
+	for _, config := range s.targets.Inactive {
+		t, err := s.targetType.New(config)
+		if err != nil {
+			return nil, errors.Maskf(err, "make inactive target")
+		}
+		s.inactive = append(s.inactive, t)
+	}
+	return s, nil
+}
+
+// send sends the alerts, recording those that were sent in the alert history.
+// If some targets fail, it returns the targets still to be sent to with the
+// error. Inactive targets are always sent to again, so failures sending to
+// them alone are retried too. Failures are only recorded by recordFailures,
+// once they will no longer be retried, so that each alert is recorded once.
+func (s *deliverySend) send(Db *db.DB) (*queuedTargets, error) {
+	s.errs = alertAll(Db, s.targetType, s.alerts, s.toAlert, s.inactive)
+
+	sent := make(map[db.TriggerID]target.Target, len(s.toAlert))
+	for id, t := range s.toAlert {
+		sent[id] = t
+	}
+	for _, errAndIDs := range s.errs {
+		for _, id := range errAndIDs.IDs {
+			delete(sent, id)
+		}
+	}
+	if len(sent) > 0 {
+		for _, a := range s.alerts {
+			recordAlerts(Db, a, s.targetType, sent, nil)
+		}
+	}
+
+	return s.failed()
+}
+
+// failed returns the targets still to be sent to after the attempt, with its
+// error, or nil if every target was sent to.
+func (s *deliverySend) failed() (*queuedTargets, error) {
+	if len(s.errs) == 0 {
+		return nil, nil
+	}
+
+	failed := &queuedTargets{Inactive: s.targets.Inactive}
+	var messages []string
+	for _, errAndIDs := range s.errs {
+		messages = append(messages, errAndIDs.Err.Error())
+		for _, id := range errAndIDs.IDs {
+			failed.ToAlert = append(failed.ToAlert, queuedTarget{
+				TriggerID: id,
+				Config:    s.configs[id],
+			})
+		}
+	}
+	return failed, errors.New(strings.Join(messages, "\n"))
+}
+
+// recordFailures records the targets that failed in the alert history.
+func (s *deliverySend) recordFailures(Db *db.DB) {
+	failed := make(map[db.TriggerID]target.Target)
+	for _, errAndIDs := range s.errs {
+		for _, id := range errAndIDs.IDs {
+			failed[id] = s.toAlert[id]
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	for _, a := range s.alerts {
+		recordAlerts(Db, a, s.targetType, failed, s.errs)
+	}
+}
+
+// loadDeliveryAlerts loads the alert queued with delivery and any alerts
+// grouped with it.
+func loadDeliveryAlerts(delivery *db.AlertDelivery) ([]*target.Alert, error) {
+	a, err := target.LoadAlert(delivery.Alert)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	alerts := []*target.Alert{a}
+
+	if len(delivery.GroupedAlerts) == 0 {
+		return alerts, nil
+	}
+
+	var grouped []types.JSONText
+	err = delivery.GroupedAlerts.Unmarshal(&grouped)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize grouped alerts")
+	}
+	for _, alertJSON := range grouped {
+		a, err := target.LoadAlert(alertJSON)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+// alertAll sends alerts to the targets in toAlert, in one message if there are
+// several and the target type supports it.
+func alertAll(
+	Db *db.DB, targetType target.Type, alerts []*target.Alert,
+	toAlert map[db.TriggerID]target.Target, inactive []target.Target) []target.ErrorAndTriggerIDs {
+	if len(alerts) == 1 {
+		return targetType.Alert(Db, alerts[0], toAlert, inactive)
+	}
+
+	if groupAlerter, ok := targetType.(target.GroupAlerter); ok {
+		return groupAlerter.AlertGroup(Db, alerts, toAlert, inactive)
+	}
+
+	// Retrying resends the whole group, so a target is failed if any of
+	// its alerts failed. Failures with no triggers are kept, as they are
+	// of inactive targets.
+	var errs []target.ErrorAndTriggerIDs
+	failed := make(map[db.TriggerID]bool)
+	for _, a := range alerts {
+		for _, errAndIDs := range targetType.Alert(Db, a, toAlert, inactive) {
+			var ids []db.TriggerID
+			for _, id := range errAndIDs.IDs {
+				if !failed[id] {
+					failed[id] = true
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) > 0 || len(errAndIDs.IDs) == 0 {
+				errs = append(errs, target.ErrorAndTriggerIDs{Err: errAndIDs.Err, IDs: ids})
+			}
+		}
+	}
+	return errs
+}
+
+// stopAndWait stops polling for deliveries, then waits for in-progress
+// deliveries to finish.
+func (d *deliverer) stopAndWait() {
+	close(d.stop)
+	<-d.stopped
+	d.working.Wait()
+	close(d.done)
+}
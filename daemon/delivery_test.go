@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+)
+
+func TestFailedResolveKeepsInactiveTargets(t *testing.T) {
+	pagerDuty, err := target.LookupType(3)
+	if err != nil {
+		t.Fatalf("Could not look up PagerDuty: %s", err)
+	}
+	a := &target.Alert{
+		MonitorID:  1,
+		SubprobeID: 2,
+		OldState:   state.Warning,
+		NewState:   state.Normal,
+		Recorded:   time.Now(),
+	}
+	config := types.JSONText(`{"IntegrationKey": "` + testPagerDutyKey + `"}`)
+	delivery, err := newAlertDelivery(a, pagerDuty, queuedTargets{Inactive: []types.JSONText{config}})
+	if err != nil {
+		t.Fatalf("Could not make delivery: %s", err)
+	}
+
+	s, err := newDeliverySend(delivery)
+	if err != nil {
+		t.Fatalf("Could not load delivery: %s", err)
+	}
+	// The resolve sent to the inactive target failed.
+	s.errs = []target.ErrorAndTriggerIDs{{Err: errors.New("resolve failed")}}
+
+	failed, err := s.failed()
+	if failed == nil || err == nil {
+		t.Fatalf("Expected the failed resolve to be retried, got %+v, %v", failed, err)
+	}
+	if len(failed.ToAlert) != 0 || len(failed.Inactive) != 1 {
+		t.Errorf("Expected the retry to resolve for the inactive target only, got %+v", failed)
+	}
+
+	targetsJSON, err := json.Marshal(failed)
+	if err != nil {
+		t.Fatalf("Could not serialize targets: %s", err)
+	}
+	delivery.Targets = types.JSONText(targetsJSON)
+	s, err = newDeliverySend(delivery)
+	if err != nil {
+		t.Fatalf("Could not load retried delivery: %s", err)
+	}
+	if len(s.toAlert) != 0 || len(s.inactive) != 1 {
+		t.Errorf("Expected the retried delivery to have 1 inactive target, got %+v", s)
+	}
+
+	s.errs = nil
+	if failed, err = s.failed(); failed != nil || err != nil {
+		t.Errorf("Expected nothing left to retry once the resolve is sent, got %+v, %v", failed, err)
+	}
+}
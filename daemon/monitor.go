@@ -3,12 +3,14 @@ package daemon
 import (
 	"regexp"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
 
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/env"
+	"github.com/yext/revere/metrics"
 	"github.com/yext/revere/probe"
 	"github.com/yext/revere/state"
 )
@@ -20,6 +22,17 @@ type monitor struct {
 	response    string
 	version     int32
 
+	// Subprobes change state only once new states have been sustained for
+	// sustainReadings consecutive readings or for sustainPeriod, whichever
+	// comes first. Either may be unset.
+	sustainReadings int
+	sustainPeriod   time.Duration
+
+	// Subprobes are flapping while they have changed state more than
+	// flapTransitions times within flapWindow.
+	flapTransitions int
+	flapWindow      time.Duration
+
 	probe    probe.Probe
 	triggers []monitorTrigger
 
@@ -54,7 +67,8 @@ func newMonitor(id db.MonitorID, env *env.Env) (*monitor, error) {
 
 	readingsChan := make(chan []probe.Reading)
 
-	probe, err := probe.New(tx, dbMonitor.ProbeType, dbMonitor.Probe, readingsChan)
+	probe, err := probe.New(tx, probe.MonitorInfo{ID: id, DB: env.DB},
+		dbMonitor.ProbeType, dbMonitor.Probe, readingsChan)
 	if err != nil {
 		return nil, errors.Maskf(err, "make probe for monitor %d", id)
 	}
@@ -69,8 +83,13 @@ func newMonitor(id db.MonitorID, env *env.Env) (*monitor, error) {
 		return nil, errors.Maskf(err, "load label triggers for monitor %d", id)
 	}
 
-	monitorTriggers := make(
-		[]monitorTrigger, 0, len(dbMonitorTriggers)+len(dbLabelTriggers))
+	dbEscalationSteps, err := tx.LoadEscalationStepsForMonitor(id)
+	if err != nil {
+		return nil, errors.Maskf(err, "load escalation steps for monitor %d", id)
+	}
+
+	monitorTriggers := make([]monitorTrigger, 0,
+		len(dbMonitorTriggers)+len(dbLabelTriggers)+len(dbEscalationSteps))
 	for _, dbMonitorTrigger := range dbMonitorTriggers {
 		monitorTrigger, err := newMonitorTrigger(
 			dbMonitorTrigger.Subprobes, dbMonitorTrigger.Trigger, env)
@@ -96,6 +115,20 @@ func newMonitor(id db.MonitorID, env *env.Env) (*monitor, error) {
 		}
 		monitorTriggers = append(monitorTriggers, *monitorTrigger)
 	}
+	for _, dbEscalationStep := range dbEscalationSteps {
+		monitorTrigger, err := newMonitorTrigger(
+			dbEscalationStep.Subprobes, dbEscalationStep.Trigger, env)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"monitor":          id,
+				"escalationPolicy": dbEscalationStep.EscalationPolicyID,
+				"trigger":          dbEscalationStep.TriggerID,
+			}).Error("Could not load escalation step. Discarding.")
+			continue
+		}
+		monitorTrigger.delay = time.Duration(dbEscalationStep.DelayMilli) * time.Millisecond
+		monitorTriggers = append(monitorTriggers, *monitorTrigger)
+	}
 
 	monitor := &monitor{
 		id:             id,
@@ -109,6 +142,12 @@ func newMonitor(id db.MonitorID, env *env.Env) (*monitor, error) {
 		readingsSource: readingsChan,
 		stopped:        make(chan struct{}),
 		Env:            env,
+
+		sustainReadings: int(dbMonitor.SustainReadings),
+		sustainPeriod:   time.Duration(dbMonitor.SustainMilli) * time.Millisecond,
+
+		flapTransitions: int(dbMonitor.FlapTransitions),
+		flapWindow:      time.Duration(dbMonitor.FlapWindowMilli) * time.Millisecond,
 	}
 
 	dbSubprobeStatuses, err := tx.LoadSubprobeStatusesForMonitor(id)
@@ -145,6 +184,26 @@ func newMonitorTrigger(subprobes string, dbTrigger *db.Trigger, env *env.Env) (*
 	}, nil
 }
 
+// sustains returns whether this monitor's subprobes wait for new states to be
+// sustained before changing state.
+func (m *monitor) sustains() bool {
+	return m.sustainReadings > 1 || m.sustainPeriod > 0
+}
+
+// sustained returns whether a pending state change seen for the given number
+// of consecutive readings, over the given time, has been sustained long enough
+// to take effect.
+func (m *monitor) sustained(readings int, elapsed time.Duration) bool {
+	return (m.sustainReadings > 1 && readings >= m.sustainReadings) ||
+		(m.sustainPeriod > 0 && elapsed >= m.sustainPeriod)
+}
+
+// detectsFlapping returns whether this monitor's subprobes are checked for
+// flapping.
+func (m *monitor) detectsFlapping() bool {
+	return m.flapTransitions > 0 && m.flapWindow > 0
+}
+
 func (m *monitor) start() {
 	m.probe.Start()
 	go func() {
@@ -163,11 +222,15 @@ func (m *monitor) process(readings []probe.Reading) {
 	m.logReadings(readings)
 
 	var silences []silence
+	var acks map[db.SubprobeID]*db.Acknowledgement
 	if m.shouldLoadSilences(readings) {
 		silences = m.loadActiveSilences()
+		acks = m.loadActiveAcknowledgements()
 	}
 
 	for _, r := range readings {
+		metrics.Readings.WithLabelValues(r.State.String()).Inc()
+
 		subprobe := m.subprobes[r.Subprobe]
 		if subprobe == nil {
 			var err error
@@ -193,7 +256,7 @@ func (m *monitor) process(readings []probe.Reading) {
 			}
 		}
 
-		subprobe.process(r, isSilenced)
+		subprobe.process(r, isSilenced, acks[subprobe.id])
 	}
 }
 
@@ -264,6 +327,22 @@ func (m *monitor) loadActiveSilences() []silence {
 	return silences
 }
 
+func (m *monitor) loadActiveAcknowledgements() map[db.SubprobeID]*db.Acknowledgement {
+	dbAcks, err := m.DB.LoadActiveAcknowledgementsForMonitor(m.id)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"monitor": m.id,
+		}).Error("Could not load acknowledgements. Proceeding without them.")
+		return nil
+	}
+
+	acks := make(map[db.SubprobeID]*db.Acknowledgement, len(dbAcks))
+	for i := range dbAcks {
+		acks[dbAcks[i].SubprobeID] = &dbAcks[i]
+	}
+	return acks
+}
+
 func (m *monitor) stop() {
 	m.stopper.Do(func() {
 		m.probe.Stop()
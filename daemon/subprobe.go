@@ -23,6 +23,20 @@ type subprobe struct {
 	enteredState time.Time
 	lastNormal   time.Time
 
+	// pendingState is the state this subprobe will change to once the
+	// monitor's sustain settings are met. There is no pending change if
+	// pendingReadings is 0.
+	pendingState    state.State
+	pendingReadings int
+	pendingSince    time.Time
+
+	// transitions holds this subprobe's state changes within the monitor's
+	// flap window. While flapping, flapWorst is the worst state the
+	// subprobe has been in.
+	transitions []transition
+	flapping    bool
+	flapWorst   state.State
+
 	saveNextReading bool
 
 	triggerSets map[db.TargetType]sameTypeTriggerSet
@@ -31,7 +45,7 @@ type subprobe struct {
 }
 
 func newSubprobe(name string, status db.SubprobeStatus, monitor *monitor) *subprobe {
-	return &subprobe{
+	s := &subprobe{
 		id:              status.SubprobeID,
 		monitor:         monitor,
 		name:            name,
@@ -39,21 +53,43 @@ func newSubprobe(name string, status db.SubprobeStatus, monitor *monitor) *subpr
 		state:           status.State,
 		enteredState:    status.EnteredState,
 		lastNormal:      status.LastNormal,
+		pendingState:    status.PendingState,
+		pendingReadings: int(status.PendingReadings),
+		flapping:        status.Flapping,
+		flapWorst:       status.State,
 		saveNextReading: false,
 		triggerSets:     newSubprobeTriggerSets(monitor, name),
 		Env:             monitor.Env,
 	}
+	if status.PendingSince != nil {
+		s.pendingSince = *status.PendingSince
+	} else {
+		s.pendingReadings = 0
+	}
+	if s.flapping {
+		// The last state change is the only one we know of, but it's
+		// enough to keep the subprobe flapping until it stabilizes.
+		s.transitions = []transition{{status.EnteredState, status.State, status.State}}
+	}
+	return s
 }
 
 // createSubprobe creates a new subprobe in the database based on receiving a
 // reading for a previously unknown subprobe.
 func createSubprobe(monitor *monitor, reading probe.Reading) (*subprobe, error) {
+	// If new states must be sustained, the first reading has to be
+	// sustained like any other.
+	initialState := reading.State
+	if monitor.sustains() {
+		initialState = state.Normal
+	}
+
 	s := &subprobe{
 		monitor: monitor,
 		name:    reading.Subprobe,
 
 		lastReading:  reading.Recorded,
-		state:        reading.State,
+		state:        initialState,
 		enteredState: reading.Recorded,
 
 		// A bit of a lie if state != Normal, but it's the best we have.
@@ -111,17 +147,35 @@ func newSubprobeTriggerSets(monitor *monitor, name string) map[db.TargetType]sam
 	return triggerSets
 }
 
-func (s *subprobe) process(r probe.Reading, isSilenced bool) {
+func (s *subprobe) process(r probe.Reading, isSilenced bool, ack *db.Acknowledgement) {
 	oldState := s.state
 
 	s.updateFor(r)
 
-	if !isSilenced {
-		alert := s.newAlert(oldState, r)
+	alert := s.newAlert(oldState, r)
+	shouldAlert := s.updateFlapping(alert)
+	isAcked := s.acknowledged(ack, alert)
+
+	if !isSilenced && shouldAlert && !isAcked {
 		for _, triggerSet := range s.triggerSets {
 			triggerSet.alert(alert)
 		}
-	} else if r.State != state.Normal && log.GetLevel() >= log.DebugLevel {
+	} else if !shouldAlert && log.GetLevel() >= log.DebugLevel {
+		log.WithFields(log.Fields{
+			"monitor":  s.monitor.id,
+			"subprobe": s.name,
+			"state":    s.state,
+			"recorded": r.Recorded,
+		}).Debug("Suppressing alerts for flapping subprobe.")
+	} else if isAcked && log.GetLevel() >= log.DebugLevel {
+		log.WithFields(log.Fields{
+			"monitor":  s.monitor.id,
+			"subprobe": s.name,
+			"state":    s.state,
+			"recorded": r.Recorded,
+			"ackedBy":  ack.AckedBy,
+		}).Debug("Suppressing repeat alerts for acknowledged subprobe.")
+	} else if isSilenced && r.State != state.Normal && log.GetLevel() >= log.DebugLevel {
 		log.WithFields(log.Fields{
 			"monitor":  s.monitor.id,
 			"subprobe": s.name,
@@ -130,6 +184,14 @@ func (s *subprobe) process(r probe.Reading, isSilenced bool) {
 		}).Debug("Suppressing alerts for silenced subprobe.")
 	}
 
+	if s.state == state.Normal {
+		// Escalations start over once the subprobe has recovered, even if
+		// the recovery was not sent.
+		for _, triggerSet := range s.triggerSets {
+			triggerSet.resetEscalations()
+		}
+	}
+
 	if err := s.record(r, isSilenced); err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"monitor":  s.monitor.id,
@@ -141,9 +203,10 @@ func (s *subprobe) process(r probe.Reading, isSilenced bool) {
 }
 
 func (s *subprobe) updateFor(r probe.Reading) {
-	stateChanged := s.state != r.State
+	newState := s.sustainedState(r)
+	stateChanged := s.state != newState
 	s.lastReading = r.Recorded
-	s.state = r.State
+	s.state = newState
 	if stateChanged {
 		s.enteredState = r.Recorded
 	}
@@ -153,6 +216,118 @@ func (s *subprobe) updateFor(r probe.Reading) {
 	s.saveNextReading = s.saveNextReading || stateChanged
 }
 
+// sustainedState returns the state s should be in after reading r, updating
+// its pending state change. If the monitor requires new states to be
+// sustained, s only changes state once enough consecutive readings, or
+// readings for long enough, have moved away from its current state in the
+// same direction, and then changes to the least extreme of their states. This
+// applies equally to recoveries.
+func (s *subprobe) sustainedState(r probe.Reading) state.State {
+	if r.State == s.state || !s.monitor.sustains() {
+		s.pendingReadings = 0
+		return r.State
+	}
+
+	worsening := r.State > s.state
+	switch {
+	case s.pendingReadings == 0 || worsening != (s.pendingState > s.state):
+		s.pendingState = r.State
+		s.pendingReadings = 1
+		s.pendingSince = r.Recorded
+	case worsening && r.State < s.pendingState, !worsening && r.State > s.pendingState:
+		s.pendingState = r.State
+		s.pendingReadings++
+	default:
+		s.pendingReadings++
+	}
+
+	if !s.monitor.sustained(s.pendingReadings, r.Recorded.Sub(s.pendingSince)) {
+		return s.state
+	}
+
+	s.pendingReadings = 0
+	return s.pendingState
+}
+
+// acknowledged returns whether a is a repeat alert suppressed by ack, which
+// may be nil. Once s recovers or becomes worse than the acknowledged state, the
+// acknowledgement no longer applies and is removed.
+func (s *subprobe) acknowledged(ack *db.Acknowledgement, a *target.Alert) bool {
+	if ack == nil {
+		return false
+	}
+
+	if s.state == state.Normal || s.state > ack.State {
+		if err := s.DB.DeleteAcknowledgement(s.id); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"monitor":  s.monitor.id,
+				"subprobe": s.name,
+			}).Error("Could not remove acknowledgement.")
+		}
+		return false
+	}
+
+	return a.OldState == a.NewState && !a.Flapping && !a.StoppedFlapping
+}
+
+// updateFlapping records the state change, if any, that a describes and
+// updates whether s is flapping. It returns whether a should be sent. If s has
+// just started or stopped flapping, a is marked accordingly.
+func (s *subprobe) updateFlapping(a *target.Alert) bool {
+	if !s.monitor.detectsFlapping() {
+		s.transitions = nil
+		s.flapping = false
+		return true
+	}
+
+	if a.OldState != a.NewState {
+		s.transitions = append(s.transitions, transition{a.Recorded, a.OldState, a.NewState})
+	}
+	windowStart := a.Recorded.Add(-s.monitor.flapWindow)
+	for len(s.transitions) > 0 && !s.transitions[0].at.After(windowStart) {
+		s.transitions = s.transitions[1:]
+	}
+
+	switch {
+	case !s.flapping && len(s.transitions) > s.monitor.flapTransitions:
+		s.flapping = true
+		s.flapWorst = a.NewState
+		for _, t := range s.transitions {
+			s.flapWorst = worstState(s.flapWorst, worstState(t.from, t.to))
+		}
+
+		a.Flapping = true
+		a.WorstState = s.flapWorst
+		a.FlapTransitions = len(s.transitions)
+		a.FlapWindow = s.monitor.flapWindow
+		return true
+	case s.flapping && len(s.transitions) == 0:
+		s.flapping = false
+
+		a.StoppedFlapping = true
+		a.WorstState = worstState(s.flapWorst, a.NewState)
+		a.OldState = a.WorstState
+		return true
+	case s.flapping:
+		s.flapWorst = worstState(s.flapWorst, a.NewState)
+		return false
+	default:
+		return true
+	}
+}
+
+type transition struct {
+	at       time.Time
+	from, to state.State
+}
+
+func worstState(a, b state.State) state.State {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func (s *subprobe) newAlert(oldState state.State, r probe.Reading) *target.Alert {
 	return &target.Alert{
 		MonitorID:    s.monitor.id,
@@ -164,7 +339,7 @@ func (s *subprobe) newAlert(oldState state.State, r probe.Reading) *target.Alert
 		Response:    s.monitor.response,
 
 		OldState: oldState,
-		NewState: r.State,
+		NewState: s.state,
 
 		Recorded:     r.Recorded,
 		EnteredState: s.enteredState,
@@ -187,7 +362,7 @@ func (s *subprobe) record(r probe.Reading, isSilenced bool) error {
 			dbReading := db.Reading{
 				SubprobeID: s.id,
 				Recorded:   r.Recorded,
-				State:      r.State,
+				State:      s.state,
 			}
 			if err := tx.InsertReading(dbReading); err != nil {
 				return errors.Maskf(err, "insert reading")
@@ -201,11 +376,18 @@ func (s *subprobe) record(r probe.Reading, isSilenced bool) error {
 }
 
 func (s *subprobe) dbStatus() db.SubprobeStatus {
-	return db.SubprobeStatus{
+	status := db.SubprobeStatus{
 		SubprobeID:   s.id,
 		Recorded:     s.lastReading,
 		State:        s.state,
 		EnteredState: s.enteredState,
 		LastNormal:   s.lastNormal,
+		Flapping:     s.flapping,
+	}
+	if s.pendingReadings > 0 {
+		status.PendingState = s.pendingState
+		status.PendingReadings = int32(s.pendingReadings)
+		status.PendingSince = &s.pendingSince
 	}
+	return status
 }
@@ -0,0 +1,253 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yext/revere/probe"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+)
+
+type testReading struct {
+	after time.Duration
+	state state.State
+}
+
+// readAll feeds s readings at the given times after start, returning its state
+// after each.
+func readAll(s *subprobe, start time.Time, readings []testReading) []state.State {
+	states := make([]state.State, len(readings))
+	for i, r := range readings {
+		s.updateFor(probe.Reading{Subprobe: s.name, State: r.state, Recorded: start.Add(r.after)})
+		states[i] = s.state
+	}
+	return states
+}
+
+func TestSustainedState(t *testing.T) {
+	cases := []struct {
+		name     string
+		readings int
+		period   time.Duration
+		initial  state.State
+		input    []testReading
+		expected []state.State
+	}{
+		{
+			name:     "not sustained",
+			initial:  state.Normal,
+			input:    []testReading{{0, state.Error}, {time.Minute, state.Normal}},
+			expected: []state.State{state.Error, state.Normal},
+		},
+		{
+			name:     "readings",
+			readings: 3,
+			initial:  state.Normal,
+			input: []testReading{
+				{0, state.Error}, {time.Minute, state.Error}, {2 * time.Minute, state.Error},
+			},
+			expected: []state.State{state.Normal, state.Normal, state.Error},
+		},
+		{
+			name:     "least extreme of pending readings",
+			readings: 3,
+			initial:  state.Normal,
+			input: []testReading{
+				{0, state.Critical}, {time.Minute, state.Warning}, {2 * time.Minute, state.Error},
+			},
+			expected: []state.State{state.Normal, state.Normal, state.Warning},
+		},
+		{
+			name:     "interrupted by current state",
+			readings: 2,
+			initial:  state.Normal,
+			input: []testReading{
+				{0, state.Error}, {time.Minute, state.Normal}, {2 * time.Minute, state.Error}, {3 * time.Minute, state.Error},
+			},
+			expected: []state.State{state.Normal, state.Normal, state.Normal, state.Error},
+		},
+		{
+			name:     "recovery",
+			readings: 2,
+			initial:  state.Error,
+			input: []testReading{
+				{0, state.Normal}, {time.Minute, state.Error}, {2 * time.Minute, state.Normal}, {3 * time.Minute, state.Warning},
+			},
+			expected: []state.State{state.Error, state.Error, state.Error, state.Warning},
+		},
+		{
+			name:     "direction change restarts",
+			readings: 2,
+			initial:  state.Error,
+			input: []testReading{
+				{0, state.Critical}, {time.Minute, state.Warning}, {2 * time.Minute, state.Warning},
+			},
+			expected: []state.State{state.Error, state.Error, state.Warning},
+		},
+		{
+			name:    "period",
+			period:  5 * time.Minute,
+			initial: state.Normal,
+			input: []testReading{
+				{0, state.Error}, {4 * time.Minute, state.Error}, {5 * time.Minute, state.Error},
+			},
+			expected: []state.State{state.Normal, state.Normal, state.Error},
+		},
+		{
+			name:    "period recovery",
+			period:  5 * time.Minute,
+			initial: state.Error,
+			input: []testReading{
+				{0, state.Normal}, {3 * time.Minute, state.Normal}, {6 * time.Minute, state.Normal},
+			},
+			expected: []state.State{state.Error, state.Error, state.Normal},
+		},
+		{
+			name:     "readings before period",
+			readings: 2,
+			period:   time.Hour,
+			initial:  state.Normal,
+			input:    []testReading{{0, state.Error}, {time.Minute, state.Error}},
+			expected: []state.State{state.Normal, state.Error},
+		},
+		{
+			name:     "period before readings",
+			readings: 10,
+			period:   2 * time.Minute,
+			initial:  state.Normal,
+			input:    []testReading{{0, state.Error}, {time.Minute, state.Error}, {2 * time.Minute, state.Error}},
+			expected: []state.State{state.Normal, state.Normal, state.Error},
+		},
+	}
+
+	start := time.Now()
+	for _, c := range cases {
+		m := newTestMonitor(t)
+		m.sustainReadings, m.sustainPeriod = c.readings, c.period
+		s := newTestSubprobe(m, c.initial, start.Add(-time.Hour))
+
+		states := readAll(s, start, c.input)
+		for i := range states {
+			if states[i] != c.expected[i] {
+				t.Errorf("%s: expected states %v, got %v", c.name, c.expected, states)
+				break
+			}
+		}
+	}
+}
+
+// flap updates s for a reading in state st at the given time, returning the
+// alert it would send and whether it would be sent.
+func flap(s *subprobe, st state.State, at time.Time) (*target.Alert, bool) {
+	oldState := s.state
+	r := probe.Reading{Subprobe: s.name, State: st, Recorded: at}
+	s.updateFor(r)
+	a := s.newAlert(oldState, r)
+	return a, s.updateFlapping(a)
+}
+
+func TestFlappingNotDetected(t *testing.T) {
+	start := time.Now()
+	m := newTestMonitor(t)
+	s := newTestSubprobe(m, state.Normal, start.Add(-time.Hour))
+
+	for i, st := range []state.State{state.Error, state.Normal, state.Error, state.Normal} {
+		a, send := flap(s, st, start.Add(time.Duration(i)*time.Minute))
+		if !send || a.Flapping || s.flapping {
+			t.Errorf("Expected no flapping without a flap window, got flapping at reading %d\n", i)
+		}
+	}
+	if len(s.transitions) != 0 {
+		t.Errorf("Expected no transitions kept, got %d\n", len(s.transitions))
+	}
+}
+
+func TestFlapWindowCounting(t *testing.T) {
+	start := time.Now()
+	m := newTestMonitor(t)
+	m.flapTransitions, m.flapWindow = 2, 10*time.Minute
+
+	s := newTestSubprobe(m, state.Normal, start.Add(-time.Hour))
+	flap(s, state.Error, start)
+	flap(s, state.Normal, start.Add(9*time.Minute))
+	a, send := flap(s, state.Error, start.Add(10*time.Minute))
+	if !send || a.Flapping || s.flapping {
+		t.Errorf("Expected transitions leaving the window not to count, got flapping\n")
+	}
+	if len(s.transitions) != 2 {
+		t.Errorf("Expected 2 transitions in the window, got %d\n", len(s.transitions))
+	}
+
+	a, send = flap(s, state.Error, start.Add(11*time.Minute))
+	if !send || a.Flapping || s.flapping {
+		t.Errorf("Expected readings without a state change not to count, got flapping\n")
+	}
+
+	a, send = flap(s, state.Normal, start.Add(12*time.Minute))
+	if !send || !a.Flapping || !s.flapping {
+		t.Errorf("Expected the third transition in the window to start flapping, got send %v, flapping %v\n", send, a.Flapping)
+	}
+	if a.FlapTransitions != 3 || a.FlapWindow != m.flapWindow {
+		t.Errorf("Expected 3 transitions in %v, got %d in %v\n", m.flapWindow, a.FlapTransitions, a.FlapWindow)
+	}
+	if a.WorstState != state.Error {
+		t.Errorf("Expected worst state %v, got %v\n", state.Error, a.WorstState)
+	}
+}
+
+func TestFlappingSuppressesAlerts(t *testing.T) {
+	start := time.Now()
+	m := newTestMonitor(t)
+	m.flapTransitions, m.flapWindow = 2, 10*time.Minute
+
+	s := newTestSubprobe(m, state.Normal, start.Add(-time.Hour))
+	flap(s, state.Critical, start)
+	flap(s, state.Normal, start.Add(time.Minute))
+	if _, send := flap(s, state.Warning, start.Add(2*time.Minute)); !send || !s.flapping {
+		t.Fatalf("Expected the subprobe to start flapping\n")
+	}
+
+	for i, st := range []state.State{state.Normal, state.Error, state.Error} {
+		a, send := flap(s, st, start.Add(time.Duration(3+i)*time.Minute))
+		if send {
+			t.Errorf("Expected alerts suppressed while flapping, got %s->%s sent\n", a.OldState, a.NewState)
+		}
+	}
+	if s.flapWorst != state.Critical {
+		t.Errorf("Expected worst state %v, got %v\n", state.Critical, s.flapWorst)
+	}
+}
+
+func TestStoppedFlappingSummary(t *testing.T) {
+	start := time.Now()
+	m := newTestMonitor(t)
+	m.flapTransitions, m.flapWindow = 2, 10*time.Minute
+
+	s := newTestSubprobe(m, state.Normal, start.Add(-time.Hour))
+	flap(s, state.Critical, start)
+	flap(s, state.Normal, start.Add(time.Minute))
+	flap(s, state.Warning, start.Add(2*time.Minute))
+	flap(s, state.Normal, start.Add(3*time.Minute))
+
+	a, send := flap(s, state.Normal, start.Add(12*time.Minute))
+	if send || !s.flapping {
+		t.Errorf("Expected the subprobe to keep flapping while transitions remain in the window\n")
+	}
+
+	a, send = flap(s, state.Normal, start.Add(13*time.Minute))
+	if !send || s.flapping {
+		t.Fatalf("Expected the subprobe to stop flapping once no transitions remain in the window\n")
+	}
+	if !a.StoppedFlapping || a.Flapping {
+		t.Errorf("Expected a stopped-flapping alert, got stopped %v, flapping %v\n", a.StoppedFlapping, a.Flapping)
+	}
+	if a.WorstState != state.Critical || a.OldState != state.Critical || a.NewState != state.Normal {
+		t.Errorf("Expected worst state %v summarized as %v->%v, got worst %v, %v->%v\n",
+			state.Critical, state.Critical, state.Normal, a.WorstState, a.OldState, a.NewState)
+	}
+
+	if _, send := flap(s, state.Error, start.Add(14*time.Minute)); !send || s.flapping {
+		t.Errorf("Expected alerts to resume after flapping stops\n")
+	}
+}
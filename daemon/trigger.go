@@ -1,13 +1,16 @@
 package daemon
 
 import (
+	"fmt"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/jmoiron/sqlx/types"
 	"github.com/juju/errors"
 
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/env"
+	"github.com/yext/revere/metrics"
 	"github.com/yext/revere/state"
 	"github.com/yext/revere/target"
 )
@@ -18,6 +21,19 @@ type triggerTemplate struct {
 	triggerOnExit bool
 	period        time.Duration
 	target        target.Target
+
+	// targetConfig is kept to queue alerts to the target.
+	targetConfig types.JSONText
+
+	// delay is set for escalation steps, which only alert once a subprobe
+	// has been away from Normal for this long.
+	delay time.Duration
+
+	// groupWindow is how long alerts are held so that others for this
+	// trigger can be sent with them. digestPeriod is how often alerts no
+	// worse than Warning are sent together instead.
+	groupWindow  time.Duration
+	digestPeriod time.Duration
 }
 
 func newTriggerTemplate(dbModel *db.Trigger, env *env.Env) (*triggerTemplate, error) {
@@ -32,6 +48,11 @@ func newTriggerTemplate(dbModel *db.Trigger, env *env.Env) (*triggerTemplate, er
 		triggerOnExit: dbModel.TriggerOnExit,
 		period:        time.Duration(dbModel.PeriodMilli) * time.Millisecond,
 		target:        target,
+
+		targetConfig: dbModel.Target,
+
+		groupWindow:  time.Duration(dbModel.GroupMilli) * time.Millisecond,
+		digestPeriod: time.Duration(dbModel.DigestMilli) * time.Millisecond,
 	}, nil
 }
 
@@ -39,6 +60,10 @@ type trigger struct {
 	*triggerTemplate
 	lastAlert time.Time
 
+	// notified records whether an escalation step has alerted since the
+	// subprobe was last Normal.
+	notified bool
+
 	*env.Env
 }
 
@@ -47,6 +72,16 @@ func newTrigger(template *triggerTemplate, Env *env.Env) *trigger {
 }
 
 func (t *trigger) shouldTrigger(a *target.Alert) bool {
+	if !t.escalated(a) {
+		return false
+	}
+
+	if a.Flapping {
+		// One alert stands in for all of a flapping subprobe's
+		// state changes.
+		return a.WorstState >= t.level
+	}
+
 	if a.OldState == a.NewState {
 		if a.NewState < t.level {
 			return false
@@ -61,6 +96,35 @@ func (t *trigger) shouldTrigger(a *target.Alert) bool {
 	return a.OldState >= t.level && t.triggerOnExit
 }
 
+// holdUntil returns when an alert for a to t should be sent if it is to be held
+// for a digest or a group, and false if it should be sent right away.
+func (t *trigger) holdUntil(a *target.Alert, now time.Time) (groupKey string, sendAt time.Time, hold bool) {
+	worst := a.NewState
+	if a.OldState > worst {
+		worst = a.OldState
+	}
+	if a.Flapping && a.WorstState > worst {
+		worst = a.WorstState
+	}
+
+	switch {
+	case t.digestPeriod > 0 && worst <= state.Warning:
+		return fmt.Sprintf("digest:%d", t.id), now.Truncate(t.digestPeriod).Add(t.digestPeriod), true
+	case t.groupWindow > 0:
+		return fmt.Sprintf("group:%d", t.id), now.Add(t.groupWindow), true
+	default:
+		return "", time.Time{}, false
+	}
+}
+
+// escalated returns whether t's escalation delay, if any, has passed since the
+// subprobe a is about was last Normal, so that changes between non-Normal
+// states don't restart the clock. Escalation steps that have alerted keep
+// receiving alerts until the subprobe returns to Normal.
+func (t *trigger) escalated(a *target.Alert) bool {
+	return t.delay == 0 || t.notified || a.Recorded.Sub(a.LastNormal) >= t.delay
+}
+
 type sameTypeTriggerSet map[db.TriggerID]*trigger
 
 func newSameTypeTriggerSet() sameTypeTriggerSet {
@@ -72,37 +136,139 @@ func (s sameTypeTriggerSet) add(t *trigger) {
 }
 
 func (s sameTypeTriggerSet) alert(a *target.Alert) {
-	toAlert := make(map[db.TriggerID]target.Target)
-	var inactive []target.Target
-	var targetType target.Type
 	var Db *db.DB
 	for _, trigger := range s {
 		Db = trigger.Env.DB
-		if trigger.shouldTrigger(a) {
-			toAlert[trigger.id] = trigger.target
-			targetType = trigger.target.Type()
-		} else {
-			inactive = append(inactive, trigger.target)
-		}
+		break
 	}
 
-	if len(toAlert) == 0 {
+	p := s.plan(a, time.Now())
+	for _, h := range p.held {
+		s.enqueueGrouped(Db, a, p.targetType, h.trigger, h.groupKey, h.sendAt)
+	}
+
+	if len(p.toAlert) == 0 && !resolves(p.targetType, a) {
 		return
 	}
 
 	if log.GetLevel() >= log.DebugLevel {
 		log.WithFields(log.Fields{
+			"monitor":    a.MonitorID,
+			"subprobe":   a.SubprobeName,
+			"state":      a.NewState,
+			"recorded":   a.Recorded,
+			"targetType": p.targetType.ID(),
+			"toAlert":    len(p.toAlert),
+			"inactive":   len(p.inactive),
+		}).Debug("Queueing alerts.")
+	}
+
+	err := enqueueAlert(Db, a, p.targetType, p.targets)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"monitor":    a.MonitorID,
+			"subprobe":   a.SubprobeName,
+			"state":      a.NewState,
+			"recorded":   a.Recorded,
+			"targetType": p.targetType.ID(),
+		}).Error("Could not queue alerts. Sending them without retries.")
+
+		s.send(Db, a, p.targetType, p.toAlert, p.inactive)
+		return
+	}
+
+	s.markAlerted(p.toAlert)
+}
+
+// alertPlan is how an alert is to be sent to the triggers of a
+// sameTypeTriggerSet.
+type alertPlan struct {
+	targetType target.Type
+
+	// toAlert are the targets to send the alert to now, and inactive the
+	// others. targets describes both for the alert queue.
+	toAlert  map[db.TriggerID]target.Target
+	inactive []target.Target
+	targets  queuedTargets
+
+	// held are the triggers to send the alert to later, with others.
+	held []heldAlert
+}
+
+type heldAlert struct {
+	trigger  *trigger
+	groupKey string
+	sendAt   time.Time
+}
+
+// plan works out which of s's triggers a should be sent to, as of now.
+func (s sameTypeTriggerSet) plan(a *target.Alert, now time.Time) alertPlan {
+	p := alertPlan{toAlert: make(map[db.TriggerID]target.Target)}
+	for _, trigger := range s {
+		p.targetType = trigger.target.Type()
+		if trigger.shouldTrigger(a) {
+			if groupKey, sendAt, hold := trigger.holdUntil(a, now); hold {
+				p.held = append(p.held, heldAlert{trigger, groupKey, sendAt})
+				continue
+			}
+
+			p.toAlert[trigger.id] = trigger.target
+			p.targets.ToAlert = append(p.targets.ToAlert, queuedTarget{
+				TriggerID: trigger.id,
+				Config:    trigger.targetConfig,
+			})
+		} else {
+			p.inactive = append(p.inactive, trigger.target)
+			p.targets.Inactive = append(p.targets.Inactive, trigger.targetConfig)
+		}
+	}
+	return p
+}
+
+// resolves returns whether a must be sent to targetType even though none of
+// its triggers alert on it, for example to resolve an incident.
+func resolves(targetType target.Type, a *target.Alert) bool {
+	r, ok := targetType.(target.Resolver)
+	return ok && r.Resolves(a)
+}
+
+// enqueueGrouped queues a to be sent to trigger's target at sendAt with the
+// other alerts held under groupKey.
+func (s sameTypeTriggerSet) enqueueGrouped(
+	Db *db.DB, a *target.Alert, targetType target.Type,
+	trigger *trigger, groupKey string, sendAt time.Time) {
+	targets := queuedTargets{
+		ToAlert: []queuedTarget{{
+			TriggerID: trigger.id,
+			Config:    trigger.targetConfig,
+		}},
+	}
+	toAlert := map[db.TriggerID]target.Target{trigger.id: trigger.target}
+
+	err := enqueueGroupedAlert(Db, a, targetType, targets, groupKey, sendAt)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
 			"monitor":    a.MonitorID,
 			"subprobe":   a.SubprobeName,
 			"state":      a.NewState,
 			"recorded":   a.Recorded,
 			"targetType": targetType.ID(),
-			"toAlert":    len(toAlert),
-			"inactive":   len(inactive),
-		}).Debug("Sending alerts.")
+			"group":      groupKey,
+		}).Error("Could not queue grouped alert. Sending it on its own without retries.")
+
+		s.send(Db, a, targetType, toAlert, nil)
+		return
 	}
 
+	s.markAlerted(toAlert)
+}
+
+// send sends alerts directly, for when they cannot be queued.
+func (s sameTypeTriggerSet) send(
+	Db *db.DB, a *target.Alert, targetType target.Type,
+	toAlert map[db.TriggerID]target.Target, inactive []target.Target) {
 	errors := targetType.Alert(Db, a, toAlert, inactive)
+	recordAlerts(Db, a, targetType, toAlert, errors)
 
 	for _, errAndIDs := range errors {
 		log.WithError(errAndIDs.Err).WithFields(log.Fields{
@@ -119,8 +285,77 @@ func (s sameTypeTriggerSet) alert(a *target.Alert) {
 		}
 	}
 
+	s.markAlerted(toAlert)
+}
+
+func (s sameTypeTriggerSet) markAlerted(toAlert map[db.TriggerID]target.Target) {
 	now := time.Now()
 	for id, _ := range toAlert {
 		s[id].lastAlert = now
+		s[id].notified = true
+	}
+}
+
+func (s sameTypeTriggerSet) resetEscalations() {
+	for _, t := range s {
+		t.notified = false
+	}
+}
+
+// recordAlerts adds the outcome of sending a to the targets in toAlert to the
+// alert history.
+func recordAlerts(
+	Db *db.DB, a *target.Alert, targetType target.Type,
+	toAlert map[db.TriggerID]target.Target, errs []target.ErrorAndTriggerIDs) {
+	failures := make(map[db.TriggerID]error)
+	for _, errAndIDs := range errs {
+		for _, id := range errAndIDs.IDs {
+			failures[id] = errAndIDs.Err
+		}
+	}
+
+	typeName := targetTypeName(targetType.ID())
+	now := time.Now()
+	alerts := make([]*db.Alert, 0, len(toAlert))
+	for id, t := range toAlert {
+		alert := &db.Alert{
+			MonitorID:  a.MonitorID,
+			SubprobeID: a.SubprobeID,
+			TriggerID:  id,
+			TargetType: targetType.ID(),
+			Recipients: t.Recipients(),
+			OldState:   a.OldState,
+			State:      a.NewState,
+			Recorded:   a.Recorded,
+			Sent:       now,
+			Success:    true,
+		}
+		result := "sent"
+		if err := failures[id]; err != nil {
+			alert.Success = false
+			alert.Error = err.Error()
+			result = "failed"
+		}
+		metrics.Alerts.WithLabelValues(typeName, result).Inc()
+		alerts = append(alerts, alert)
+	}
+
+	err := Db.InsertAlerts(alerts)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"monitor":  a.MonitorID,
+			"subprobe": a.SubprobeName,
+		}).Error("Could not record alert history.")
+	}
+}
+
+// targetTypeName returns the name of the target type with the given ID, as
+// shown in the web UI.
+func targetTypeName(id db.TargetType) string {
+	for _, tt := range target.AllTargets() {
+		if tt.Id() == id {
+			return tt.Name()
+		}
 	}
+	return fmt.Sprintf("%d", id)
 }
@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/env"
+	"github.com/yext/revere/probe"
+	"github.com/yext/revere/state"
+)
+
+const testPagerDutyKey = "0123456789abcdef0123456789abcdef"
+
+// newTestMonitor makes a monitor with the given triggers, applying to all of
+// its subprobes, that does not need a database until readings are recorded.
+func newTestMonitor(t *testing.T, triggers ...*db.Trigger) *monitor {
+	m := &monitor{id: 1, name: "test", Env: &env.Env{}}
+	for _, dbTrigger := range triggers {
+		template, err := newTriggerTemplate(dbTrigger, m.Env)
+		if err != nil {
+			t.Fatalf("Could not make trigger %d: %s", dbTrigger.TriggerID, err)
+		}
+		m.triggers = append(m.triggers, monitorTrigger{regexp.MustCompile(""), template})
+	}
+	return m
+}
+
+func newTestSubprobe(m *monitor, s state.State, at time.Time) *subprobe {
+	return newSubprobe("sub", db.SubprobeStatus{
+		SubprobeID:   1,
+		Recorded:     at,
+		State:        s,
+		EnteredState: at,
+		LastNormal:   at,
+	}, m)
+}
+
+// plan updates s for a reading in state st at the given time, returning the
+// alert it would send and how the alert would be sent to the triggers of the
+// given target type.
+func plan(s *subprobe, targetType db.TargetType, st state.State, at time.Time) (alertPlan, bool) {
+	oldState := s.state
+	r := probe.Reading{Subprobe: s.name, State: st, Recorded: at}
+	s.updateFor(r)
+	a := s.newAlert(oldState, r)
+
+	p := s.triggerSets[targetType].plan(a, at)
+	return p, len(p.toAlert) > 0 || resolves(p.targetType, a)
+}
+
+func TestRecoveryResolvesPagerDuty(t *testing.T) {
+	pagerDuty := db.TargetType(3)
+	m := newTestMonitor(t, &db.Trigger{
+		TriggerID:  1,
+		Level:      state.Warning,
+		TargetType: pagerDuty,
+		Target:     types.JSONText(`{"IntegrationKey": "` + testPagerDutyKey + `"}`),
+	})
+	now := time.Now()
+	s := newTestSubprobe(m, state.Normal, now)
+
+	p, send := plan(s, pagerDuty, state.Warning, now.Add(time.Minute))
+	if !send || p.toAlert[1] == nil {
+		t.Fatalf("Expected Warning to alert trigger 1, got %+v", p)
+	}
+	s.triggerSets[pagerDuty].markAlerted(p.toAlert)
+
+	p, send = plan(s, pagerDuty, state.Normal, now.Add(2*time.Minute))
+	if !send {
+		t.Fatalf("Expected recovery to be sent to resolve the incident")
+	}
+	if len(p.toAlert) != 0 || len(p.inactive) != 1 || len(p.targets.Inactive) != 1 {
+		t.Errorf("Expected recovery to be sent to 1 inactive target, got %+v", p)
+	}
+
+	_, send = plan(s, pagerDuty, state.Normal, now.Add(3*time.Minute))
+	if send {
+		t.Errorf("Expected no alert while staying Normal")
+	}
+}
+
+func TestRecoveryWithoutResolverNotSent(t *testing.T) {
+	email := db.TargetType(1)
+	m := newTestMonitor(t, &db.Trigger{
+		TriggerID:  1,
+		Level:      state.Warning,
+		TargetType: email,
+		Target:     types.JSONText(`{}`),
+	})
+	now := time.Now()
+	s := newTestSubprobe(m, state.Warning, now)
+
+	if _, send := plan(s, email, state.Normal, now.Add(time.Minute)); send {
+		t.Errorf("Expected recovery not to be sent to a trigger that doesn't alert on exit")
+	}
+}
+
+func TestEscalationDelayCountsFromLastNormal(t *testing.T) {
+	email := db.TargetType(1)
+	m := newTestMonitor(t, &db.Trigger{
+		TriggerID:  1,
+		Level:      state.Warning,
+		TargetType: email,
+		Target:     types.JSONText(`{}`),
+	})
+	m.triggers[0].delay = 10 * time.Minute
+	now := time.Now()
+	s := newTestSubprobe(m, state.Normal, now)
+
+	readings := []struct {
+		after time.Duration
+		state state.State
+		alert bool
+	}{
+		{time.Minute, state.Warning, false},
+		{5 * time.Minute, state.Error, false},
+		{8 * time.Minute, state.Warning, false},
+		// Still not Normal, so the step escalates even though the
+		// subprobe only just became Error again.
+		{11 * time.Minute, state.Error, true},
+	}
+	for _, r := range readings {
+		p, _ := plan(s, email, r.state, now.Add(r.after))
+		if alerted := p.toAlert[1] != nil; alerted != r.alert {
+			t.Errorf("%s after %s: expected escalation step to alert: %v, got %v", r.state, r.after, r.alert, alerted)
+		}
+	}
+}
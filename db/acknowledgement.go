@@ -0,0 +1,77 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+// Acknowledgement records that someone is handling a subprobe's problem.
+// While it is active, repeat alerts for the subprobe are suppressed unless its
+// state becomes worse than State.
+type Acknowledgement struct {
+	SubprobeID SubprobeID
+	MonitorID  MonitorID
+	AckedBy    string
+	Comment    string
+	State      state.State
+	Acked      time.Time
+	Expires    time.Time
+}
+
+// SaveAcknowledgement saves a, replacing any earlier acknowledgement of the
+// same subprobe.
+func (db *DB) SaveAcknowledgement(a *Acknowledgement) error {
+	q := `INSERT INTO pfx_acknowledgements (subprobeid, monitorid, ackedby, comment, state, acked, expires)
+	      VALUES (:subprobeid, :monitorid, :ackedby, :comment, :state, :acked, :expires)
+	      ON DUPLICATE KEY UPDATE
+	          ackedby = VALUES(ackedby),
+	          comment = VALUES(comment),
+	          state = VALUES(state),
+	          acked = VALUES(acked),
+	          expires = VALUES(expires)`
+	_, err := db.NamedExec(cq(db, q), a)
+	return errors.Trace(err)
+}
+
+func (db *DB) DeleteAcknowledgement(id SubprobeID) error {
+	q := `DELETE FROM pfx_acknowledgements WHERE subprobeid = ?`
+	_, err := db.Exec(cq(db, q), id)
+	return errors.Trace(err)
+}
+
+func (db *DB) LoadActiveAcknowledgementsForMonitor(id MonitorID) ([]Acknowledgement, error) {
+	var acks []Acknowledgement
+	q := `SELECT * FROM pfx_acknowledgements
+	      WHERE monitorid = ? AND UTC_TIMESTAMP() < expires`
+	if err := db.Select(&acks, cq(db, q), id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return acks, nil
+}
+
+func (db *DB) LoadActiveAcknowledgement(id SubprobeID) (*Acknowledgement, error) {
+	var ack Acknowledgement
+	q := `SELECT * FROM pfx_acknowledgements
+	      WHERE subprobeid = ? AND UTC_TIMESTAMP() < expires`
+	err := db.Get(&ack, cq(db, q), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return &ack, nil
+}
+
+func (tx *Tx) LoadActiveAcknowledgements() ([]Acknowledgement, error) {
+	var acks []Acknowledgement
+	q := `SELECT * FROM pfx_acknowledgements WHERE UTC_TIMESTAMP() < expires`
+	if err := tx.Select(&acks, cq(tx, q)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return acks, nil
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+type AlertID int64
+
+// Alert records an attempt to send an alert to one trigger's target.
+type Alert struct {
+	AlertID    AlertID
+	MonitorID  MonitorID
+	SubprobeID SubprobeID
+	TriggerID  TriggerID
+	TargetType TargetType
+	Recipients string
+	OldState   state.State
+	State      state.State
+	Recorded   time.Time
+	Sent       time.Time
+	Success    bool
+	Error      string
+}
+
+type MonitorAlert struct {
+	MonitorName  string
+	SubprobeName string
+	*Alert
+}
+
+// AlertFilter narrows down the alerts loaded by LoadAlerts. Zero fields don't
+// filter.
+type AlertFilter struct {
+	MonitorID  MonitorID
+	SubprobeID SubprobeID
+	LabelID    LabelID
+	TargetType TargetType
+	From       time.Time
+	To         time.Time
+
+	// Limit is the maximum number of alerts to load, most recent first.
+	Limit int
+}
+
+func (db *DB) InsertAlerts(alerts []*Alert) error {
+	return db.Tx(func(tx *Tx) error {
+		q := `INSERT INTO pfx_alerts
+		      (monitorid, subprobeid, triggerid, targettype, recipients, oldstate, state, recorded, sent, success, error)
+		      VALUES (:monitorid, :subprobeid, :triggerid, :targettype, :recipients, :oldstate, :state, :recorded, :sent, :success, :error)`
+		for _, a := range alerts {
+			_, err := tx.NamedExec(cq(tx, q), a)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (db *DB) LoadAlerts(f AlertFilter) ([]*MonitorAlert, error) {
+	var (
+		joins  []string
+		wheres []string
+		args   []interface{}
+	)
+	if f.MonitorID != 0 {
+		wheres = append(wheres, "a.monitorid = ?")
+		args = append(args, f.MonitorID)
+	}
+	if f.SubprobeID != 0 {
+		wheres = append(wheres, "a.subprobeid = ?")
+		args = append(args, f.SubprobeID)
+	}
+	if f.LabelID != 0 {
+		joins = append(joins, "JOIN pfx_labels_monitors lm ON lm.monitorid = a.monitorid")
+		wheres = append(wheres, "lm.labelid = ?")
+		args = append(args, f.LabelID)
+	}
+	if f.TargetType != 0 {
+		wheres = append(wheres, "a.targettype = ?")
+		args = append(args, f.TargetType)
+	}
+	if !f.From.IsZero() {
+		wheres = append(wheres, "a.sent >= ?")
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		wheres = append(wheres, "a.sent < ?")
+		args = append(args, f.To)
+	}
+
+	q := `SELECT a.*, m.name AS monitorname, s.name AS subprobename
+	      FROM pfx_alerts a
+	      JOIN pfx_monitors m ON m.monitorid = a.monitorid
+	      JOIN pfx_subprobes s ON s.subprobeid = a.subprobeid `
+	q += strings.Join(joins, " ")
+	if len(wheres) > 0 {
+		q += " WHERE " + strings.Join(wheres, " AND ")
+	}
+	q += " ORDER BY a.sent DESC, a.alertid DESC"
+	if f.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+
+	var alerts []*MonitorAlert
+	err := db.Select(&alerts, cq(db, q), args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return alerts, nil
+}
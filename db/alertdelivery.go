@@ -0,0 +1,211 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+)
+
+type AlertDeliveryID int64
+
+// AlertDelivery is an alert waiting to be sent to some of a monitor's targets
+// of one type. Deliveries are removed once sent. Deliveries that have
+// exhausted their retries are kept, marked dead, until they are retried or
+// discarded by hand.
+type AlertDelivery struct {
+	AlertDeliveryID AlertDeliveryID
+	MonitorID       MonitorID
+	SubprobeID      SubprobeID
+	TargetType      TargetType
+	Alert           types.JSONText
+	Targets         types.JSONText
+	Attempts        int32
+	Created         time.Time
+	NextAttempt     time.Time
+	LastError       string
+	Dead            bool
+
+	// Alerts held to be sent together share a GroupKey. While GroupOpen is
+	// set, further alerts for the group are added to GroupedAlerts, a JSON
+	// array of the alerts after the first.
+	GroupKey      string
+	GroupOpen     bool
+	GroupedAlerts types.JSONText
+}
+
+type MonitorAlertDelivery struct {
+	MonitorName  string
+	SubprobeName string
+	*AlertDelivery
+}
+
+func (db *DB) EnqueueAlertDelivery(d *AlertDelivery) (AlertDeliveryID, error) {
+	q := `INSERT INTO pfx_alert_deliveries
+	      (monitorid, subprobeid, targettype, alert, targets, attempts, created, nextattempt, lasterror, dead,
+	       groupkey, groupopen, groupedalerts)
+	      VALUES (:monitorid, :subprobeid, :targettype, :alert, :targets, :attempts, :created, :nextattempt, :lasterror, :dead,
+	       :groupkey, :groupopen, :groupedalerts)`
+	if len(d.GroupedAlerts) == 0 {
+		d.GroupedAlerts = types.JSONText("[]")
+	}
+	result, err := db.NamedExec(cq(db, q), d)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return AlertDeliveryID(id), nil
+}
+
+// AddToAlertDeliveryGroup adds alert to the open delivery of d's group if
+// there is one, and otherwise queues d as the group's open delivery.
+func (db *DB) AddToAlertDeliveryGroup(d *AlertDelivery, alert types.JSONText) (AlertDeliveryID, error) {
+	var id AlertDeliveryID
+	err := db.Tx(func(tx *Tx) error {
+		var open []*AlertDelivery
+		q := `SELECT * FROM pfx_alert_deliveries
+		      WHERE groupkey = ? AND groupopen = TRUE
+		      LIMIT 1
+		      FOR UPDATE`
+		err := tx.Select(&open, cq(tx, q), d.GroupKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		if len(open) == 0 {
+			q = `INSERT INTO pfx_alert_deliveries
+			     (monitorid, subprobeid, targettype, alert, targets, attempts, created, nextattempt, lasterror, dead,
+			      groupkey, groupopen, groupedalerts)
+			     VALUES (:monitorid, :subprobeid, :targettype, :alert, :targets, :attempts, :created, :nextattempt, :lasterror, :dead,
+			      :groupkey, TRUE, '[]')`
+			result, err := tx.NamedExec(cq(tx, q), d)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			lastID, err := result.LastInsertId()
+			id = AlertDeliveryID(lastID)
+			return errors.Trace(err)
+		}
+
+		id = open[0].AlertDeliveryID
+		var alerts []json.RawMessage
+		err = open[0].GroupedAlerts.Unmarshal(&alerts)
+		if err != nil {
+			return errors.Maskf(err, "deserialize grouped alerts")
+		}
+		grouped, err := json.Marshal(append(alerts, json.RawMessage(alert)))
+		if err != nil {
+			return errors.Maskf(err, "serialize grouped alerts")
+		}
+
+		q = `UPDATE pfx_alert_deliveries SET groupedalerts = ? WHERE alertdeliveryid = ?`
+		_, err = tx.Exec(cq(tx, q), string(grouped), id)
+		return errors.Trace(err)
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return id, nil
+}
+
+// ClaimDueAlertDeliveries loads up to limit live deliveries whose next attempt
+// is due, and pushes their next attempt back by lease so that they are not
+// claimed again while being sent. Claimed deliveries are closed to further
+// grouped alerts.
+func (db *DB) ClaimDueAlertDeliveries(now time.Time, limit int, lease time.Duration) ([]*AlertDelivery, error) {
+	var deliveries []*AlertDelivery
+	err := db.Tx(func(tx *Tx) error {
+		q := `SELECT * FROM pfx_alert_deliveries
+		      WHERE dead = FALSE AND nextattempt <= ?
+		      ORDER BY nextattempt
+		      LIMIT ?
+		      FOR UPDATE`
+		err := tx.Select(&deliveries, cq(tx, q), now, limit)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		q = `UPDATE pfx_alert_deliveries SET nextattempt = ?, groupopen = FALSE WHERE alertdeliveryid = ?`
+		for _, d := range deliveries {
+			d.NextAttempt = now.Add(lease)
+			d.GroupOpen = false
+			_, err = tx.Exec(cq(tx, q), d.NextAttempt, d.AlertDeliveryID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return deliveries, nil
+}
+
+// UpdateAlertDelivery saves the outcome of a failed attempt to send d.
+func (db *DB) UpdateAlertDelivery(d *AlertDelivery) error {
+	q := `UPDATE pfx_alert_deliveries
+	      SET targets=:targets,
+	          attempts=:attempts,
+	          nextattempt=:nextattempt,
+	          lasterror=:lasterror,
+	          dead=:dead
+	      WHERE alertdeliveryid=:alertdeliveryid`
+	_, err := db.NamedExec(cq(db, q), d)
+	return errors.Trace(err)
+}
+
+// ExtendAlertDeliveryLeases keeps the given claimed deliveries hidden from
+// other pollers until until, while they are still being sent.
+func (db *DB) ExtendAlertDeliveryLeases(ids []AlertDeliveryID, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q, args, err := sqlx.In(`UPDATE pfx_alert_deliveries
+	                         SET nextattempt = ?
+	                         WHERE dead = FALSE AND alertdeliveryid IN (?)`, until, ids)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = db.Exec(cq(db, db.Rebind(q)), args...)
+	return errors.Trace(err)
+}
+
+// RequeueAlertDelivery revives a dead delivery, giving it a fresh set of
+// retries starting at now.
+func (db *DB) RequeueAlertDelivery(id AlertDeliveryID, now time.Time) error {
+	q := `UPDATE pfx_alert_deliveries
+	      SET dead = FALSE, attempts = 0, nextattempt = ?
+	      WHERE alertdeliveryid = ?`
+	_, err := db.Exec(cq(db, q), now, id)
+	return errors.Trace(err)
+}
+
+func (db *DB) DeleteAlertDelivery(id AlertDeliveryID) error {
+	q := `DELETE FROM pfx_alert_deliveries WHERE alertdeliveryid = ?`
+	_, err := db.Exec(cq(db, q), id)
+	return errors.Trace(err)
+}
+
+// LoadAlertDeliveries loads all deliveries that have not yet been sent, dead
+// ones first, then oldest first.
+func (db *DB) LoadAlertDeliveries() ([]*MonitorAlertDelivery, error) {
+	var deliveries []*MonitorAlertDelivery
+	q := `SELECT d.*, m.name AS monitorname, s.name AS subprobename
+	      FROM pfx_alert_deliveries d
+	      JOIN pfx_monitors m USING (monitorid)
+	      JOIN pfx_subprobes s USING (subprobeid)
+	      ORDER BY d.dead DESC, d.created, d.alertdeliveryid`
+	err := db.Select(&deliveries, cq(db, q))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return deliveries, nil
+}
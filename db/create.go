@@ -51,6 +51,10 @@ var createTables = []struct {
 			"changed DATETIME NOT NULL",
 			"version INTEGER NOT NULL DEFAULT 0",
 			"archived DATETIME DEFAULT NULL",
+			"sustainreadings INTEGER NOT NULL DEFAULT 0",
+			"sustainmilli BIGINT NOT NULL DEFAULT 0",
+			"flaptransitions INTEGER NOT NULL DEFAULT 0",
+			"flapwindowmilli BIGINT NOT NULL DEFAULT 0",
 			"KEY idx_changed (changed)",
 		},
 	},
@@ -74,6 +78,10 @@ var createTables = []struct {
 			"silenced BOOLEAN NOT NULL DEFAULT FALSE",
 			"enteredstate DATETIME NOT NULL",
 			"lastnormal DATETIME NOT NULL",
+			"pendingstate TINYINT NOT NULL DEFAULT 0",
+			"pendingreadings INTEGER NOT NULL DEFAULT 0",
+			"pendingsince DATETIME DEFAULT NULL",
+			"flapping BOOLEAN NOT NULL DEFAULT FALSE",
 			"KEY idx_state_silenced_enteredstate_recorded (state, silenced, enteredstate, recorded)",
 			"CONSTRAINT nodbpfx_subprobe_statuses_fk_subprobeid FOREIGN KEY (subprobeid) REFERENCES pfx_subprobes (subprobeid) ON DELETE CASCADE",
 		},
@@ -89,6 +97,16 @@ var createTables = []struct {
 			"CONSTRAINT nodbpfx_readings_fk_subprobeid FOREIGN KEY (subprobeid) REFERENCES pfx_subprobes (subprobeid) ON DELETE CASCADE",
 		},
 	},
+	{
+		name: "heartbeats",
+		rowsAndKeys: []string{
+			"monitorid INTEGER UNSIGNED NOT NULL",
+			"subprobe VARCHAR(150) NOT NULL",
+			"recorded DATETIME NOT NULL",
+			"PRIMARY KEY (monitorid, subprobe)",
+			"CONSTRAINT nodbpfx_heartbeats_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
+		},
+	},
 	{
 		name: "triggers",
 		rowsAndKeys: []string{
@@ -98,6 +116,8 @@ var createTables = []struct {
 			"periodmilli INTEGER NOT NULL DEFAULT 0",
 			"targettype SMALLINT NOT NULL",
 			"target TEXT NOT NULL",
+			"groupmilli INTEGER NOT NULL DEFAULT 0",
+			"digestmilli INTEGER NOT NULL DEFAULT 0",
 		},
 	},
 	{
@@ -143,6 +163,48 @@ var createTables = []struct {
 			"CONSTRAINT nodbpfx_labels_monitors_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
 		},
 	},
+	{
+		name: "escalation_policies",
+		rowsAndKeys: []string{
+			"escalationpolicyid INTEGER UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"name VARCHAR(30) NOT NULL",
+			"description TEXT NOT NULL",
+		},
+	},
+	{
+		name: "escalation_steps",
+		rowsAndKeys: []string{
+			"escalationpolicyid INTEGER UNSIGNED NOT NULL",
+			"triggerid INTEGER UNSIGNED NOT NULL",
+			"delaymilli BIGINT NOT NULL DEFAULT 0",
+			"PRIMARY KEY (escalationpolicyid, triggerid)",
+			"UNIQUE KEY idx_triggerid (triggerid)",
+			"CONSTRAINT nodbpfx_escalation_steps_fk_escalationpolicyid FOREIGN KEY (escalationpolicyid) REFERENCES pfx_escalation_policies (escalationpolicyid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_escalation_steps_fk_triggerid FOREIGN KEY (triggerid) REFERENCES pfx_triggers (triggerid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "escalation_policies_monitors",
+		rowsAndKeys: []string{
+			"escalationpolicyid INTEGER UNSIGNED NOT NULL",
+			"monitorid INTEGER UNSIGNED NOT NULL",
+			"PRIMARY KEY (escalationpolicyid, monitorid)",
+			"KEY idx_monitorid (monitorid)",
+			"CONSTRAINT nodbpfx_escalation_policies_monitors_fk_escalationpolicyid FOREIGN KEY (escalationpolicyid) REFERENCES pfx_escalation_policies (escalationpolicyid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_escalation_policies_monitors_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "escalation_policies_labels",
+		rowsAndKeys: []string{
+			"escalationpolicyid INTEGER UNSIGNED NOT NULL",
+			"labelid INTEGER UNSIGNED NOT NULL",
+			"PRIMARY KEY (escalationpolicyid, labelid)",
+			"KEY idx_labelid (labelid)",
+			"CONSTRAINT nodbpfx_escalation_policies_labels_fk_escalationpolicyid FOREIGN KEY (escalationpolicyid) REFERENCES pfx_escalation_policies (escalationpolicyid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_escalation_policies_labels_fk_labelid FOREIGN KEY (labelid) REFERENCES pfx_labels (labelid) ON DELETE CASCADE",
+		},
+	},
 	{
 		name: "silences",
 		rowsAndKeys: []string{
@@ -155,6 +217,56 @@ var createTables = []struct {
 			"CONSTRAINT nodbpfx_silences_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
 		},
 	},
+	{
+		name: "acknowledgements",
+		rowsAndKeys: []string{
+			"subprobeid INTEGER UNSIGNED PRIMARY KEY",
+			"monitorid INTEGER UNSIGNED NOT NULL",
+			"ackedby VARCHAR(100) NOT NULL",
+			"comment TEXT NOT NULL",
+			"state TINYINT NOT NULL",
+			"acked DATETIME NOT NULL",
+			"expires DATETIME NOT NULL",
+			"KEY idx_monitorid_expires (monitorid, expires)",
+			"CONSTRAINT nodbpfx_acknowledgements_fk_subprobeid FOREIGN KEY (subprobeid) REFERENCES pfx_subprobes (subprobeid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_acknowledgements_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "schedules",
+		rowsAndKeys: []string{
+			"scheduleid INTEGER UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"name VARCHAR(30) NOT NULL",
+			"description TEXT NOT NULL",
+		},
+	},
+	{
+		name: "schedule_layers",
+		rowsAndKeys: []string{
+			"schedulelayerid INTEGER UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"scheduleid INTEGER UNSIGNED NOT NULL",
+			"position INTEGER UNSIGNED NOT NULL",
+			"start DATETIME NOT NULL",
+			"shiftmilli BIGINT NOT NULL",
+			"members TEXT NOT NULL",
+			"KEY idx_scheduleid_position (scheduleid, position)",
+			"CONSTRAINT nodbpfx_schedule_layers_fk_scheduleid FOREIGN KEY (scheduleid) REFERENCES pfx_schedules (scheduleid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "schedule_overrides",
+		rowsAndKeys: []string{
+			"scheduleoverrideid INTEGER UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"scheduleid INTEGER UNSIGNED NOT NULL",
+			"name VARCHAR(100) NOT NULL",
+			"email VARCHAR(255) NOT NULL",
+			"slackhandle VARCHAR(100) NOT NULL",
+			"start DATETIME NOT NULL",
+			"end DATETIME NOT NULL",
+			"KEY idx_scheduleid_end_start (scheduleid, end, start)",
+			"CONSTRAINT nodbpfx_schedule_overrides_fk_scheduleid FOREIGN KEY (scheduleid) REFERENCES pfx_schedules (scheduleid) ON DELETE CASCADE",
+		},
+	},
 	{
 		name: "resources",
 		rowsAndKeys: []string{
@@ -171,6 +283,74 @@ var createTables = []struct {
 			"setting TEXT NOT NULL",
 		},
 	},
+	{
+		name: "alert_deliveries",
+		rowsAndKeys: []string{
+			"alertdeliveryid BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"monitorid INTEGER UNSIGNED NOT NULL",
+			"subprobeid INTEGER UNSIGNED NOT NULL",
+			"targettype SMALLINT NOT NULL",
+			"alert TEXT NOT NULL",
+			"targets TEXT NOT NULL",
+			"attempts INTEGER NOT NULL DEFAULT 0",
+			"created DATETIME NOT NULL",
+			"nextattempt DATETIME NOT NULL",
+			"lasterror TEXT NOT NULL",
+			"dead BOOLEAN NOT NULL DEFAULT FALSE",
+			"groupkey VARCHAR(50) NOT NULL DEFAULT ''",
+			"groupopen BOOLEAN NOT NULL DEFAULT FALSE",
+			"groupedalerts TEXT NOT NULL",
+			"KEY idx_dead_nextattempt (dead, nextattempt)",
+			"KEY idx_groupkey_groupopen (groupkey, groupopen)",
+			"CONSTRAINT nodbpfx_alert_deliveries_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_alert_deliveries_fk_subprobeid FOREIGN KEY (subprobeid) REFERENCES pfx_subprobes (subprobeid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "alerts",
+		rowsAndKeys: []string{
+			"alertid BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"monitorid INTEGER UNSIGNED NOT NULL",
+			"subprobeid INTEGER UNSIGNED NOT NULL",
+			"triggerid INTEGER UNSIGNED NOT NULL",
+			"targettype SMALLINT NOT NULL",
+			"recipients TEXT NOT NULL",
+			"oldstate TINYINT NOT NULL",
+			"state TINYINT NOT NULL",
+			"recorded DATETIME NOT NULL",
+			"sent DATETIME NOT NULL",
+			"success BOOLEAN NOT NULL",
+			"error TEXT NOT NULL",
+			"KEY idx_sent (sent)",
+			"KEY idx_subprobeid_sent (subprobeid, sent)",
+			"KEY idx_monitorid_sent (monitorid, sent)",
+			"CONSTRAINT nodbpfx_alerts_fk_monitorid FOREIGN KEY (monitorid) REFERENCES pfx_monitors (monitorid) ON DELETE CASCADE",
+			"CONSTRAINT nodbpfx_alerts_fk_subprobeid FOREIGN KEY (subprobeid) REFERENCES pfx_subprobes (subprobeid) ON DELETE CASCADE",
+		},
+	},
+	{
+		name: "users",
+		rowsAndKeys: []string{
+			"userid INTEGER UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"username VARCHAR(100) NOT NULL",
+			"passwordhash VARCHAR(60) NOT NULL",
+			"role VARCHAR(10) NOT NULL",
+			"UNIQUE KEY idx_username (username)",
+		},
+	},
+	{
+		name: "history",
+		rowsAndKeys: []string{
+			"historyid BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+			"componenttype VARCHAR(20) NOT NULL",
+			"componentid INTEGER UNSIGNED NOT NULL",
+			"version INTEGER NOT NULL",
+			"author VARCHAR(100) NOT NULL",
+			"recorded DATETIME NOT NULL",
+			"snapshot MEDIUMTEXT NOT NULL",
+			"UNIQUE KEY idx_componenttype_componentid_version (componenttype, componentid, version)",
+		},
+	},
 	{
 		name: "schema_history",
 		rowsAndKeys: []string{
@@ -6,11 +6,14 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/juju/errors"
+
+	"github.com/yext/revere/metrics"
 )
 
 type DB struct {
@@ -58,6 +61,7 @@ func (db *DB) Beginx() (*Tx, error) {
 }
 
 func (db *DB) Tx(f func(*Tx) error) (err error) {
+	start := time.Now()
 	tx, err := db.Beginx()
 	if err != nil {
 		return errors.Trace(err)
@@ -73,6 +77,7 @@ func (db *DB) Tx(f func(*Tx) error) (err error) {
 				log.WithError(err).Warn("Tx rollback failed.")
 			}
 		}
+		metrics.DBTxDuration.Observe(time.Since(start).Seconds())
 	}()
 
 	return errors.Trace(f(tx))
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/juju/errors"
+)
+
+type EscalationPolicyID int32
+
+// EscalationPolicy is a chain of triggers that are notified one after another
+// while a subprobe stays away from Normal.
+type EscalationPolicy struct {
+	EscalationPolicyID EscalationPolicyID
+	Name               string
+	Description        string
+}
+
+// EscalationStep is a trigger in an escalation policy. It only alerts once
+// DelayMilli has passed since the subprobe's last Normal reading, so the delay
+// starts over every time the subprobe recovers.
+type EscalationStep struct {
+	EscalationPolicyID EscalationPolicyID
+	DelayMilli         int64
+	*Trigger
+}
+
+// EscalationStepWithSubprobes is an escalation step applying to the subprobes
+// of a monitor that match Subprobes.
+type EscalationStepWithSubprobes struct {
+	EscalationStep
+	Subprobes string
+}
+
+func (db *DB) LoadEscalationPolicy(id EscalationPolicyID) (*EscalationPolicy, error) {
+	return loadEscalationPolicy(db, id)
+}
+
+func (tx *Tx) LoadEscalationPolicy(id EscalationPolicyID) (*EscalationPolicy, error) {
+	return loadEscalationPolicy(tx, id)
+}
+
+func loadEscalationPolicy(dt dbOrTx, id EscalationPolicyID) (*EscalationPolicy, error) {
+	dt = unsafe(dt)
+
+	var p EscalationPolicy
+	q := `SELECT * FROM pfx_escalation_policies WHERE escalationpolicyid = ?`
+	err := dt.Get(&p, cq(dt, q), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return &p, nil
+}
+
+func (tx *Tx) LoadEscalationPolicies() ([]*EscalationPolicy, error) {
+	var policies []*EscalationPolicy
+	q := `SELECT * FROM pfx_escalation_policies ORDER BY name`
+	if err := tx.Select(&policies, cq(tx, q)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return policies, nil
+}
+
+func (tx *Tx) LoadStepsForEscalationPolicy(id EscalationPolicyID) ([]EscalationStep, error) {
+	dt := unsafe(tx)
+
+	var steps []EscalationStep
+	q := `SELECT *
+	      FROM pfx_escalation_steps
+	      JOIN pfx_triggers USING (triggerid)
+	      WHERE pfx_escalation_steps.escalationpolicyid = ?
+	      ORDER BY delaymilli, triggerid`
+	err := dt.Select(&steps, cq(dt, q), id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return steps, nil
+}
+
+func (tx *Tx) LoadMonitorIDsForEscalationPolicy(id EscalationPolicyID) ([]MonitorID, error) {
+	var ids []MonitorID
+	q := `SELECT monitorid FROM pfx_escalation_policies_monitors WHERE escalationpolicyid = ?`
+	if err := tx.Select(&ids, cq(tx, q), id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ids, nil
+}
+
+func (tx *Tx) LoadLabelIDsForEscalationPolicy(id EscalationPolicyID) ([]LabelID, error) {
+	var ids []LabelID
+	q := `SELECT labelid FROM pfx_escalation_policies_labels WHERE escalationpolicyid = ?`
+	if err := tx.Select(&ids, cq(tx, q), id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ids, nil
+}
+
+// LoadEscalationStepsForMonitor loads the steps of the escalation policies
+// that apply to a monitor, either directly or through its labels.
+func (tx *Tx) LoadEscalationStepsForMonitor(id MonitorID) ([]EscalationStepWithSubprobes, error) {
+	var results []EscalationStepWithSubprobes
+	q := `SELECT s.escalationpolicyid, s.delaymilli, t.*, '' AS subprobes
+	      FROM pfx_escalation_policies_monitors pm
+	      JOIN pfx_escalation_steps s USING (escalationpolicyid)
+	      JOIN pfx_triggers t USING (triggerid)
+	      WHERE pm.monitorid = ?
+	      UNION ALL
+	      SELECT s.escalationpolicyid, s.delaymilli, t.*, lm.subprobes
+	      FROM pfx_escalation_policies_labels pl
+	      JOIN pfx_labels_monitors lm USING (labelid)
+	      JOIN pfx_escalation_steps s USING (escalationpolicyid)
+	      JOIN pfx_triggers t USING (triggerid)
+	      WHERE lm.monitorid = ?`
+	err := tx.Select(&results, cq(tx, q), id, id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+func (tx *Tx) CreateEscalationPolicy(p *EscalationPolicy) (EscalationPolicyID, error) {
+	q := `INSERT INTO pfx_escalation_policies (name, description)
+	      VALUES (:name, :description)`
+	result, err := tx.NamedExec(cq(tx, q), p)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return EscalationPolicyID(id), nil
+}
+
+func (tx *Tx) UpdateEscalationPolicy(p *EscalationPolicy) error {
+	q := `UPDATE pfx_escalation_policies
+	      SET name=:name, description=:description
+	      WHERE escalationpolicyid=:escalationpolicyid`
+	_, err := tx.NamedExec(cq(tx, q), p)
+	return errors.Trace(err)
+}
+
+func (tx *Tx) CreateEscalationStep(s EscalationStep) (TriggerID, error) {
+	var err error
+	s.TriggerID, err = tx.createTrigger(s.Trigger)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	q := `INSERT INTO pfx_escalation_steps (escalationpolicyid, triggerid, delaymilli)
+	      VALUES (:escalationpolicyid, :triggerid, :delaymilli)`
+	_, err = tx.NamedExec(cq(tx, q), s)
+	return s.TriggerID, errors.Trace(err)
+}
+
+func (tx *Tx) UpdateEscalationStep(s EscalationStep) error {
+	err := tx.updateTrigger(s.Trigger)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	q := `UPDATE pfx_escalation_steps
+	      SET delaymilli=:delaymilli
+	      WHERE triggerid=:triggerid`
+	_, err = tx.NamedExec(cq(tx, q), s)
+	return errors.Trace(err)
+}
+
+func (tx *Tx) DeleteEscalationStep(triggerID TriggerID) error {
+	return tx.deleteTrigger(triggerID)
+}
+
+// SetEscalationPolicyMonitors replaces the monitors a policy applies to
+// directly.
+func (tx *Tx) SetEscalationPolicyMonitors(id EscalationPolicyID, monitorIDs []MonitorID) error {
+	q := `DELETE FROM pfx_escalation_policies_monitors WHERE escalationpolicyid = ?`
+	if _, err := tx.Exec(cq(tx, q), id); err != nil {
+		return errors.Trace(err)
+	}
+
+	q = `INSERT INTO pfx_escalation_policies_monitors (escalationpolicyid, monitorid)
+	     VALUES (?, ?)`
+	for _, monitorID := range monitorIDs {
+		if _, err := tx.Exec(cq(tx, q), id, monitorID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// SetEscalationPolicyLabels replaces the labels a policy applies to.
+func (tx *Tx) SetEscalationPolicyLabels(id EscalationPolicyID, labelIDs []LabelID) error {
+	q := `DELETE FROM pfx_escalation_policies_labels WHERE escalationpolicyid = ?`
+	if _, err := tx.Exec(cq(tx, q), id); err != nil {
+		return errors.Trace(err)
+	}
+
+	q = `INSERT INTO pfx_escalation_policies_labels (escalationpolicyid, labelid)
+	     VALUES (?, ?)`
+	for _, labelID := range labelIDs {
+		if _, err := tx.Exec(cq(tx, q), id, labelID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// TouchMonitorsForEscalationPolicy bumps the version of every monitor a policy
+// applies to, so that running daemons reload them with the policy's current
+// steps.
+func (tx *Tx) TouchMonitorsForEscalationPolicy(id EscalationPolicyID) error {
+	var monitorIDs []MonitorID
+	q := `SELECT monitorid FROM pfx_escalation_policies_monitors WHERE escalationpolicyid = ?
+	      UNION
+	      SELECT lm.monitorid
+	      FROM pfx_escalation_policies_labels pl
+	      JOIN pfx_labels_monitors lm USING (labelid)
+	      WHERE pl.escalationpolicyid = ?`
+	if err := tx.Select(&monitorIDs, cq(tx, q), id, id); err != nil {
+		return errors.Trace(err)
+	}
+	if len(monitorIDs) == 0 {
+		return nil
+	}
+
+	q, args, err := sqlx.In(`UPDATE pfx_monitors
+	                         SET changed=NOW(), version=version+1
+	                         WHERE monitorid IN (?)`, monitorIDs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = tx.Exec(cq(tx, tx.Rebind(q)), args...)
+	return errors.Trace(err)
+}
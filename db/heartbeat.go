@@ -0,0 +1,46 @@
+package db
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Heartbeat is the most recent time a subprobe of a heartbeat monitor checked
+// in.
+type Heartbeat struct {
+	MonitorID MonitorID
+	Subprobe  string
+	Recorded  time.Time
+}
+
+func (db *DB) RecordHeartbeat(h Heartbeat) error {
+	q := `INSERT INTO pfx_heartbeats (monitorid, subprobe, recorded)
+	      VALUES (:monitorid, :subprobe, :recorded)
+	      ON DUPLICATE KEY UPDATE recorded = GREATEST(recorded, VALUES(recorded))`
+	_, err := db.NamedExec(cq(db, q), h)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func (db *DB) LoadHeartbeatsForMonitor(id MonitorID) ([]Heartbeat, error) {
+	return loadHeartbeatsForMonitor(db, id)
+}
+
+func (tx *Tx) LoadHeartbeatsForMonitor(id MonitorID) ([]Heartbeat, error) {
+	return loadHeartbeatsForMonitor(tx, id)
+}
+
+func loadHeartbeatsForMonitor(dt dbOrTx, id MonitorID) ([]Heartbeat, error) {
+	var heartbeats []Heartbeat
+	q := `SELECT * FROM pfx_heartbeats WHERE monitorid = ?`
+	err := dt.Select(&heartbeats, cq(dt, q), id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return heartbeats, nil
+}
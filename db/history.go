@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+type HistoryID int64
+
+// History is one saved version of a monitor, label, silence or setting.
+// ComponentType is the component's name, such as "Monitor", and Version counts
+// the versions of each component from 1. Snapshot is the component as JSON.
+type History struct {
+	HistoryID     HistoryID
+	ComponentType string
+	ComponentID   int64
+	Version       int32
+	Author        string
+	Recorded      time.Time
+	Snapshot      string
+}
+
+// CreateHistory records h as the next version of its component.
+func (tx *Tx) CreateHistory(h *History) (HistoryID, error) {
+	q := `INSERT INTO pfx_history (componenttype, componentid, version, author, recorded, snapshot)
+	      SELECT :componenttype, :componentid, COALESCE(MAX(version), 0) + 1, :author, :recorded, :snapshot
+	      FROM pfx_history
+	      WHERE componenttype = :componenttype AND componentid = :componentid`
+	result, err := tx.NamedExec(cq(tx, q), h)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return HistoryID(id), nil
+}
+
+// LoadHistory loads every version of a component, newest first.
+func (tx *Tx) LoadHistory(componentType string, id int64) ([]*History, error) {
+	var history []*History
+	q := `SELECT * FROM pfx_history
+	      WHERE componenttype = ? AND componentid = ?
+	      ORDER BY version DESC`
+	if err := tx.Select(&history, cq(tx, q), componentType, id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return history, nil
+}
+
+// LoadHistoryVersion loads one version of a component, returning nil if there
+// is no such version.
+func (tx *Tx) LoadHistoryVersion(componentType string, id int64, version int32) (*History, error) {
+	var h History
+	q := `SELECT * FROM pfx_history
+	      WHERE componenttype = ? AND componentid = ? AND version = ?`
+	err := tx.Get(&h, cq(tx, q), componentType, id, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return &h, nil
+}
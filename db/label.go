@@ -207,3 +207,29 @@ func (tx *Tx) CreateLabelTrigger(lt LabelTrigger) (TriggerID, error) {
 func (tx *Tx) UpdateLabelTrigger(lt LabelTrigger) error {
 	return tx.updateTrigger(lt.Trigger)
 }
+
+// DeleteLabel deletes a label along with its triggers. The label is removed
+// from any monitors it was applied to.
+func (tx *Tx) DeleteLabel(id LabelID) error {
+	q := `DELETE FROM pfx_triggers
+	      WHERE triggerid IN (SELECT triggerid FROM pfx_label_triggers WHERE labelid = ?)`
+	_, err := tx.Exec(cq(tx, q), id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	q = `DELETE FROM pfx_labels WHERE labelid = ?`
+	_, err = tx.Exec(cq(tx, q), id)
+	return errors.Trace(err)
+}
+
+// BumpMonitorVersionsForLabel bumps the version of every monitor with the
+// label, so that the daemon reloads them with the label's changes.
+func (tx *Tx) BumpMonitorVersionsForLabel(id LabelID) error {
+	q := `UPDATE pfx_monitors
+	      SET changed=NOW(),
+	          version=version+1
+	      WHERE monitorid IN (SELECT monitorid FROM pfx_labels_monitors WHERE labelid = ?)`
+	_, err := tx.Exec(cq(tx, q), id)
+	return errors.Trace(err)
+}
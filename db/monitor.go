@@ -23,6 +23,18 @@ type Monitor struct {
 	Changed     time.Time
 	Version     int32
 	Archived    *time.Time
+
+	// A subprobe only changes state once readings have agreed on the new
+	// state for at least SustainReadings consecutive readings spanning at
+	// least SustainMilli.
+	SustainReadings int32
+	SustainMilli    int64
+
+	// A subprobe is flapping while it has changed state more than
+	// FlapTransitions times within the last FlapWindowMilli. Zero
+	// FlapTransitions disables flap detection.
+	FlapTransitions int32
+	FlapWindowMilli int64
 }
 
 type MonitorTrigger struct {
@@ -44,8 +56,8 @@ type MonitorVersionInfo struct {
 }
 
 func (tx *Tx) CreateMonitor(m *Monitor) (MonitorID, error) {
-	q := `INSERT INTO pfx_monitors (name, owner, description, response, probetype, probe, changed, version, archived)
-		VALUES (:name, :owner, :description, :response, :probetype, :probe, NOW(), 1, :archived)`
+	q := `INSERT INTO pfx_monitors (name, owner, description, response, probetype, probe, changed, version, archived, sustainreadings, sustainmilli, flaptransitions, flapwindowmilli)
+		VALUES (:name, :owner, :description, :response, :probetype, :probe, NOW(), 1, :archived, :sustainreadings, :sustainmilli, :flaptransitions, :flapwindowmilli)`
 	result, err := tx.NamedExec(cq(tx, q), m)
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -68,7 +80,11 @@ func (tx *Tx) UpdateMonitor(m *Monitor) error {
 	          probe=:probe,
 	          changed=NOW(),
 	          version=version+1,
-	          archived=:archived
+	          archived=:archived,
+	          sustainreadings=:sustainreadings,
+	          sustainmilli=:sustainmilli,
+	          flaptransitions=:flaptransitions,
+	          flapwindowmilli=:flapwindowmilli
 	      WHERE monitorid=:monitorid`
 	_, err := tx.NamedExec(cq(tx, q), m)
 	return errors.Trace(err)
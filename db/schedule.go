@@ -0,0 +1,180 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+)
+
+type ScheduleID int32
+type ScheduleLayerID int32
+type ScheduleOverrideID int32
+
+// Schedule is an on-call schedule. Who is on call is decided by its layers
+// and overrides.
+type Schedule struct {
+	ScheduleID  ScheduleID
+	Name        string
+	Description string
+}
+
+// ScheduleLayer is a rotation through Members, handing off every ShiftMilli
+// starting at Start. Layers with a higher Position take precedence.
+type ScheduleLayer struct {
+	ScheduleLayerID ScheduleLayerID
+	ScheduleID      ScheduleID
+	Position        int32
+	Start           time.Time
+	ShiftMilli      int64
+	Members         types.JSONText
+}
+
+// ScheduleOverride puts someone on call in place of a schedule's layers
+// between Start and End.
+type ScheduleOverride struct {
+	ScheduleOverrideID ScheduleOverrideID
+	ScheduleID         ScheduleID
+	Name               string
+	Email              string
+	SlackHandle        string
+	Start              time.Time
+	End                time.Time
+}
+
+func (db *DB) IsExistingSchedule(id ScheduleID) (exists bool) {
+	if id == 0 {
+		return false
+	}
+
+	q := `SELECT EXISTS (SELECT * FROM pfx_schedules WHERE scheduleid = ?)`
+	err := db.Get(&exists, cq(db, q), id)
+	if err != nil {
+		return false
+	}
+	return
+}
+
+func (db *DB) LoadSchedule(id ScheduleID) (*Schedule, error) {
+	return loadSchedule(db, id)
+}
+
+func (tx *Tx) LoadSchedule(id ScheduleID) (*Schedule, error) {
+	return loadSchedule(tx, id)
+}
+
+func loadSchedule(dt dbOrTx, id ScheduleID) (*Schedule, error) {
+	var s Schedule
+	q := `SELECT * FROM pfx_schedules WHERE scheduleid = ?`
+	err := dt.Get(&s, cq(dt, q), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return &s, nil
+}
+
+func (tx *Tx) LoadSchedules() ([]*Schedule, error) {
+	var schedules []*Schedule
+	q := `SELECT * FROM pfx_schedules ORDER BY name`
+	if err := tx.Select(&schedules, cq(tx, q)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return schedules, nil
+}
+
+func (db *DB) LoadScheduleLayers(id ScheduleID) ([]ScheduleLayer, error) {
+	return loadScheduleLayers(db, id)
+}
+
+func (tx *Tx) LoadScheduleLayers(id ScheduleID) ([]ScheduleLayer, error) {
+	return loadScheduleLayers(tx, id)
+}
+
+func loadScheduleLayers(dt dbOrTx, id ScheduleID) ([]ScheduleLayer, error) {
+	var layers []ScheduleLayer
+	q := `SELECT * FROM pfx_schedule_layers WHERE scheduleid = ? ORDER BY position`
+	if err := dt.Select(&layers, cq(dt, q), id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return layers, nil
+}
+
+func (db *DB) LoadScheduleOverrides(id ScheduleID) ([]ScheduleOverride, error) {
+	return loadScheduleOverrides(db, id)
+}
+
+func (tx *Tx) LoadScheduleOverrides(id ScheduleID) ([]ScheduleOverride, error) {
+	return loadScheduleOverrides(tx, id)
+}
+
+func loadScheduleOverrides(dt dbOrTx, id ScheduleID) ([]ScheduleOverride, error) {
+	var overrides []ScheduleOverride
+	q := `SELECT * FROM pfx_schedule_overrides WHERE scheduleid = ? ORDER BY start`
+	if err := dt.Select(&overrides, cq(dt, q), id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return overrides, nil
+}
+
+func (tx *Tx) CreateSchedule(s *Schedule) (ScheduleID, error) {
+	q := `INSERT INTO pfx_schedules (name, description)
+	      VALUES (:name, :description)`
+	result, err := tx.NamedExec(cq(tx, q), s)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return ScheduleID(id), nil
+}
+
+func (tx *Tx) UpdateSchedule(s *Schedule) error {
+	q := `UPDATE pfx_schedules
+	      SET name=:name, description=:description
+	      WHERE scheduleid=:scheduleid`
+	_, err := tx.NamedExec(cq(tx, q), s)
+	return errors.Trace(err)
+}
+
+// SetScheduleLayers replaces the layers of a schedule.
+func (tx *Tx) SetScheduleLayers(id ScheduleID, layers []ScheduleLayer) error {
+	q := `DELETE FROM pfx_schedule_layers WHERE scheduleid = ?`
+	if _, err := tx.Exec(cq(tx, q), id); err != nil {
+		return errors.Trace(err)
+	}
+
+	q = `INSERT INTO pfx_schedule_layers (scheduleid, position, start, shiftmilli, members)
+	     VALUES (:scheduleid, :position, :start, :shiftmilli, :members)`
+	for _, layer := range layers {
+		layer.ScheduleID = id
+		if _, err := tx.NamedExec(cq(tx, q), layer); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// SetScheduleOverrides replaces the overrides of a schedule.
+func (tx *Tx) SetScheduleOverrides(id ScheduleID, overrides []ScheduleOverride) error {
+	q := `DELETE FROM pfx_schedule_overrides WHERE scheduleid = ?`
+	if _, err := tx.Exec(cq(tx, q), id); err != nil {
+		return errors.Trace(err)
+	}
+
+	q = `INSERT INTO pfx_schedule_overrides (scheduleid, name, email, slackhandle, start, end)
+	     VALUES (:scheduleid, :name, :email, :slackhandle, :start, :end)`
+	for _, override := range overrides {
+		override.ScheduleID = id
+		if _, err := tx.NamedExec(cq(tx, q), override); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
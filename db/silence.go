@@ -57,6 +57,12 @@ func (tx *Tx) UpdateMonitorSilence(monitorSilence *MonitorSilence) error {
 	return errors.Trace(err)
 }
 
+func (tx *Tx) DeleteMonitorSilence(id SilenceID) error {
+	q := `DELETE FROM pfx_silences WHERE silenceid = ?`
+	_, err := tx.Exec(cq(tx, q), id)
+	return errors.Trace(err)
+}
+
 func (db *DB) LoadActiveSilencesForMonitor(monitorID MonitorID) ([]Silence, error) {
 	var silences []Silence
 	q := `SELECT * FROM pfx_silences
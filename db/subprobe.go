@@ -62,14 +62,14 @@ func (tx *Tx) LoadSubprobesByName(monitorID MonitorID) ([]*SubprobeWithStatusInf
 
 func (tx *Tx) LoadSubprobesBySeverity() ([]*SubprobeWithStatusInfo, error) {
 	return loadSubprobesWithStatus(tx, fmt.Sprintf(
-		`WHERE ss.state != %d
+		`WHERE (ss.state != %d OR ss.flapping)
 		ORDER BY ss.state DESC, ss.enteredstate, s.name`, state.Normal))
 }
 
 func (tx *Tx) LoadSubprobesBySeverityForLabel(labelID LabelID) ([]*SubprobeWithStatusInfo, error) {
 	return loadSubprobesWithStatus(tx, fmt.Sprintf(
 		`JOIN pfx_labels_monitors lm USING (monitorid)
-		WHERE ss.state != %d AND lm.labelid = %d
+		WHERE (ss.state != %d OR ss.flapping) AND lm.labelid = %d
 		ORDER BY ss.state DESC, ss.enteredstate, s.name`, state.Normal, labelID))
 }
 
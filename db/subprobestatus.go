@@ -15,6 +15,17 @@ type SubprobeStatus struct {
 	Silenced     bool
 	EnteredState time.Time
 	LastNormal   time.Time
+
+	// If PendingReadings is positive, the subprobe's readings have been
+	// PendingState or further from State since PendingSince, but not yet
+	// for long enough to change State.
+	PendingState    state.State
+	PendingReadings int32
+	PendingSince    *time.Time
+
+	// Flapping is whether the subprobe is changing state too often, in which
+	// case alerts for its state changes are suppressed.
+	Flapping bool
 }
 
 func (db *DB) LoadSubprobeStatusesForMonitor(id MonitorID) (map[string]SubprobeStatus, error) {
@@ -56,14 +67,22 @@ func (tx *Tx) InsertSubprobeStatus(s SubprobeStatus) error {
 	        state,
 	        silenced,
 	        enteredstate,
-	        lastnormal
+	        lastnormal,
+	        pendingstate,
+	        pendingreadings,
+	        pendingsince,
+	        flapping
 	      ) VALUES (
 	        :subprobeid,
 		:recorded,
 		:state,
 		:silenced,
 		:enteredstate,
-		:lastnormal
+		:lastnormal,
+		:pendingstate,
+		:pendingreadings,
+		:pendingsince,
+		:flapping
 	      )`
 	_, err := tx.NamedExec(cq(tx, q), s)
 	if err != nil {
@@ -79,7 +98,11 @@ func (tx *Tx) UpdateSubprobeStatus(s SubprobeStatus) error {
 	          state = :state,
 	          silenced = :silenced,
 	          enteredstate = :enteredstate,
-	          lastnormal = :lastnormal
+	          lastnormal = :lastnormal,
+	          pendingstate = :pendingstate,
+	          pendingreadings = :pendingreadings,
+	          pendingsince = :pendingsince,
+	          flapping = :flapping
 	      WHERE subprobeid = :subprobeid`
 	result, err := tx.NamedExec(cq(tx, q), s)
 	if err != nil {
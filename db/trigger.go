@@ -17,11 +17,17 @@ type Trigger struct {
 	PeriodMilli   int32
 	TargetType    TargetType
 	Target        types.JSONText
+
+	// GroupMilli is how long alerts are held so that others for the same
+	// trigger can be sent with them. DigestMilli is how often alerts no
+	// worse than Warning are sent together as a digest.
+	GroupMilli  int32
+	DigestMilli int32
 }
 
 func (tx *Tx) createTrigger(t *Trigger) (TriggerID, error) {
-	q := `INSERT INTO pfx_triggers (level, triggeronexit, periodmilli, targettype, target)
-	      VALUES (:level, :triggeronexit, :periodmilli, :targettype, :target)`
+	q := `INSERT INTO pfx_triggers (level, triggeronexit, periodmilli, targettype, target, groupmilli, digestmilli)
+	      VALUES (:level, :triggeronexit, :periodmilli, :targettype, :target, :groupmilli, :digestmilli)`
 	result, err := tx.NamedExec(cq(tx, q), t)
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -40,7 +46,9 @@ func (tx *Tx) updateTrigger(t *Trigger) error {
 	          triggeronexit=:triggeronexit,
 	          periodmilli=:periodmilli,
 	          targettype=:targettype,
-	          target=:target
+	          target=:target,
+	          groupmilli=:groupmilli,
+	          digestmilli=:digestmilli
 	      WHERE triggerid=:triggerid`
 	_, err := tx.NamedExec(cq(tx, q), t)
 	return errors.Trace(err)
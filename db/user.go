@@ -0,0 +1,51 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/juju/errors"
+)
+
+type UserID int32
+
+// User is a local web UI user. PasswordHash is a bcrypt hash, and Role is the
+// name of one of the roles in package auth.
+type User struct {
+	UserID       UserID
+	Username     string
+	PasswordHash string
+	Role         string
+}
+
+// LoadUser loads the user with the given name, returning nil if there is no
+// such user.
+func (db *DB) LoadUser(username string) (*User, error) {
+	var u User
+	q := `SELECT * FROM pfx_users WHERE username = ?`
+	err := db.Get(&u, cq(db, q), username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return &u, nil
+}
+
+// SaveUser creates u, or updates the password hash and role of the user with
+// the same name.
+func (db *DB) SaveUser(u *User) error {
+	q := `INSERT INTO pfx_users (username, passwordhash, role)
+	      VALUES (:username, :passwordhash, :role)
+	      ON DUPLICATE KEY UPDATE
+	          passwordhash = VALUES(passwordhash),
+	          role = VALUES(role)`
+	_, err := db.NamedExec(cq(db, q), u)
+	return errors.Trace(err)
+}
+
+func (db *DB) DeleteUser(username string) error {
+	q := `DELETE FROM pfx_users WHERE username = ?`
+	_, err := db.Exec(cq(db, q), username)
+	return errors.Trace(err)
+}
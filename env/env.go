@@ -8,14 +8,23 @@ import (
 
 	"github.com/juju/errors"
 
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
 )
 
 // Env provides runtime access to Revere's static environment.
 type Env struct {
 	DB   *db.DB
+	Auth *auth.Auth
 	Port uint16
 	Host string
+
+	// MetricsPort, if set, is a port to serve metrics on apart from the web
+	// UI, for processes that only run the daemon. Metrics served on it do not
+	// require logging in.
+	MetricsPort uint16
+
+	Delivery DeliveryJSONModel
 }
 
 // New initializes an Env based on the configuration found in conf, which
@@ -41,8 +50,18 @@ func New(conf []byte) (*Env, error) {
 	if err != nil {
 		return nil, errors.Maskf(err, "load DB")
 	}
+	e.Auth, err = auth.New(model.Auth, e.DB)
+	if err != nil {
+		return nil, errors.Maskf(err, "load auth")
+	}
 	e.Port = model.Port
 	e.Host = model.Host
+	e.MetricsPort = model.MetricsPort
+
+	if model.Delivery.Workers < 0 || model.Delivery.LeaseSeconds < 0 {
+		return nil, errors.New("delivery workers and lease must not be negative")
+	}
+	e.Delivery = model.Delivery
 
 	return &e, nil
 }
@@ -51,6 +70,23 @@ func New(conf []byte) (*Env, error) {
 // file.
 type EnvJSONModel struct {
 	DB   db.DBJSONModel
+	Auth auth.AuthJSONModel
 	Port uint16
 	Host string
+
+	MetricsPort uint16
+
+	Delivery DeliveryJSONModel
+}
+
+// DeliveryJSONModel configures how the daemon sends queued alerts. Zero values
+// leave the defaults in place.
+type DeliveryJSONModel struct {
+	// Workers is how many alerts are sent at once.
+	Workers int
+
+	// LeaseSeconds is how long alerts claimed by a daemon are hidden from
+	// other daemons. Daemons keep extending the leases of alerts they are
+	// still sending, so this only matters when a daemon dies.
+	LeaseSeconds int
 }
@@ -9,6 +9,10 @@ require (
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/juju/errors v1.0.0
 	github.com/julienschmidt/httprouter v1.3.0
+	github.com/prometheus/client_golang v1.11.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/sirupsen/logrus v1.9.0
+	golang.org/x/crypto v0.7.0
 	golang.org/x/sys v0.6.0
+	sigs.k8s.io/yaml v1.3.0
 )
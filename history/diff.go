@@ -0,0 +1,65 @@
+package history
+
+import (
+	"strings"
+)
+
+// Line is a line of a version, or of the version before it.
+type Line struct {
+	Text string
+	// Added is set for lines only in the version, and Removed for lines only
+	// in the version before it.
+	Added   bool
+	Removed bool
+}
+
+// diff returns the lines of b, along with the lines of a that were removed
+// from it, in order.
+func diff(a, b string) []Line {
+	as, bs := splitLines(a), splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of as[i:] and
+	// bs[j:]. Snapshots are short enough for this to be cheap.
+	lcs := make([][]int, len(as)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(as) || j < len(bs) {
+		switch {
+		case i < len(as) && j < len(bs) && as[i] == bs[j]:
+			lines = append(lines, Line{Text: bs[j]})
+			i++
+			j++
+		case i < len(as) && (j == len(bs) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removed lines come before the lines that replace them.
+			lines = append(lines, Line{Text: as[i], Removed: true})
+			i++
+		default:
+			lines = append(lines, Line{Text: bs[j], Added: true})
+			j++
+		}
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected []Line
+	}{
+		{"", "a\nb\n", []Line{{Text: "a", Added: true}, {Text: "b", Added: true}}},
+		{"a\nb\n", "a\nb\n", []Line{{Text: "a"}, {Text: "b"}}},
+		{
+			"name: disk\nowner: me\nlevel: Warning\n",
+			"name: disk\nowner: you\nlevel: Warning\nperiod: 1\n",
+			[]Line{
+				{Text: "name: disk"},
+				{Text: "owner: me", Removed: true},
+				{Text: "owner: you", Added: true},
+				{Text: "level: Warning"},
+				{Text: "period: 1", Added: true},
+			},
+		},
+		{"a\nb\nc\n", "a\nc\n", []Line{{Text: "a"}, {Text: "b", Removed: true}, {Text: "c"}}},
+	}
+
+	for _, c := range cases {
+		if lines := diff(c.a, c.b); !reflect.DeepEqual(lines, c.expected) {
+			t.Errorf("Expected diff of %q and %q to be %+v, got %+v\n", c.a, c.b, c.expected, lines)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Package history keeps every saved version of Revere's monitors, labels,
+silences and settings, along with who saved it and when, so that changes can
+be audited and monitors can be restored to earlier versions.
+
+Monitors and labels are kept in the form package manifest reads and writes,
+and silences and settings in a similar form. Versions are shown as YAML.
+*/
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/juju/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/manifest"
+	"github.com/yext/revere/setting"
+	"github.com/yext/revere/web/vm"
+)
+
+// monitorType is the component type of monitors' history, which is the name
+// of the component.
+const monitorType = "Monitor"
+
+// Version is one saved version of a monitor, label, silence or setting.
+type Version struct {
+	Version  int32
+	Author   string
+	Recorded time.Time
+	// Snapshot is the component as it was saved, as YAML.
+	Snapshot string
+}
+
+// Change is a version of a component, with how it differs from the version
+// before it. Previous is nil for the first version.
+type Change struct {
+	*Version
+	Previous *Version
+	Lines    []Line
+}
+
+type labelSnapshot struct {
+	*manifest.Label
+	Monitors []*labelMonitor `json:"monitors,omitempty"`
+}
+
+type labelMonitor struct {
+	Name      string `json:"name"`
+	Subprobes string `json:"subprobes,omitempty"`
+}
+
+type silenceSnapshot struct {
+	Monitor   string    `json:"monitor"`
+	Subprobes string    `json:"subprobes,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+type settingSnapshot struct {
+	SettingType string          `json:"settingType"`
+	Setting     setting.Setting `json:"setting"`
+}
+
+// Record records c, as it has just been saved in tx, as a new version saved
+// by author.
+func Record(tx *db.Tx, c vm.NamedComponent, author string) error {
+	return record(tx, c, author, nil)
+}
+
+// RecordArchived records that a monitor has just been archived in tx by
+// author, as a new version marked as archived.
+func RecordArchived(tx *db.Tx, id db.MonitorID, author string) error {
+	m, err := vm.NewMonitor(tx, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if m.Archived == nil {
+		return errors.Errorf("Monitor %d is not archived", id)
+	}
+	return record(tx, m, author, map[string]interface{}{"archived": m.Archived.UTC()})
+}
+
+// RecordDeleted records that c is being deleted in tx by author, as a new
+// version marked as deleted. It must be called before c is deleted.
+func RecordDeleted(tx *db.Tx, c vm.NamedComponent, author string) error {
+	return record(tx, c, author, map[string]interface{}{"deleted": true})
+}
+
+// record records c as it is in tx as a new version saved by author, with the
+// given fields added to its snapshot.
+func record(tx *db.Tx, c vm.NamedComponent, author string, fields map[string]interface{}) error {
+	s, err := snapshot(tx, c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Maskf(err, "serialize %s %d", c.ComponentName(), c.Id())
+	}
+	if fields != nil {
+		var m map[string]interface{}
+		err = json.Unmarshal(data, &m)
+		if err != nil {
+			return errors.Maskf(err, "serialize %s %d", c.ComponentName(), c.Id())
+		}
+		for k, v := range fields {
+			m[k] = v
+		}
+		data, err = json.Marshal(m)
+		if err != nil {
+			return errors.Maskf(err, "serialize %s %d", c.ComponentName(), c.Id())
+		}
+	}
+
+	_, err = tx.CreateHistory(&db.History{
+		ComponentType: c.ComponentName(),
+		ComponentID:   c.Id(),
+		Author:        author,
+		Recorded:      time.Now().UTC(),
+		Snapshot:      string(data),
+	})
+	return errors.Trace(err)
+}
+
+// snapshot returns c as it is in tx, in the form its history is kept in, with
+// secrets such as PagerDuty keys redacted.
+func snapshot(tx *db.Tx, c vm.NamedComponent) (interface{}, error) {
+	switch c := c.(type) {
+	case *vm.Monitor:
+		m, err := vm.NewMonitor(tx, c.MonitorID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s, err := manifest.MonitorSnapshot(m)
+		return s, errors.Trace(err)
+	case *vm.Label:
+		l, err := vm.NewLabel(tx, c.LabelID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		label, err := manifest.LabelSnapshot(l)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s := &labelSnapshot{Label: label}
+		for _, lm := range l.Monitors {
+			s.Monitors = append(s.Monitors, &labelMonitor{
+				Name:      lm.Monitor.Name,
+				Subprobes: lm.Subprobes,
+			})
+		}
+		return s, nil
+	case *vm.Silence:
+		ms, err := tx.LoadMonitorSilence(c.SilenceID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ms == nil {
+			return nil, errors.Errorf("Silence not found: %d", c.SilenceID)
+		}
+		return &silenceSnapshot{
+			Monitor:   ms.MonitorName,
+			Subprobes: ms.Subprobes,
+			Start:     ms.Start.UTC(),
+			End:       ms.End.UTC(),
+		}, nil
+	case *setting.VM:
+		return &settingSnapshot{
+			SettingType: c.Setting.Name(),
+			Setting:     redactSetting(c.Setting),
+		}, nil
+	default:
+		return nil, errors.Errorf("no history is kept for %s", c.ComponentName())
+	}
+}
+
+// Recorder returns a function that records components saved by author, for
+// saves that take a callback, such as manifest.Plan.Apply.
+func Recorder(author string) func(*db.Tx, vm.NamedComponent) error {
+	return func(tx *db.Tx, c vm.NamedComponent) error {
+		return Record(tx, c, author)
+	}
+}
+
+// MonitorVersions returns every version of a monitor, newest first.
+func MonitorVersions(tx *db.Tx, id db.MonitorID) ([]*Version, error) {
+	history, err := tx.LoadHistory(monitorType, int64(id))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	versions := make([]*Version, len(history))
+	for i, h := range history {
+		versions[i], err = newVersion(h)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return versions, nil
+}
+
+// MonitorChange returns a version of a monitor with what changed in it, or nil
+// if there is no such version.
+func MonitorChange(tx *db.Tx, id db.MonitorID, version int32) (*Change, error) {
+	v, err := monitorVersion(tx, id, version)
+	if err != nil || v == nil {
+		return nil, errors.Trace(err)
+	}
+
+	c := &Change{Version: v}
+	if version > 1 {
+		c.Previous, err = monitorVersion(tx, id, version-1)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	var previous string
+	if c.Previous != nil {
+		previous = c.Previous.Snapshot
+	}
+	c.Lines = diff(previous, v.Snapshot)
+	return c, nil
+}
+
+func monitorVersion(tx *db.Tx, id db.MonitorID, version int32) (*Version, error) {
+	h, err := tx.LoadHistoryVersion(monitorType, int64(id), version)
+	if err != nil || h == nil {
+		return nil, errors.Trace(err)
+	}
+	return newVersion(h)
+}
+
+func newVersion(h *db.History) (*Version, error) {
+	snapshot, err := yaml.JSONToYAML([]byte(h.Snapshot))
+	if err != nil {
+		return nil, errors.Maskf(err, "load version %d of %s %d", h.Version, h.ComponentType, h.ComponentID)
+	}
+
+	return &Version{
+		Version:  h.Version,
+		Author:   h.Author,
+		Recorded: h.Recorded,
+		Snapshot: string(snapshot),
+	}, nil
+}
+
+// RestoreMonitor saves a monitor as it was in the given version, recording
+// the restored monitor as a new version saved by author. If the old version
+// is no longer valid, for example because a label it had has been deleted, it
+// returns why, and nothing is saved.
+func RestoreMonitor(DB *db.DB, id db.MonitorID, version int32, author string) (errs []string, err error) {
+	err = DB.Tx(func(tx *db.Tx) error {
+		h, err := tx.LoadHistoryVersion(monitorType, int64(id), version)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if h == nil {
+			return errors.Errorf("Version %d of monitor %d not found", version, id)
+		}
+
+		current, err := vm.NewMonitor(tx, id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		m, err := manifest.RestoreMonitor(tx, []byte(h.Snapshot), current)
+		if invalid, ok := errors.Cause(err).(manifest.ValidationErrors); ok {
+			errs = invalid
+			return nil
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		errs = m.Validate(DB)
+		if errs != nil {
+			return nil
+		}
+
+		err = m.Save(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return Record(tx, m, author)
+	})
+	return errs, errors.Trace(err)
+}
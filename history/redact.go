@@ -0,0 +1,31 @@
+package history
+
+import (
+	"github.com/yext/revere/setting"
+	"github.com/yext/revere/target"
+)
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return target.Redacted
+}
+
+// redactSetting returns a copy of s with its secrets redacted. The outgoing
+// email setting has none, as mail is sent without authenticating.
+func redactSetting(s setting.Setting) setting.Setting {
+	switch s := s.(type) {
+	case *setting.SlackSetting:
+		c := *s
+		c.APIToken = redact(c.APIToken)
+		c.WebhookURL = redact(c.WebhookURL)
+		return &c
+	case *setting.PagerDutySetting:
+		c := *s
+		c.IntegrationKey = redact(c.IntegrationKey)
+		return &c
+	default:
+		return s
+	}
+}
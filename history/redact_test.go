@@ -0,0 +1,37 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yext/revere/setting"
+	"github.com/yext/revere/target"
+)
+
+const testPagerDutyKey = "0123456789abcdef0123456789abcdef"
+
+func TestRedactSetting(t *testing.T) {
+	slack := &setting.SlackSetting{APIToken: "xoxb-token", BotName: "revere", WebhookURL: "https://hooks/secret"}
+	redactedSlack, ok := redactSetting(slack).(*setting.SlackSetting)
+	if !ok || redactedSlack.APIToken != target.Redacted || redactedSlack.WebhookURL != target.Redacted || redactedSlack.BotName != "revere" {
+		t.Errorf("Expected the Slack token and webhook URL redacted, got %+v\n", redactedSlack)
+	}
+	if slack.APIToken != "xoxb-token" {
+		t.Errorf("Expected the saved setting unchanged, got %+v\n", slack)
+	}
+
+	pagerDuty := &setting.PagerDutySetting{IntegrationKey: testPagerDutyKey}
+	if s := redactSetting(pagerDuty).(*setting.PagerDutySetting); s.IntegrationKey != target.Redacted {
+		t.Errorf("Expected the PagerDuty key redacted, got %+v\n", s)
+	}
+
+	data, err := json.Marshal(&settingSnapshot{SettingType: "Slack", Setting: redactSetting(slack)})
+	if err != nil {
+		t.Fatalf("Failed to serialize snapshot: %s\n", err.Error())
+	}
+	var snapshot struct{ Setting map[string]interface{} }
+	json.Unmarshal(data, &snapshot)
+	if snapshot.Setting["APIToken"] != target.Redacted {
+		t.Errorf("Expected the snapshot's token redacted, got %s\n", data)
+	}
+}
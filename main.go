@@ -4,6 +4,9 @@ Revere is an alerting system for medium-sized microservices architectures.
 Usage:
 
 	revere [-conf env.json] [-mode mode,...]
+	revere [-conf env.json] -mode apply -file monitors.yaml [-dryRun]
+	revere [-conf env.json] -mode export [-file monitors.yaml]
+	revere [-conf env.json] -mode user -user name -role role
 
 Configuration
 
@@ -29,24 +32,45 @@ The daemon mode runs the daemon that monitors systems and generates alerts.
 The web mode serves the HTTP UI for administering Revere and viewing its
 current state.
 
+The apply mode makes the monitors and labels in the manifest file given by
+the -file flag match the database, printing the changes it makes. With
+-dryRun, it only prints them. The export mode writes every monitor and label
+to the -file manifest, or to standard output as YAML if -file is not set.
+Manifests are JSON if the file name ends in .json and YAML otherwise. See
+github.com/yext/revere/manifest for the format. Neither mode can be combined
+with any other modes.
+
+The user mode adds the local web UI user given by the -user flag, or changes
+their role, reading their password from standard input. An empty password
+keeps an existing user's password. With -role none, the user is removed.
+Roles are viewer, editor and admin. This mode cannot be combined with any
+other modes.
+
 The -mode flag defaults to daemon,web.
 */
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/signal"
+	osuser "os/user"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
 	"golang.org/x/sys/unix"
 
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/daemon"
 	"github.com/yext/revere/env"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/manifest"
+	"github.com/yext/revere/metrics"
 	"github.com/yext/revere/web/server"
 )
 
@@ -54,6 +78,10 @@ var (
 	conf     = flag.String("conf", "", "JSON `file` configuring Revere's static environment")
 	mode     = flag.String("mode", "daemon,web", "comma-separated `modes` to run")
 	logLevel = flag.String("logLevel", "warn", "Logrus `level` to log at")
+	file     = flag.String("file", "", "manifest `file` for the apply and export modes")
+	dryRun   = flag.Bool("dryRun", false, "print what the apply mode would change without changing it")
+	user     = flag.String("user", "", "`name` of the local user for the user mode")
+	role     = flag.String("role", "", "`role` to give the user in the user mode")
 )
 
 func main() {
@@ -74,10 +102,28 @@ func main() {
 	modes, err := parseMode()
 	ifErrPrintAndExit(err)
 
-	if modes[0] == "initdb" {
+	switch modes[0] {
+	case "initdb":
 		err := env.DB.Init()
 		ifErrPrintAndExit(err)
 		return
+	case "apply":
+		err := applyManifest(env)
+		ifErrPrintAndExit(err)
+		return
+	case "export":
+		err := exportManifest(env)
+		ifErrPrintAndExit(err)
+		return
+	case "user":
+		err := setUser(env)
+		ifErrPrintAndExit(err)
+		return
+	}
+
+	if env.MetricsPort != 0 {
+		s := metrics.Start(env.MetricsPort)
+		defer s.Close()
 	}
 
 	for _, mode := range modes {
@@ -131,7 +177,7 @@ func parseMode() ([]string, error) {
 	modes := make(map[string]bool)
 	for _, m := range strings.Split(*mode, ",") {
 		switch m {
-		case "daemon", "initdb", "web":
+		case "daemon", "initdb", "web", "apply", "export", "user":
 			if modes[m] {
 				return nil, errors.New("duplicate mode " + m)
 			}
@@ -141,8 +187,18 @@ func parseMode() ([]string, error) {
 		}
 	}
 
-	if modes["initdb"] && len(modes) > 1 {
-		return nil, errors.New("initdb cannot be combined with other modes")
+	for _, m := range []string{"initdb", "apply", "export", "user"} {
+		if modes[m] && len(modes) > 1 {
+			return nil, errors.New(m + " cannot be combined with other modes")
+		}
+	}
+
+	if modes["apply"] && *file == "" {
+		return nil, errors.New("apply needs a manifest file")
+	}
+
+	if modes["user"] && (*user == "" || *role == "") {
+		return nil, errors.New("user needs a user name and role")
 	}
 
 	modesSlice := make([]string, len(modes))
@@ -154,6 +210,87 @@ func parseMode() ([]string, error) {
 	return modesSlice, nil
 }
 
+func applyManifest(env *env.Env) error {
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		return errors.Maskf(err, "load manifest %s", *file)
+	}
+
+	m, err := manifest.Parse(data, manifest.FormatOf(*file))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	plan, err := manifest.NewPlan(env.DB, m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Print(plan)
+	if *dryRun || plan.IsEmpty() {
+		return nil
+	}
+
+	err = plan.Apply(env.DB, history.Recorder(applyAuthor()))
+	if err != nil {
+		return errors.Maskf(err, "apply manifest %s", *file)
+	}
+	fmt.Println("Applied.")
+	return nil
+}
+
+// applyAuthor returns who the history of changes made by the apply mode
+// records them as made by.
+func applyAuthor() string {
+	name := "unknown"
+	if u, err := osuser.Current(); err == nil {
+		name = u.Username
+	}
+	return name + " (apply)"
+}
+
+func exportManifest(env *env.Env) error {
+	m, err := manifest.Export(env.DB)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	format := manifest.YAML
+	if *file != "" {
+		format = manifest.FormatOf(*file)
+	}
+	data, err := manifest.Marshal(m, format)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if *file == "" {
+		_, err = os.Stdout.Write(data)
+		return errors.Trace(err)
+	}
+	return errors.Maskf(ioutil.WriteFile(*file, data, 0644), "write manifest %s", *file)
+}
+
+func setUser(env *env.Env) error {
+	if *role == "none" {
+		return errors.Maskf(env.Auth.DeleteUser(*user), "remove user %s", *user)
+	}
+
+	r, err := auth.ParseRole(*role)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Password for %s: ", *user)
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return errors.Maskf(err, "read password")
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	return errors.Maskf(env.Auth.SetUser(*user, password, r), "set user %s", *user)
+}
+
 func waitForExitSignal() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, unix.SIGHUP, unix.SIGINT, unix.SIGTERM)
@@ -0,0 +1,105 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/web/vm"
+)
+
+// Label is a label with its triggers. Labels are identified by name, and are
+// applied to monitors by listing them in the monitors' labels.
+type Label struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Triggers    []*Trigger `json:"triggers,omitempty"`
+}
+
+func newLabel(l *vm.Label) (*Label, error) {
+	label := &Label{
+		Name:        l.Name,
+		Description: l.Description,
+	}
+
+	for _, lt := range l.Triggers {
+		t, err := newTrigger(lt.Trigger, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		label.Triggers = append(label.Triggers, t)
+	}
+
+	return label, nil
+}
+
+// normalize puts l in the same form as labels loaded from the DB, so that they
+// can be compared.
+func (l *Label) normalize() (errs []string) {
+	in := fmt.Sprintf("label %q", l.Name)
+	if l.Name == "" {
+		errs = append(errs, "Every label needs a name.")
+	}
+
+	for _, t := range l.Triggers {
+		if t.Subprobes != "" {
+			errs = append(errs, fmt.Sprintf("Triggers of %s cannot set subprobes; set them where the label is applied.", in))
+		}
+		errs = append(errs, t.normalize(in)...)
+	}
+	return
+}
+
+// diff returns the names of the parts of l that differ from old.
+func (l *Label) diff(old *Label) (fields []string) {
+	if l.Description != old.Description {
+		fields = append(fields, "description")
+	}
+	if triggersDiffer(l.Triggers, old.Triggers) {
+		fields = append(fields, "triggers")
+	}
+	return
+}
+
+// unredact fills in the redacted secrets of l's triggers from those of old, the
+// label l replaces, or nil if l is new.
+func (l *Label) unredact(old *vm.Label) ([]string, error) {
+	var oldTriggers []*vm.Trigger
+	if old != nil {
+		for _, lt := range old.Triggers {
+			oldTriggers = append(oldTriggers, lt.Trigger)
+		}
+	}
+	return unredactTriggers(l.Triggers, oldTriggers, fmt.Sprintf("label %q", l.Name))
+}
+
+// toVM converts l to a label that can be validated and saved. old is the label
+// being replaced, or nil if l is new. Which monitors have the label is left
+// alone, as that is saved with the monitors.
+func (l *Label) toVM(old *vm.Label) (*vm.Label, error) {
+	label := &vm.Label{
+		Name:        l.Name,
+		Description: l.Description,
+	}
+
+	var oldTriggers []*vm.Trigger
+	if old != nil {
+		label.LabelID = old.LabelID
+		for _, lt := range old.Triggers {
+			oldTriggers = append(oldTriggers, lt.Trigger)
+		}
+	}
+
+	triggers, deleted, err := mergeTriggers(l.Triggers, oldTriggers)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, t := range append(triggers, deleted...) {
+		label.Triggers = append(label.Triggers, &vm.LabelTrigger{
+			Trigger: t,
+			LabelID: label.LabelID,
+		})
+	}
+
+	return label, nil
+}
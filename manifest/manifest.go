@@ -0,0 +1,196 @@
+/*
+Package manifest reads and writes monitors, their triggers and labels in a
+declarative YAML or JSON format, so that they can be kept in version control.
+
+Monitors and labels are identified by name rather than ID, and probe and
+target types by their names, so that a manifest can be applied to any Revere
+database. Applying a manifest creates or updates the monitors and labels in it
+to match; monitors and labels that are not in the manifest are left alone.
+*/
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+)
+
+// Manifest is the set of labels and monitors in a manifest file.
+type Manifest struct {
+	Labels   []*Label   `json:"labels,omitempty"`
+	Monitors []*Monitor `json:"monitors,omitempty"`
+}
+
+// Format is the file format of a manifest.
+type Format int
+
+const (
+	YAML Format = iota
+	JSON
+)
+
+// FormatOf returns the format of the manifest file at path, judging by its
+// extension. Files are YAML unless they end in .json.
+func FormatOf(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return JSON
+	}
+	return YAML
+}
+
+// Parse reads a manifest. Unknown fields are rejected, as they are most likely
+// typos.
+func Parse(data []byte, format Format) (*Manifest, error) {
+	var err error
+	if format == YAML {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, errors.Maskf(err, "parse manifest")
+		}
+	}
+
+	var m Manifest
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	err = d.Decode(&m)
+	if err != nil {
+		return nil, errors.Maskf(err, "parse manifest")
+	}
+	return &m, nil
+}
+
+// Marshal writes a manifest.
+func Marshal(m *Manifest, format Format) ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, errors.Maskf(err, "write manifest")
+	}
+
+	if format == YAML {
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return nil, errors.Maskf(err, "write manifest")
+		}
+		return data, nil
+	}
+	return append(data, '\n'), nil
+}
+
+// Export returns a manifest of every label and every monitor that has not been
+// archived, sorted by name. Target secrets are redacted; applying the manifest
+// keeps them.
+func Export(DB *db.DB) (*Manifest, error) {
+	monitors, labels, err := load(DB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var m Manifest
+	for _, l := range labels {
+		label, err := newLabel(l)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m.Labels = append(m.Labels, label)
+	}
+	for _, monitor := range monitors {
+		mon, err := newMonitor(monitor)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m.Monitors = append(m.Monitors, mon)
+	}
+	return &m, nil
+}
+
+// load loads every label and every monitor that has not been archived, with
+// their triggers, sorted by name.
+func load(DB *db.DB) (monitors []*vm.Monitor, labels []*vm.Label, err error) {
+	err = DB.Tx(func(tx *db.Tx) error {
+		all, err := vm.AllMonitors(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, m := range all {
+			if m.Archived != nil {
+				continue
+			}
+			m, err = vm.NewMonitor(tx, m.MonitorID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			monitors = append(monitors, m)
+		}
+
+		allLabels, err := vm.AllLabels(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, l := range allLabels {
+			l, err = vm.NewLabel(tx, l.LabelID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			labels = append(labels, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	sort.SliceStable(monitors, func(i, j int) bool {
+		return monitors[i].Name < monitors[j].Name
+	})
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return monitors, labels, nil
+}
+
+// normalize puts every label and monitor in m in the same form as those
+// loaded from the DB, and checks that names are unique.
+func (m *Manifest) normalize() (errs []string) {
+	labels := make(map[string]bool)
+	for _, l := range m.Labels {
+		if labels[l.Name] {
+			errs = append(errs, fmt.Sprintf("Label %q is in the manifest more than once.", l.Name))
+		}
+		labels[l.Name] = true
+		errs = append(errs, l.normalize()...)
+	}
+
+	monitors := make(map[string]bool)
+	for _, monitor := range m.Monitors {
+		if monitors[monitor.Name] {
+			errs = append(errs, fmt.Sprintf("Monitor %q is in the manifest more than once.", monitor.Name))
+		}
+		monitors[monitor.Name] = true
+		errs = append(errs, monitor.normalize()...)
+	}
+	return
+}
+
+// canonicalJSON reformats s so that equivalent JSON compares equal. Invalid
+// JSON is returned as is.
+func canonicalJSON(s string) string {
+	var v interface{}
+	err := json.Unmarshal([]byte(s), &v)
+	if err != nil {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
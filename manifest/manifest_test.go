@@ -0,0 +1,414 @@
+package manifest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/probe"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+	"github.com/yext/revere/test"
+	"github.com/yext/revere/web/vm"
+)
+
+const testManifest = `
+labels:
+- name: prod
+  triggers:
+  - level: critical
+    period: 60
+    periodType: minute
+    targetType: email
+    target:
+      Emails:
+      - EmailTo: oncall@example.com
+monitors:
+- name: disk
+  probeType: graphite threshold
+  probe: ` + test.DefaultProbeJson + `
+  sustainPeriod: 120
+  sustainPeriodType: second
+  triggers:
+  - subprobes: .*
+    level: Warning
+    period: 1
+    periodType: hour
+    targetType: Email
+    target:
+      Emails:
+      - EmailTo: ops@example.com
+  labels:
+  - name: prod
+`
+
+func dbMonitor(t *testing.T) *vm.Monitor {
+	p, err := probe.LoadFromParams(probe.GraphiteThresholdType{}.Id(), test.DefaultProbeJson)
+	if err != nil {
+		t.Fatalf("Failed to load probe: %s\n", err.Error())
+	}
+	email, err := target.LoadFromParams(target.EmailType{}.Id(), `{"Emails": [{"EmailTo": "ops@example.com"}]}`)
+	if err != nil {
+		t.Fatalf("Failed to load target: %s\n", err.Error())
+	}
+
+	return &vm.Monitor{
+		MonitorID:         7,
+		Name:              "disk",
+		ProbeType:         p.Id(),
+		Probe:             p,
+		SustainPeriod:     2,
+		SustainPeriodType: "minute",
+		Triggers: []*vm.MonitorTrigger{{
+			MonitorID: 7,
+			Subprobes: ".*",
+			Trigger: &vm.Trigger{
+				TriggerID:  3,
+				Level:      state.Warning,
+				Period:     1,
+				PeriodType: "hour",
+				TargetType: email.Id(),
+				Target:     email,
+			},
+		}},
+		Labels: []*vm.MonitorLabel{{
+			Label:     &vm.Label{LabelID: 4, Name: "prod"},
+			MonitorID: 7,
+		}},
+	}
+}
+
+func parseTestManifest(t *testing.T) *Manifest {
+	m, err := Parse([]byte(testManifest), YAML)
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %s\n", err.Error())
+	}
+	if errs := m.normalize(); errs != nil {
+		t.Fatalf("Unexpected errors: %v\n", errs)
+	}
+	return m
+}
+
+func TestNormalize(t *testing.T) {
+	m := parseTestManifest(t)
+
+	l := m.Labels[0]
+	if l.Triggers[0].Level != "CRITICAL" || l.Triggers[0].Period != 1 || l.Triggers[0].PeriodType != "hour" {
+		t.Errorf("Expected a CRITICAL trigger every hour, got %+v\n", l.Triggers[0])
+	}
+	if l.Triggers[0].TargetType != "Email" {
+		t.Errorf("Expected target type Email, got %s\n", l.Triggers[0].TargetType)
+	}
+
+	monitor := m.Monitors[0]
+	if monitor.ProbeType != "Graphite Threshold" {
+		t.Errorf("Expected probe type Graphite Threshold, got %s\n", monitor.ProbeType)
+	}
+	if monitor.SustainPeriod != 2 || monitor.SustainPeriodType != "minute" {
+		t.Errorf("Expected sustain period of 2 minutes, got %d %s\n", monitor.SustainPeriod, monitor.SustainPeriodType)
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	m, err := Parse([]byte(`
+monitors:
+- name: disk
+  probeType: tea leaves
+  sustainPeriod: 2
+  sustainPeriodType: fortnight
+  triggers:
+  - level: Loud
+    period: 1
+    periodType: hour
+    targetType: Email
+    target: {}
+- name: disk
+  probeType: Graphite Threshold
+  probe: {}
+`), YAML)
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %s\n", err.Error())
+	}
+
+	errs := m.normalize()
+	for _, expected := range []string{"probe type", "sustain period", "level", "more than once"} {
+		found := false
+		for _, e := range errs {
+			found = found || strings.Contains(e, expected)
+		}
+		if !found {
+			t.Errorf("Expected an error about %s, got %v\n", expected, errs)
+		}
+	}
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	_, err := Parse([]byte("monitors:\n- name: disk\n  ownr: me\n"), YAML)
+	if err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	m := parseTestManifest(t)
+	monitor := m.Monitors[0]
+
+	old, err := newMonitor(dbMonitor(t))
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	if fields := monitor.diff(old); fields != nil {
+		t.Errorf("Expected no changes, got %v\n", fields)
+	}
+
+	monitor.Owner = "someone"
+	monitor.Triggers[0].Level = state.Critical.String()
+	monitor.Labels = nil
+	expected := []string{"owner", "triggers", "labels"}
+	if fields := monitor.diff(old); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected changes to %v, got %v\n", expected, fields)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{YAML, JSON} {
+		monitor, err := newMonitor(dbMonitor(t))
+		if err != nil {
+			t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+		}
+
+		data, err := Marshal(&Manifest{Monitors: []*Monitor{monitor}}, format)
+		if err != nil {
+			t.Fatalf("Failed to write manifest: %s\n", err.Error())
+		}
+		m, err := Parse(data, format)
+		if err != nil {
+			t.Fatalf("Failed to parse manifest %s: %s\n", data, err.Error())
+		}
+		if errs := m.normalize(); errs != nil {
+			t.Fatalf("Unexpected errors: %v\n", errs)
+		}
+
+		if fields := m.Monitors[0].diff(monitor); fields != nil {
+			t.Errorf("Expected exported monitor to be unchanged, got changes to %v in %s\n", fields, data)
+		}
+	}
+}
+
+// secretMonitor is dbMonitor with triggers whose targets have secrets.
+func secretMonitor(t *testing.T) *vm.Monitor {
+	m := dbMonitor(t)
+	base := *m.Triggers[0].Trigger
+	m.Triggers = nil
+	targets := []target.VM{
+		target.PagerDutyTarget{IntegrationKey: "0123456789abcdef0123456789abcdef"},
+		target.WebhookTarget{
+			URL:        "https://example.com/hook",
+			Headers:    "Authorization: Bearer s3cret-token",
+			HMACSecret: "s3cret-hmac",
+		},
+		target.TeamsTarget{WebhookURL: "https://example.webhook.office.com/s3cret"},
+	}
+	for i, tv := range targets {
+		trigger := base
+		trigger.TriggerID = db.TriggerID(i + 3)
+		trigger.TargetType = tv.Id()
+		trigger.Target = tv
+		m.Triggers = append(m.Triggers, &vm.MonitorTrigger{MonitorID: 7, Subprobes: ".*", Trigger: &trigger})
+	}
+	return m
+}
+
+var secrets = []string{
+	"0123456789abcdef0123456789abcdef",
+	"s3cret-token",
+	"s3cret-hmac",
+	"https://example.webhook.office.com/s3cret",
+}
+
+func TestSecretsRoundTrip(t *testing.T) {
+	monitor, err := newMonitor(secretMonitor(t))
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	data, err := Marshal(&Manifest{Monitors: []*Monitor{monitor}}, YAML)
+	if err != nil {
+		t.Fatalf("Failed to write manifest: %s\n", err.Error())
+	}
+	for _, secret := range secrets {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be redacted, got %s\n", secret, data)
+		}
+	}
+
+	m, err := Parse(data, YAML)
+	if err != nil {
+		t.Fatalf("Failed to parse manifest %s: %s\n", data, err.Error())
+	}
+	if errs := m.normalize(); errs != nil {
+		t.Fatalf("Unexpected errors: %v\n", errs)
+	}
+	errs, err := m.Monitors[0].unredact(secretMonitor(t))
+	if err != nil || errs != nil {
+		t.Fatalf("Unexpected errors: %v %v\n", errs, err)
+	}
+	if fields := m.Monitors[0].diff(monitor); fields != nil {
+		t.Errorf("Expected exported monitor to be unchanged, got changes to %v\n", fields)
+	}
+
+	v, err := m.Monitors[0].toVM(secretMonitor(t), nil)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	var params string
+	for _, mt := range v.Triggers {
+		params += mt.Trigger.TargetParams
+	}
+	for _, secret := range secrets {
+		if !strings.Contains(params, secret) {
+			t.Errorf("Expected %q to be kept, got %s\n", secret, params)
+		}
+	}
+
+	// Changing a secret is a change.
+	m, _ = Parse(data, YAML)
+	m.normalize()
+	m.Monitors[0].Triggers[0].Target = json.RawMessage(`{"IntegrationKey": "fedcba9876543210fedcba9876543210"}`)
+	m.normalize()
+	m.Monitors[0].unredact(secretMonitor(t))
+	if fields := m.Monitors[0].diff(monitor); !reflect.DeepEqual(fields, []string{"triggers"}) {
+		t.Errorf("Expected the triggers to change, got changes to %v\n", fields)
+	}
+
+	// Secrets cannot be kept from a trigger of another type, or from none.
+	m, _ = Parse(data, YAML)
+	m.normalize()
+	errs, err = m.Monitors[0].unredact(dbMonitor(t))
+	if err != nil || len(errs) != 3 {
+		t.Errorf("Expected errors for the three redacted triggers, got %v %v\n", errs, err)
+	}
+}
+
+func TestToVM(t *testing.T) {
+	m := parseTestManifest(t)
+	monitor := m.Monitors[0]
+	added := *monitor.Triggers[0]
+	added.Subprobes = "db.*"
+	monitor.Triggers = append(monitor.Triggers, &added)
+	monitor.Labels = []*MonitorLabel{{Name: "staging"}}
+
+	v, err := monitor.toVM(dbMonitor(t), map[string]db.LabelID{"prod": 4, "staging": 5})
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	if v.MonitorID != 7 {
+		t.Errorf("Expected monitor 7, got %d\n", v.MonitorID)
+	}
+
+	if len(v.Triggers) != 2 || v.Triggers[0].Trigger.TriggerID != 3 || v.Triggers[1].Trigger.TriggerID != 0 {
+		t.Errorf("Expected trigger 3 to be kept and a trigger to be added, got %+v\n", v.Triggers)
+	}
+
+	if len(v.Labels) != 2 {
+		t.Fatalf("Expected 2 labels, got %d\n", len(v.Labels))
+	}
+	if l := v.Labels[0]; l.Label.LabelID != 5 || !l.Create {
+		t.Errorf("Expected label 5 to be added, got %+v\n", l)
+	}
+	if l := v.Labels[1]; l.Label.LabelID != 4 || !l.Delete {
+		t.Errorf("Expected label 4 to be removed, got %+v\n", l)
+	}
+
+	// Plans validate monitors without their labels, which need the DB.
+	v, err = monitor.toVM(dbMonitor(t), nil)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	if errs := v.Validate(new(db.DB)); errs != nil {
+		t.Errorf("Unexpected errors: %v\n", errs)
+	}
+}
+
+func TestMergeTriggers(t *testing.T) {
+	m := parseTestManifest(t)
+	old := dbMonitor(t).Triggers[0].Trigger
+
+	merged, deleted, err := mergeTriggers(nil, []*vm.Trigger{old})
+	if err != nil {
+		t.Fatalf("Failed to merge triggers: %s\n", err.Error())
+	}
+	if len(merged) != 0 || len(deleted) != 1 || !deleted[0].Delete || deleted[0].TargetParams == "" {
+		t.Errorf("Expected the old trigger to be deleted, got %+v, %+v\n", merged, deleted)
+	}
+
+	merged, deleted, err = mergeTriggers(m.Labels[0].Triggers, []*vm.Trigger{dbMonitor(t).Triggers[0].Trigger})
+	if err != nil {
+		t.Fatalf("Failed to merge triggers: %s\n", err.Error())
+	}
+	if len(merged) != 1 || merged[0].TriggerID != 3 || merged[0].LevelText != "CRITICAL" || len(deleted) != 0 {
+		t.Errorf("Expected trigger 3 to be updated, got %+v, %+v\n", merged, deleted)
+	}
+}
+
+func TestRestoreMonitor(t *testing.T) {
+	snapshot, err := MonitorSnapshot(dbMonitor(t))
+	if err != nil {
+		t.Fatalf("Failed to take snapshot: %s\n", err.Error())
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to serialize snapshot: %s\n", err.Error())
+	}
+
+	current := dbMonitor(t)
+	current.Owner = "someone"
+	current.Triggers[0].Trigger.Level = state.Critical
+	current.Triggers = append(current.Triggers, &vm.MonitorTrigger{
+		MonitorID: 7,
+		Trigger:   &vm.Trigger{TriggerID: 9, TargetType: current.Triggers[0].Trigger.TargetType, Target: current.Triggers[0].Trigger.Target},
+	})
+	current.Labels[0] = &vm.MonitorLabel{Label: &vm.Label{LabelID: 5, Name: "staging"}, MonitorID: 7}
+
+	v, err := restoreMonitor(data, current, map[string]db.LabelID{"prod": 4, "staging": 5})
+	if err != nil {
+		t.Fatalf("Failed to restore monitor: %s\n", err.Error())
+	}
+	if v.MonitorID != 7 || v.Owner != "" {
+		t.Errorf("Expected monitor 7 without an owner, got %d owned by %q\n", v.MonitorID, v.Owner)
+	}
+	if len(v.Triggers) != 2 || v.Triggers[0].Trigger.TriggerID != 3 || v.Triggers[0].Trigger.LevelText != "Warning" ||
+		!v.Triggers[1].Trigger.Delete {
+		t.Errorf("Expected trigger 3 to be restored and trigger 9 deleted, got %+v\n", v.Triggers)
+	}
+	if len(v.Labels) != 2 || !v.Labels[0].Create || v.Labels[0].Label.LabelID != 4 || !v.Labels[1].Delete {
+		t.Errorf("Expected label 4 to be restored and label 5 removed, got %+v\n", v.Labels)
+	}
+
+	_, err = restoreMonitor(data, current, map[string]db.LabelID{"staging": 5})
+	if _, ok := err.(ValidationErrors); !ok {
+		t.Errorf("Expected a validation error for a deleted label, got %v\n", err)
+	}
+
+	snapshot, err = MonitorSnapshot(secretMonitor(t))
+	if err != nil {
+		t.Fatalf("Failed to take snapshot: %s\n", err.Error())
+	}
+	data, err = json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to serialize snapshot: %s\n", err.Error())
+	}
+	labelIDs := map[string]db.LabelID{"prod": 4}
+	v, err = restoreMonitor(data, secretMonitor(t), labelIDs)
+	if err != nil {
+		t.Fatalf("Failed to restore monitor: %s\n", err.Error())
+	}
+	if params := v.Triggers[0].Trigger.TargetParams; !strings.Contains(params, secrets[0]) {
+		t.Errorf("Expected the PagerDuty key to be kept, got %s\n", params)
+	}
+	_, err = restoreMonitor(data, dbMonitor(t), labelIDs)
+	if _, ok := err.(ValidationErrors); !ok {
+		t.Errorf("Expected a validation error for secrets with nowhere to come from, got %v\n", err)
+	}
+}
@@ -0,0 +1,295 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/probe"
+	"github.com/yext/revere/web/vm"
+)
+
+// Monitor is a monitor with its triggers and the labels applied to it.
+// Monitors are identified by name. Probe has the same fields as the probe's
+// form in the web UI.
+type Monitor struct {
+	Name        string          `json:"name"`
+	Owner       string          `json:"owner,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Response    string          `json:"response,omitempty"`
+	ProbeType   string          `json:"probeType"`
+	Probe       json.RawMessage `json:"probe"`
+
+	SustainReadings   int32  `json:"sustainReadings,omitempty"`
+	SustainPeriod     int64  `json:"sustainPeriod,omitempty"`
+	SustainPeriodType string `json:"sustainPeriodType,omitempty"`
+
+	FlapTransitions int32  `json:"flapTransitions,omitempty"`
+	FlapWindow      int64  `json:"flapWindow,omitempty"`
+	FlapWindowType  string `json:"flapWindowType,omitempty"`
+
+	Triggers []*Trigger      `json:"triggers,omitempty"`
+	Labels   []*MonitorLabel `json:"labels,omitempty"`
+
+	// probeDB is the probe as it is stored, which is what probes are compared
+	// by.
+	probeDB string
+}
+
+// MonitorLabel applies the label with the given name to the subprobes of a
+// monitor matching Subprobes.
+type MonitorLabel struct {
+	Name      string `json:"name"`
+	Subprobes string `json:"subprobes,omitempty"`
+}
+
+func newMonitor(m *vm.Monitor) (*Monitor, error) {
+	params, err := json.Marshal(m.Probe)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize probe of monitor %d", m.MonitorID)
+	}
+	probeDB, err := m.Probe.SerializeForDB()
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize probe of monitor %d", m.MonitorID)
+	}
+
+	monitor := &Monitor{
+		Name:        m.Name,
+		Owner:       m.Owner,
+		Description: m.Description,
+		Response:    m.Response,
+		ProbeType:   m.Probe.Name(),
+		Probe:       json.RawMessage(params),
+
+		SustainReadings: m.SustainReadings,
+		FlapTransitions: m.FlapTransitions,
+
+		probeDB: canonicalJSON(probeDB),
+	}
+	monitor.SustainPeriod, monitor.SustainPeriodType = normalizePeriod(m.SustainPeriod, m.SustainPeriodType)
+	monitor.FlapWindow, monitor.FlapWindowType = normalizePeriod(m.FlapWindow, m.FlapWindowType)
+
+	for _, mt := range m.Triggers {
+		t, err := newTrigger(mt.Trigger, mt.Subprobes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		monitor.Triggers = append(monitor.Triggers, t)
+	}
+
+	for _, ml := range m.Labels {
+		monitor.Labels = append(monitor.Labels, &MonitorLabel{
+			Name:      ml.Label.Name,
+			Subprobes: ml.Subprobes,
+		})
+	}
+	monitor.sortLabels()
+
+	return monitor, nil
+}
+
+// normalize puts m in the same form as monitors loaded from the DB, so that
+// they can be compared.
+func (m *Monitor) normalize() (errs []string) {
+	in := fmt.Sprintf("monitor %q", m.Name)
+	if m.Name == "" {
+		errs = append(errs, "Every monitor needs a name.")
+	}
+
+	probeType, ok := probeTypeByName(m.ProbeType)
+	if !ok {
+		errs = append(errs, fmt.Sprintf("Unknown probe type for %s: %q", in, m.ProbeType))
+	} else {
+		m.ProbeType = probeType.Name()
+		loaded, err := probe.LoadFromParams(probeType.Id(), string(m.Probe))
+		if err == nil {
+			m.probeDB, err = loaded.SerializeForDB()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Unable to load probe for %s: %s", in, err.Error()))
+		}
+		m.probeDB = canonicalJSON(m.probeDB)
+	}
+
+	errs = append(errs, checkPeriod("sustain period", in, m.SustainPeriod, m.SustainPeriodType)...)
+	errs = append(errs, checkPeriod("flap detection window", in, m.FlapWindow, m.FlapWindowType)...)
+	m.SustainPeriod, m.SustainPeriodType = normalizePeriod(m.SustainPeriod, m.SustainPeriodType)
+	m.FlapWindow, m.FlapWindowType = normalizePeriod(m.FlapWindow, m.FlapWindowType)
+
+	for _, t := range m.Triggers {
+		errs = append(errs, t.normalize(in)...)
+	}
+
+	seen := make(map[string]bool)
+	for _, ml := range m.Labels {
+		if seen[ml.Name] {
+			errs = append(errs, fmt.Sprintf("Label %q is applied to %s more than once.", ml.Name, in))
+		}
+		seen[ml.Name] = true
+	}
+	m.sortLabels()
+
+	return
+}
+
+func (m *Monitor) sortLabels() {
+	sort.SliceStable(m.Labels, func(i, j int) bool {
+		return m.Labels[i].Name < m.Labels[j].Name
+	})
+}
+
+// diff returns the names of the parts of m that differ from old.
+func (m *Monitor) diff(old *Monitor) (fields []string) {
+	if m.Owner != old.Owner {
+		fields = append(fields, "owner")
+	}
+	if m.Description != old.Description {
+		fields = append(fields, "description")
+	}
+	if m.Response != old.Response {
+		fields = append(fields, "response")
+	}
+	if m.ProbeType != old.ProbeType || m.probeDB != old.probeDB {
+		fields = append(fields, "probe")
+	}
+	if m.SustainReadings != old.SustainReadings || m.SustainPeriod != old.SustainPeriod ||
+		m.SustainPeriodType != old.SustainPeriodType {
+		fields = append(fields, "sustain")
+	}
+	if m.FlapTransitions != old.FlapTransitions || m.FlapWindow != old.FlapWindow ||
+		m.FlapWindowType != old.FlapWindowType {
+		fields = append(fields, "flapping")
+	}
+	if triggersDiffer(m.Triggers, old.Triggers) {
+		fields = append(fields, "triggers")
+	}
+	if labelsDiffer(m.Labels, old.Labels) {
+		fields = append(fields, "labels")
+	}
+	return
+}
+
+func labelsDiffer(a, b []*MonitorLabel) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// unredact fills in the redacted secrets of m's triggers from those of old, the
+// monitor m replaces, or nil if m is new.
+func (m *Monitor) unredact(old *vm.Monitor) ([]string, error) {
+	var oldTriggers []*vm.Trigger
+	if old != nil {
+		for _, mt := range old.Triggers {
+			oldTriggers = append(oldTriggers, mt.Trigger)
+		}
+	}
+	return unredactTriggers(m.Triggers, oldTriggers, fmt.Sprintf("monitor %q", m.Name))
+}
+
+// toVM converts m to a monitor that can be validated and saved. old is the
+// monitor being replaced, or nil if m is new. Labels are resolved to IDs with
+// labelIDs; if labelIDs is nil, the monitor's labels are left alone.
+func (m *Monitor) toVM(old *vm.Monitor, labelIDs map[string]db.LabelID) (*vm.Monitor, error) {
+	probeType, _ := probeTypeByName(m.ProbeType)
+	monitor := &vm.Monitor{
+		Name:        m.Name,
+		Owner:       m.Owner,
+		Description: m.Description,
+		Response:    m.Response,
+		ProbeType:   probeType.Id(),
+		ProbeParams: string(m.Probe),
+
+		SustainReadings:   m.SustainReadings,
+		SustainPeriod:     m.SustainPeriod,
+		SustainPeriodType: m.SustainPeriodType,
+
+		FlapTransitions: m.FlapTransitions,
+		FlapWindow:      m.FlapWindow,
+		FlapWindowType:  m.FlapWindowType,
+	}
+
+	var oldTriggers []*vm.Trigger
+	if old != nil {
+		monitor.MonitorID = old.MonitorID
+		monitor.Changed = old.Changed
+		monitor.Version = old.Version
+		monitor.Archived = old.Archived
+
+		for _, mt := range old.Triggers {
+			oldTriggers = append(oldTriggers, mt.Trigger)
+		}
+	}
+
+	triggers, deleted, err := mergeTriggers(m.Triggers, oldTriggers)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, t := range triggers {
+		monitor.Triggers = append(monitor.Triggers, &vm.MonitorTrigger{
+			Trigger:   t,
+			MonitorID: monitor.MonitorID,
+			Subprobes: m.Triggers[i].Subprobes,
+		})
+	}
+	for _, t := range deleted {
+		monitor.Triggers = append(monitor.Triggers, &vm.MonitorTrigger{
+			Trigger:   t,
+			MonitorID: monitor.MonitorID,
+		})
+	}
+
+	if labelIDs == nil {
+		return monitor, nil
+	}
+
+	attached := make(map[db.LabelID]*vm.MonitorLabel)
+	if old != nil {
+		for _, ml := range old.Labels {
+			attached[ml.Label.LabelID] = ml
+		}
+	}
+	for _, ml := range m.Labels {
+		id, ok := labelIDs[ml.Name]
+		if !ok {
+			return nil, errors.Errorf("Unknown label %q", ml.Name)
+		}
+		_, isAttached := attached[id]
+		delete(attached, id)
+		monitor.Labels = append(monitor.Labels, &vm.MonitorLabel{
+			Label:     &vm.Label{LabelID: id},
+			MonitorID: monitor.MonitorID,
+			Subprobes: ml.Subprobes,
+			Create:    !isAttached,
+		})
+	}
+	if old != nil {
+		for _, ml := range old.Labels {
+			if _, ok := attached[ml.Label.LabelID]; ok {
+				ml.Delete = true
+				monitor.Labels = append(monitor.Labels, ml)
+			}
+		}
+	}
+
+	return monitor, nil
+}
+
+func probeTypeByName(name string) (probe.VMType, bool) {
+	for _, t := range probe.AllTypes() {
+		if strings.EqualFold(name, t.Name()) {
+			return t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,285 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+)
+
+// Plan is the set of changes needed to make the DB match a manifest.
+type Plan struct {
+	labels    []*labelChange
+	monitors  []*monitorChange
+	unchanged int
+
+	// unmanaged lists the monitors in the DB that are not in the manifest,
+	// which applying the plan leaves alone.
+	unmanaged []string
+
+	// labelIDs holds the IDs of the labels already in the DB by name.
+	labelIDs map[string]db.LabelID
+}
+
+// labelChange creates label, or updates old to match it if old is set.
+type labelChange struct {
+	label  *Label
+	old    *vm.Label
+	fields []string
+}
+
+// monitorChange creates monitor, or updates old to match it if old is set.
+type monitorChange struct {
+	monitor *Monitor
+	old     *vm.Monitor
+	fields  []string
+}
+
+// ValidationErrors lists the problems that keep a manifest from being applied.
+type ValidationErrors []string
+
+func (errs ValidationErrors) Error() string {
+	return "invalid manifest:\n  " + strings.Join(errs, "\n  ")
+}
+
+// NewPlan compares m to the DB and works out what applying it would change.
+// It returns ValidationErrors if m is invalid.
+func NewPlan(DB *db.DB, m *Manifest) (*Plan, error) {
+	errs := m.normalize()
+	if errs != nil {
+		return nil, ValidationErrors(errs)
+	}
+
+	monitors, labels, err := load(DB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	oldLabels := make(map[string][]*vm.Label)
+	for _, l := range labels {
+		oldLabels[l.Name] = append(oldLabels[l.Name], l)
+	}
+	oldMonitors := make(map[string][]*vm.Monitor)
+	for _, monitor := range monitors {
+		oldMonitors[monitor.Name] = append(oldMonitors[monitor.Name], monitor)
+	}
+
+	p := &Plan{labelIDs: make(map[string]db.LabelID)}
+	for name, ls := range oldLabels {
+		if len(ls) == 1 {
+			p.labelIDs[name] = ls[0].LabelID
+		}
+	}
+
+	inManifest := make(map[string]bool)
+	for _, l := range m.Labels {
+		inManifest[l.Name] = true
+		olds := oldLabels[l.Name]
+		if len(olds) > 1 {
+			errs = append(errs, fmt.Sprintf("There is more than one label named %q.", l.Name))
+			continue
+		}
+
+		change := &labelChange{label: l}
+		if len(olds) == 1 {
+			change.old = olds[0]
+		}
+		unredactErrs, err := l.unredact(change.old)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if unredactErrs != nil {
+			errs = append(errs, unredactErrs...)
+			continue
+		}
+		if change.old != nil {
+			old, err := newLabel(change.old)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			change.fields = l.diff(old)
+			if change.fields == nil {
+				p.unchanged++
+				continue
+			}
+		}
+
+		errs = append(errs, validate(DB, change)...)
+		p.labels = append(p.labels, change)
+	}
+
+	for _, monitor := range m.Monitors {
+		for _, ml := range monitor.Labels {
+			if _, ok := p.labelIDs[ml.Name]; !ok && !inManifest[ml.Name] {
+				errs = append(errs, fmt.Sprintf("Unknown label %q for monitor %q.", ml.Name, monitor.Name))
+			}
+		}
+
+		olds := oldMonitors[monitor.Name]
+		delete(oldMonitors, monitor.Name)
+		if len(olds) > 1 {
+			errs = append(errs, fmt.Sprintf("There is more than one monitor named %q.", monitor.Name))
+			continue
+		}
+
+		change := &monitorChange{monitor: monitor}
+		if len(olds) == 1 {
+			change.old = olds[0]
+		}
+		unredactErrs, err := monitor.unredact(change.old)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if unredactErrs != nil {
+			errs = append(errs, unredactErrs...)
+			continue
+		}
+		if change.old != nil {
+			old, err := newMonitor(change.old)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			change.fields = monitor.diff(old)
+			if change.fields == nil {
+				p.unchanged++
+				continue
+			}
+		}
+
+		errs = append(errs, validate(DB, change)...)
+		p.monitors = append(p.monitors, change)
+	}
+	if errs != nil {
+		return nil, ValidationErrors(errs)
+	}
+
+	for _, monitor := range monitors {
+		if _, ok := oldMonitors[monitor.Name]; ok {
+			p.unmanaged = append(p.unmanaged, monitor.Name)
+		}
+	}
+	return p, nil
+}
+
+// validate checks a change the same way the web UI checks saves.
+func validate(DB *db.DB, change interface{}) (errs []string) {
+	var in string
+	switch c := change.(type) {
+	case *labelChange:
+		in = fmt.Sprintf("label %q", c.label.Name)
+		l, err := c.label.toVM(c.old)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %s", in, err.Error())}
+		}
+		errs = l.Validate(DB)
+	case *monitorChange:
+		in = fmt.Sprintf("monitor %q", c.monitor.Name)
+		// Labels are checked by NewPlan, since they may not have been
+		// created yet.
+		m, err := c.monitor.toVM(c.old, nil)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %s", in, err.Error())}
+		}
+		errs = m.Validate(DB)
+	}
+
+	for i, err := range errs {
+		errs[i] = fmt.Sprintf("%s: %s", in, err)
+	}
+	return errs
+}
+
+// IsEmpty returns whether applying p would change nothing.
+func (p *Plan) IsEmpty() bool {
+	return len(p.labels) == 0 && len(p.monitors) == 0
+}
+
+// String describes the changes in p, one per line, with a summary at the end.
+func (p *Plan) String() string {
+	var b strings.Builder
+	var creates, updates int
+	describe := func(kind, name string, isCreate bool, fields []string) {
+		if isCreate {
+			creates++
+			fmt.Fprintf(&b, "+ %s %q\n", kind, name)
+		} else {
+			updates++
+			fmt.Fprintf(&b, "~ %s %q (%s)\n", kind, name, strings.Join(fields, ", "))
+		}
+	}
+	for _, c := range p.labels {
+		describe("label", c.label.Name, c.old == nil, c.fields)
+	}
+	for _, c := range p.monitors {
+		describe("monitor", c.monitor.Name, c.old == nil, c.fields)
+	}
+
+	if len(p.unmanaged) > 0 {
+		fmt.Fprintf(&b, "Not in the manifest, left alone: %s\n", strings.Join(p.unmanaged, ", "))
+	}
+	if p.IsEmpty() {
+		fmt.Fprintf(&b, "No changes. %d unchanged.\n", p.unchanged)
+	} else {
+		fmt.Fprintf(&b, "%d to create, %d to update, %d unchanged.\n", creates, updates, p.unchanged)
+	}
+	return b.String()
+}
+
+// Apply makes the changes in p in a single transaction. Saving a monitor bumps
+// its version, as does changing a label it has, so that the daemon reloads it.
+// If saved is not nil, it is called in the transaction after each label and
+// monitor is saved.
+func (p *Plan) Apply(DB *db.DB, saved func(*db.Tx, vm.NamedComponent) error) error {
+	return DB.Tx(func(tx *db.Tx) error {
+		labelIDs := make(map[string]db.LabelID)
+		for name, id := range p.labelIDs {
+			labelIDs[name] = id
+		}
+
+		for _, c := range p.labels {
+			l, err := c.label.toVM(c.old)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = l.Save(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			labelIDs[l.Name] = l.LabelID
+			if saved != nil {
+				err = saved(tx, l)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+
+			if c.old != nil {
+				err = tx.BumpMonitorVersionsForLabel(l.LabelID)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+
+		for _, c := range p.monitors {
+			m, err := c.monitor.toVM(c.old, labelIDs)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = m.Save(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if saved != nil {
+				err = saved(tx, m)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+		return nil
+	})
+}
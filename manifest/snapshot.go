@@ -0,0 +1,66 @@
+package manifest
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+)
+
+// MonitorSnapshot returns m in manifest form. Package history keeps snapshots
+// of monitors in this form: since it names labels rather than giving their
+// IDs, and triggers are matched by position, a snapshot can still be restored
+// after triggers and labels have come and gone. Target secrets are redacted.
+func MonitorSnapshot(m *vm.Monitor) (*Monitor, error) {
+	return newMonitor(m)
+}
+
+// LabelSnapshot returns l in manifest form.
+func LabelSnapshot(l *vm.Label) (*Label, error) {
+	return newLabel(l)
+}
+
+// RestoreMonitor returns current changed to match snapshot, a monitor in
+// manifest form as JSON, ready to be validated and saved. Redacted secrets are
+// kept from the triggers current has in their place. It returns
+// ValidationErrors if snapshot cannot be restored.
+func RestoreMonitor(tx *db.Tx, snapshot []byte, current *vm.Monitor) (*vm.Monitor, error) {
+	labels, err := vm.AllLabels(tx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	labelIDs := make(map[string]db.LabelID)
+	for _, l := range labels {
+		labelIDs[l.Name] = l.LabelID
+	}
+	return restoreMonitor(snapshot, current, labelIDs)
+}
+
+func restoreMonitor(snapshot []byte, current *vm.Monitor, labelIDs map[string]db.LabelID) (*vm.Monitor, error) {
+	var m Monitor
+	err := json.Unmarshal(snapshot, &m)
+	if err != nil {
+		return nil, errors.Maskf(err, "parse snapshot")
+	}
+
+	errs := m.normalize()
+	if errs != nil {
+		return nil, ValidationErrors(errs)
+	}
+	errs, err = m.unredact(current)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if errs != nil {
+		return nil, ValidationErrors(errs)
+	}
+	monitor, err := m.toVM(current, labelIDs)
+	if err != nil {
+		// The snapshot refers to labels or triggers that are no longer valid.
+		return nil, ValidationErrors{err.Error()}
+	}
+	return monitor, nil
+}
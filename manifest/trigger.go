@@ -0,0 +1,262 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+	"github.com/yext/revere/util"
+	"github.com/yext/revere/web/vm"
+)
+
+// Trigger is a trigger of a monitor or a label. Subprobes only applies to
+// monitor triggers; label triggers apply to the subprobes the label does.
+type Trigger struct {
+	Subprobes       string          `json:"subprobes,omitempty"`
+	Level           string          `json:"level"`
+	Period          int64           `json:"period"`
+	PeriodType      string          `json:"periodType"`
+	TriggerOnExit   bool            `json:"triggerOnExit,omitempty"`
+	GroupWindow     int64           `json:"groupWindow,omitempty"`
+	GroupWindowType string          `json:"groupWindowType,omitempty"`
+	Digest          int64           `json:"digest,omitempty"`
+	DigestType      string          `json:"digestType,omitempty"`
+	TargetType      string          `json:"targetType"`
+	Target          json.RawMessage `json:"target"`
+
+	// targetDB is the target as it is stored, which is what triggers are
+	// compared by.
+	targetDB string
+}
+
+var levels = []state.State{state.Normal, state.Warning, state.Unknown, state.Error, state.Critical}
+
+// newTrigger returns t in manifest form. Its target's secrets are redacted, as
+// manifests are kept in version control and snapshots in the history; they are
+// compared by the secrets as they are stored.
+func newTrigger(t *vm.Trigger, subprobes string) (*Trigger, error) {
+	params, err := json.Marshal(target.Redact(t.Target))
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize target of trigger %d", t.TriggerID)
+	}
+	targetDB, err := t.Target.Serialize()
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize target of trigger %d", t.TriggerID)
+	}
+
+	trigger := &Trigger{
+		Subprobes:     subprobes,
+		Level:         t.Level.String(),
+		TriggerOnExit: t.TriggerOnExit,
+		TargetType:    t.Target.Name(),
+		Target:        json.RawMessage(params),
+		targetDB:      canonicalJSON(targetDB),
+	}
+	trigger.Period, trigger.PeriodType = normalizePeriod(int64(t.Period), t.PeriodType)
+	trigger.GroupWindow, trigger.GroupWindowType = normalizePeriod(t.GroupWindow, t.GroupWindowType)
+	trigger.Digest, trigger.DigestType = normalizePeriod(t.Digest, t.DigestType)
+	return trigger, nil
+}
+
+// normalize puts t in the same form as triggers loaded from the DB, so that
+// they can be compared.
+func (t *Trigger) normalize(in string) (errs []string) {
+	level, ok := parseLevel(t.Level)
+	if !ok {
+		errs = append(errs, fmt.Sprintf("Invalid level for trigger of %s: %q", in, t.Level))
+	} else {
+		t.Level = level.String()
+	}
+
+	errs = append(errs, checkPeriod("trigger period", in, t.Period, t.PeriodType)...)
+	errs = append(errs, checkPeriod("trigger grouping window", in, t.GroupWindow, t.GroupWindowType)...)
+	errs = append(errs, checkPeriod("trigger digest period", in, t.Digest, t.DigestType)...)
+	t.Period, t.PeriodType = normalizePeriod(t.Period, t.PeriodType)
+	t.GroupWindow, t.GroupWindowType = normalizePeriod(t.GroupWindow, t.GroupWindowType)
+	t.Digest, t.DigestType = normalizePeriod(t.Digest, t.DigestType)
+
+	targetType, ok := targetTypeByName(t.TargetType)
+	if !ok {
+		return append(errs, fmt.Sprintf("Unknown target type for trigger of %s: %q", in, t.TargetType))
+	}
+	t.TargetType = targetType.Name()
+
+	loaded, err := target.LoadFromParams(targetType.Id(), string(t.Target))
+	if err != nil {
+		return append(errs, fmt.Sprintf("Unable to load target for trigger of %s: %s", in, err.Error()))
+	}
+	targetDB, err := loaded.Serialize()
+	if err != nil {
+		return append(errs, fmt.Sprintf("Unable to load target for trigger of %s: %s", in, err.Error()))
+	}
+	t.targetDB = canonicalJSON(targetDB)
+	return
+}
+
+// unredact fills in the secrets of t's target that are redacted, as they are
+// in exports and snapshots, from old, the trigger t replaces, or nil if t is
+// new. t must already be normalized.
+func (t *Trigger) unredact(old *vm.Trigger) (bool, error) {
+	targetType, ok := targetTypeByName(t.TargetType)
+	if !ok {
+		// normalize reports the unknown target type.
+		return true, nil
+	}
+	loaded, err := target.LoadFromParams(targetType.Id(), string(t.Target))
+	if err != nil {
+		return true, nil
+	}
+
+	var oldTarget target.VM
+	if old != nil {
+		oldTarget = old.Target
+	}
+	loaded, ok = target.Unredact(loaded, oldTarget)
+	if !ok {
+		return false, nil
+	}
+
+	params, err := json.Marshal(loaded)
+	if err != nil {
+		return false, errors.Maskf(err, "serialize target")
+	}
+	targetDB, err := loaded.Serialize()
+	if err != nil {
+		return false, errors.Maskf(err, "serialize target")
+	}
+	t.Target = json.RawMessage(params)
+	t.targetDB = canonicalJSON(targetDB)
+	return true, nil
+}
+
+// unredactTriggers fills in the secrets of triggers that are redacted from the
+// old triggers they are paired with by position. It returns why if a
+// trigger's secrets cannot be filled in, because the trigger in its place has
+// a different target type or there is none.
+func unredactTriggers(triggers []*Trigger, old []*vm.Trigger, in string) (errs []string, err error) {
+	for i, t := range triggers {
+		var o *vm.Trigger
+		if i < len(old) {
+			o = old[i]
+		}
+		ok, err := t.unredact(o)
+		if err != nil {
+			return nil, errors.Maskf(err, "trigger %d of %s", i+1, in)
+		}
+		if !ok {
+			errs = append(errs, fmt.Sprintf(
+				"Trigger %d of %s has redacted secrets, and there is no %s trigger in its place to keep them from.",
+				i+1, in, t.TargetType))
+		}
+	}
+	return errs, nil
+}
+
+// key identifies what t does, so that triggers can be compared.
+func (t *Trigger) key() string {
+	c := *t
+	c.Target = json.RawMessage(t.targetDB)
+	key, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return string(key)
+}
+
+func (t *Trigger) toVM(id db.TriggerID) *vm.Trigger {
+	level, _ := parseLevel(t.Level)
+	targetType, _ := targetTypeByName(t.TargetType)
+
+	return &vm.Trigger{
+		TriggerID:       id,
+		Level:           level,
+		LevelText:       level.String(),
+		Period:          int32(t.Period),
+		PeriodType:      t.PeriodType,
+		TriggerOnExit:   t.TriggerOnExit,
+		GroupWindow:     t.GroupWindow,
+		GroupWindowType: t.GroupWindowType,
+		Digest:          t.Digest,
+		DigestType:      t.DigestType,
+		TargetType:      targetType.Id(),
+		TargetParams:    string(t.Target),
+	}
+}
+
+// mergeTriggers pairs new triggers with old ones by position, so that a
+// changed trigger keeps its ID. The old triggers left over are returned
+// marked for deletion.
+func mergeTriggers(triggers []*Trigger, old []*vm.Trigger) ([]*vm.Trigger, []*vm.Trigger, error) {
+	merged := make([]*vm.Trigger, len(triggers))
+	for i, t := range triggers {
+		var id db.TriggerID
+		if i < len(old) {
+			id = old[i].TriggerID
+		}
+		merged[i] = t.toVM(id)
+	}
+
+	var deleted []*vm.Trigger
+	for i := len(triggers); i < len(old); i++ {
+		t := old[i]
+		params, err := json.Marshal(t.Target)
+		if err != nil {
+			return nil, nil, errors.Maskf(err, "serialize target of trigger %d", t.TriggerID)
+		}
+		t.LevelText = t.Level.String()
+		t.TargetParams = string(params)
+		t.Delete = true
+		deleted = append(deleted, t)
+	}
+	return merged, deleted, nil
+}
+
+func triggersDiffer(a, b []*Trigger) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for i := range a {
+		if a[i].key() != b[i].key() {
+			return true
+		}
+	}
+	return false
+}
+
+func parseLevel(s string) (state.State, bool) {
+	for _, l := range levels {
+		if strings.EqualFold(s, l.String()) {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+func targetTypeByName(name string) (target.VMType, bool) {
+	for _, t := range target.AllTargets() {
+		if strings.EqualFold(name, t.Name()) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// normalizePeriod expresses a period in the largest unit that divides it, as
+// periods loaded from the DB are.
+func normalizePeriod(period int64, periodType string) (int64, string) {
+	return util.GetPeriodAndType(util.GetMs(period, periodType))
+}
+
+// checkPeriod reports periods whose type is not a known unit, as they would
+// otherwise be normalized to nothing.
+func checkPeriod(what, in string, period int64, periodType string) []string {
+	if period != 0 && util.GetMs(period, periodType) == 0 {
+		return []string{fmt.Sprintf("Invalid %s for %s: %d %s", what, in, period, periodType)}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Package metrics exposes Revere's own health as Prometheus metrics, so that
+Revere can itself be monitored, for example to notice when the daemon stops
+evaluating monitors.
+
+Metrics are registered with the default Prometheus registry and served by
+Handler, which the web mode serves at /metrics to logged-in viewers. Processes
+that only run the daemon, or that must be scraped without logging in, can serve
+them on a port of their own with Start.
+*/
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+const namespace = "revere"
+
+var (
+	// ProbeCheckDuration is how long probes take to check what they
+	// monitor, per monitor ID.
+	ProbeCheckDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "probe_check_duration_seconds",
+		Help:      "How long probe checks take, per monitor.",
+		Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"monitor"})
+
+	// ProbeCheckErrors counts probe checks that could not tell the state of
+	// what they monitor, per monitor ID.
+	ProbeCheckErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "probe_check_errors_total",
+		Help:      "Probe checks that read Unknown, per monitor.",
+	}, []string{"monitor"})
+
+	// Readings counts the readings monitors process, per state.
+	Readings = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "readings_total",
+		Help:      "Readings processed, per state.",
+	}, []string{"state"})
+
+	// Alerts counts alerts sent to targets, per target type and whether
+	// sending succeeded ("sent") or not ("failed").
+	Alerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_total",
+		Help:      "Alerts sent to targets, per target type and result.",
+	}, []string{"target_type", "result"})
+
+	// DBTxDuration is how long database transactions take.
+	DBTxDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_tx_duration_seconds",
+		Help:      "How long database transactions take.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// RunningMonitors is the number of monitors the daemon is running.
+	RunningMonitors = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "running_monitors",
+		Help:      "Monitors the daemon is running.",
+	})
+
+	// MonitorLoadFailures counts the times the daemon could not load a
+	// monitor it was starting or reloading, or the list of monitors to
+	// reload.
+	MonitorLoadFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "monitor_load_failures_total",
+		Help:      "Failures loading monitors to start or reload.",
+	})
+)
+
+// Monitor returns the label value identifying a monitor.
+func Monitor(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// Handler returns a handler serving the metrics in Prometheus's text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Start serves the metrics at /metrics on port until the returned server is
+// closed.
+func Start(port uint16) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	s := &http.Server{Addr: ":" + strconv.Itoa(int(port)), Handler: mux}
+	go func() {
+		log.Info("Serving metrics on :", port)
+		err := s.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	return s
+}
@@ -0,0 +1,134 @@
+package probe
+
+import (
+	"math"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/resource"
+)
+
+// Modes for Graphite threshold probes.
+const (
+	graphiteModeThreshold = "threshold"
+	graphiteModeAnomaly   = "anomaly"
+)
+
+// Baselines for anomaly mode.
+const (
+	// anomalyBaselineShift compares the audited window against the same
+	// window shifted into the past, e.g. by a day or a week.
+	anomalyBaselineShift = "shift"
+	// anomalyBaselineRolling compares the audited window against the mean
+	// of a window immediately preceding it.
+	anomalyBaselineRolling = "rolling"
+)
+
+// anomaly configures how a Graphite threshold probe in anomaly mode computes
+// the baseline its audited values are compared against. In anomaly mode,
+// thresholds are multiples of the baseline's standard deviation.
+type anomaly struct {
+	baseline string
+	shift    time.Duration
+	window   time.Duration
+}
+
+func newAnomaly(config GraphiteThresholdAnomalyDBModel) (*anomaly, error) {
+	a := anomaly{
+		baseline: config.Baseline,
+		shift:    time.Duration(config.ShiftMilli) * time.Millisecond,
+		window:   time.Duration(config.WindowMilli) * time.Millisecond,
+	}
+
+	switch a.baseline {
+	case anomalyBaselineShift:
+		if a.shift <= 0 {
+			return nil, errors.Errorf("nonpositive anomaly shift %s", a.shift)
+		}
+	case anomalyBaselineRolling:
+		if a.window <= 0 {
+			return nil, errors.Errorf("nonpositive anomaly window %s", a.window)
+		}
+	default:
+		return nil, errors.Errorf("unknown anomaly baseline: %s", a.baseline)
+	}
+
+	return &a, nil
+}
+
+// query fetches the baseline series for the window from auditStart to
+// auditEnd, keyed by series name.
+func (a *anomaly) query(g resource.GraphiteDaemon, expression string, auditStart, auditEnd time.Time) (map[string]resource.GraphiteSeries, error) {
+	var from, until time.Time
+	switch a.baseline {
+	case anomalyBaselineShift:
+		from, until = auditStart.Add(-a.shift), auditEnd.Add(-a.shift)
+	case anomalyBaselineRolling:
+		from, until = auditStart.Add(-a.window), auditStart
+	}
+
+	series, err := g.Query(expression, from, until)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	baselines := make(map[string]resource.GraphiteSeries, len(series))
+	for _, s := range series {
+		baselines[s.Name] = s
+	}
+	return baselines, nil
+}
+
+// center returns the value the audited summary value is expected to be near,
+// given the baseline series.
+func (a *anomaly) center(baseline resource.GraphiteSeries, summarize auditFunction) float64 {
+	if a.baseline == anomalyBaselineShift {
+		return summarize(baseline.Values, baseline.Step)
+	}
+	return auditFunctions["avg"](baseline.Values, baseline.Step)
+}
+
+// deviation returns how many standard deviations measured is from center. A
+// zero standard deviation makes any difference infinitely large.
+func deviation(measured, center, stddev float64) float64 {
+	diff := measured - center
+	if diff == 0 {
+		return 0
+	}
+	return diff / stddev
+}
+
+// stddev returns the population standard deviation of the non-NaN values, or
+// NaN if there are none.
+func stddev(values []float64) float64 {
+	mean := auditFunctions["avg"](values, 0)
+	if math.IsNaN(mean) {
+		return math.NaN()
+	}
+
+	sum := float64(0)
+	count := 0
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			sum += (value - mean) * (value - mean)
+			count++
+		}
+	}
+	return math.Sqrt(sum / float64(count))
+}
+
+// anomalyThresholds converts thresholds expressed as positive deviation
+// multiples into signed deviations to compare against, so that "<" and "<="
+// trigger when values fall below the baseline band.
+func anomalyThresholds(thresholds []threshold, triggerIf string) []threshold {
+	if triggerIf != "<" && triggerIf != "<=" {
+		return thresholds
+	}
+
+	negated := make([]threshold, len(thresholds))
+	for i, t := range thresholds {
+		negated[i] = threshold{t.state, -t.threshold}
+	}
+	return negated
+}
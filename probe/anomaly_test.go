@@ -0,0 +1,173 @@
+package probe
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/resource"
+	"github.com/yext/revere/state"
+)
+
+var nan = math.NaN()
+
+// sameFloat returns whether a and b are equal, treating NaNs as equal.
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestNewAnomaly(t *testing.T) {
+	cases := []struct {
+		config GraphiteThresholdAnomalyDBModel
+		valid  bool
+	}{
+		{GraphiteThresholdAnomalyDBModel{Baseline: anomalyBaselineShift, ShiftMilli: 1000}, true},
+		{GraphiteThresholdAnomalyDBModel{Baseline: anomalyBaselineShift}, false},
+		{GraphiteThresholdAnomalyDBModel{Baseline: anomalyBaselineRolling, WindowMilli: 1000}, true},
+		{GraphiteThresholdAnomalyDBModel{Baseline: anomalyBaselineRolling, ShiftMilli: 1000}, false},
+		{GraphiteThresholdAnomalyDBModel{Baseline: "median", ShiftMilli: 1000, WindowMilli: 1000}, false},
+	}
+
+	for _, c := range cases {
+		_, err := newAnomaly(c.config)
+		if (err == nil) != c.valid {
+			t.Errorf("Expected %+v valid: %t, got error %v\n", c.config, c.valid, err)
+		}
+	}
+}
+
+func TestAnomalyCenter(t *testing.T) {
+	cases := []struct {
+		baseline string
+		audit    string
+		values   []float64
+		expected float64
+	}{
+		{anomalyBaselineShift, "max", []float64{1, 5, 3}, 5},
+		{anomalyBaselineShift, "avg", []float64{1, nan, 3}, 2},
+		{anomalyBaselineShift, "last", []float64{1, 4, nan}, 4},
+		{anomalyBaselineRolling, "max", []float64{1, 5, 3}, 3},
+		{anomalyBaselineRolling, "avg", []float64{nan, 2, nan, 4}, 3},
+		{anomalyBaselineShift, "avg", []float64{nan, nan}, nan},
+		{anomalyBaselineRolling, "max", []float64{}, nan},
+	}
+
+	for _, c := range cases {
+		a := &anomaly{baseline: c.baseline}
+		baseline := resource.GraphiteSeries{Step: time.Minute, Values: c.values}
+		if center := a.center(baseline, auditFunctions[c.audit]); !sameFloat(center, c.expected) {
+			t.Errorf("Expected %s %s center of %v: %v, got %v\n", c.baseline, c.audit, c.values, c.expected, center)
+		}
+	}
+}
+
+func TestStddev(t *testing.T) {
+	cases := []struct {
+		values   []float64
+		expected float64
+	}{
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 2},
+		{[]float64{2, nan, 4, 4, 4, 5, nan, 5, 7, 9}, 2},
+		{[]float64{3, 3, 3}, 0},
+		{[]float64{3}, 0},
+		{[]float64{nan, 3, nan}, 0},
+		{[]float64{nan, nan}, nan},
+		{nil, nan},
+	}
+
+	for _, c := range cases {
+		if s := stddev(c.values); !sameFloat(s, c.expected) {
+			t.Errorf("Expected standard deviation of %v: %v, got %v\n", c.values, c.expected, s)
+		}
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	cases := []struct {
+		measured, center, stddev float64
+		expected                 float64
+	}{
+		{14, 10, 2, 2},
+		{7, 10, 2, -1.5},
+		{10, 10, 2, 0},
+		{10, 10, 0, 0},
+		{11, 10, 0, math.Inf(1)},
+		{9, 10, 0, math.Inf(-1)},
+	}
+
+	for _, c := range cases {
+		if d := deviation(c.measured, c.center, c.stddev); !sameFloat(d, c.expected) {
+			t.Errorf("Expected deviation of %v from %v with stddev %v: %v, got %v\n",
+				c.measured, c.center, c.stddev, c.expected, d)
+		}
+	}
+}
+
+func TestAnomalyThresholds(t *testing.T) {
+	thresholds := []threshold{{state.Warning, 2}, {state.Error, 3}}
+
+	for _, triggerIf := range []string{">", ">="} {
+		if actual := anomalyThresholds(thresholds, triggerIf); actual[0].threshold != 2 || actual[1].threshold != 3 {
+			t.Errorf("Expected thresholds unchanged for %s, got %v\n", triggerIf, actual)
+		}
+	}
+	for _, triggerIf := range []string{"<", "<="} {
+		if actual := anomalyThresholds(thresholds, triggerIf); actual[0].threshold != -2 || actual[1].threshold != -3 {
+			t.Errorf("Expected thresholds negated for %s, got %v\n", triggerIf, actual)
+		}
+	}
+	if thresholds[0].threshold != 2 {
+		t.Errorf("Expected the probe's thresholds unchanged, got %v\n", thresholds)
+	}
+}
+
+func TestCheckAnomaly(t *testing.T) {
+	// The baseline's mean is 5 and its standard deviation is 2.
+	baseline := resource.GraphiteSeries{Step: time.Minute, Values: []float64{2, 4, nan, 4, 4, 5, 5, nan, 7, 9}}
+	flat := resource.GraphiteSeries{Step: time.Minute, Values: []float64{5, nan, 5}}
+	missing := resource.GraphiteSeries{Step: time.Minute, Values: []float64{nan, nan}}
+
+	cases := []struct {
+		triggerIf string
+		baseline  resource.GraphiteSeries
+		measured  float64
+		expected  state.State
+	}{
+		{">", baseline, 8, state.Normal},
+		{">", baseline, 9.5, state.Warning},
+		{">", baseline, 11.5, state.Error},
+		{">=", baseline, 9, state.Warning},
+		{">", baseline, 1, state.Normal},
+		{"<", baseline, 1, state.Normal},
+		{"<", baseline, 0.5, state.Warning},
+		{"<=", baseline, 1, state.Warning},
+		{"<", baseline, -1.5, state.Error},
+		{"<", baseline, 9.5, state.Normal},
+		{">", flat, 5, state.Normal},
+		{">", flat, 5.1, state.Error},
+		{"<", flat, 4.9, state.Error},
+		{">", missing, 5, state.Unknown},
+		{">", resource.GraphiteSeries{}, 5, state.Unknown},
+	}
+
+	for _, c := range cases {
+		gt := &GraphiteThreshold{
+			anomaly:         &anomaly{baseline: anomalyBaselineRolling, window: time.Hour},
+			summarizeValues: auditFunctions["avg"],
+		}
+		thresholds := anomalyThresholds([]threshold{{state.Warning, 2}, {state.Error, 3}}, c.triggerIf)
+
+		d := gt.checkAnomaly(c.baseline, thresholds, c.measured)
+		s := state.Unknown
+		if !math.IsNaN(d.center) && !math.IsNaN(d.stddev) {
+			s, _ = evaluateThresholds(thresholds, triggerIfFunctions[c.triggerIf], d.deviation)
+		}
+		if s != c.expected {
+			t.Errorf("Expected %v %s baseline %v: %s, got %s (deviation %v)\n",
+				c.measured, c.triggerIf, c.baseline.Values, c.expected, s, d.deviation)
+		}
+		if d.bandMultiple != 2 {
+			t.Errorf("Expected band multiple 2, got %v\n", d.bandMultiple)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package probe
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+// Composite implements a probe whose readings are derived from the current
+// subprobe statuses of other monitors.
+type Composite struct {
+	*Polling
+
+	db    *db.DB
+	rules []compositeRule
+}
+
+type compositeRule struct {
+	subprobe    string
+	inputs      []compositeInput
+	inputState  state.State
+	minCount    int
+	resultState state.State
+}
+
+type compositeInput struct {
+	monitorID db.MonitorID
+	subprobes *regexp.Regexp
+}
+
+func newComposite(m MonitorInfo, configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	c := Composite{db: m.DB}
+
+	var config CompositeDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize probe config")
+	}
+
+	checkPeriod := time.Duration(config.CheckPeriodMilli) * time.Millisecond
+	c.Polling, err = NewPolling(checkPeriod, &c, readingsSink)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	if len(config.Rules) == 0 {
+		return nil, errors.New("no rules")
+	}
+	for i, r := range config.Rules {
+		if r.MinCount < 1 || r.MinCount > len(r.Inputs) {
+			return nil, errors.Errorf("invalid minimum count %d for rule %d", r.MinCount, i)
+		}
+		rule := compositeRule{
+			subprobe:    r.Subprobe,
+			inputState:  r.InputState,
+			minCount:    r.MinCount,
+			resultState: r.ResultState,
+		}
+		for _, in := range r.Inputs {
+			subprobes, err := regexp.Compile(in.Subprobes)
+			if err != nil {
+				return nil, errors.Maskf(err, "compile regexp for rule %d", i)
+			}
+			rule.inputs = append(rule.inputs, compositeInput{in.MonitorID, subprobes})
+		}
+		c.rules = append(c.rules, rule)
+	}
+
+	return &c, nil
+}
+
+func (c *Composite) Check() []Reading {
+	now := time.Now()
+
+	statuses := make(map[db.MonitorID]map[string]db.SubprobeStatus)
+	loadErrs := make(map[db.MonitorID]error)
+	for _, rule := range c.rules {
+		for _, in := range rule.inputs {
+			if _, loaded := statuses[in.monitorID]; loaded {
+				continue
+			}
+			if _, failed := loadErrs[in.monitorID]; failed {
+				continue
+			}
+			s, err := c.db.LoadSubprobeStatusesForMonitor(in.monitorID)
+			if err != nil {
+				log.WithError(err).WithField("monitor", in.monitorID).
+					Error("Could not load subprobe statuses for composite probe.")
+				loadErrs[in.monitorID] = err
+				continue
+			}
+			statuses[in.monitorID] = s
+		}
+	}
+
+	var order []string
+	details := make(map[string]*compositeDetails)
+	for _, rule := range c.rules {
+		d, ok := details[rule.subprobe]
+		if !ok {
+			d = &compositeDetails{state: state.Normal}
+			details[rule.subprobe] = d
+			order = append(order, rule.subprobe)
+		}
+		d.rules = append(d.rules, rule.evaluate(statuses, loadErrs))
+	}
+
+	readings := make([]Reading, 0, len(order))
+	for _, subprobe := range order {
+		d := details[subprobe]
+		for _, r := range d.rules {
+			d.state = worseState(d.state, r.state)
+		}
+		readings = append(readings, Reading{
+			Subprobe: subprobe,
+			State:    d.state,
+			Recorded: now,
+			Details:  *d,
+		})
+	}
+	return readings
+}
+
+func (r compositeRule) evaluate(
+	statuses map[db.MonitorID]map[string]db.SubprobeStatus,
+	loadErrs map[db.MonitorID]error,
+) compositeRuleResult {
+	result := compositeRuleResult{
+		inputState:  r.inputState,
+		minCount:    r.minCount,
+		resultState: r.resultState,
+		inputCount:  len(r.inputs),
+		state:       state.Normal,
+	}
+
+	unknown := false
+	for _, in := range r.inputs {
+		if _, failed := loadErrs[in.monitorID]; failed {
+			unknown = true
+			continue
+		}
+
+		var matched []string
+		for name, status := range statuses[in.monitorID] {
+			// Silenced subprobes act as if they were Normal, as they
+			// do for triggers.
+			if status.Silenced || !in.subprobes.MatchString(name) {
+				continue
+			}
+			if status.State >= r.inputState {
+				matched = append(matched, name+" is "+status.State.String())
+			}
+		}
+
+		if len(matched) > 0 {
+			result.triggered = append(result.triggered, compositeTriggeredInput{
+				monitorID: in.monitorID,
+				subprobes: matched,
+			})
+		}
+	}
+
+	switch {
+	case len(result.triggered) >= r.minCount:
+		result.state = r.resultState
+	case unknown:
+		result.state = state.Unknown
+	}
+	return result
+}
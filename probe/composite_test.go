@@ -0,0 +1,109 @@
+package probe_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/yext/revere/probe"
+	"github.com/yext/revere/test"
+)
+
+var (
+	cId        = 6
+	cName      = "Composite"
+	cProbeType = CompositeType{}
+	validCJson = test.DefaultCompositeProbeJson
+)
+
+func validCompositeProbe() (*CompositeProbe, error) {
+	probe, err := LoadFromParams(cProbeType.Id(), validCJson)
+	if err != nil {
+		return nil, err
+	}
+
+	cProbe, ok := probe.(CompositeProbe)
+	if !ok {
+		return nil, fmt.Errorf("Invalid probe loaded for probe type: %s\n", cProbeType.Name())
+	}
+
+	return &cProbe, nil
+}
+
+func TestCompositeId(t *testing.T) {
+	if int(cProbeType.Id()) != cId {
+		t.Errorf("Expected composite probe type id: %d, got %d\n", cId, cProbeType.Id())
+	}
+}
+
+func TestCompositeName(t *testing.T) {
+	if cProbeType.Name() != cName {
+		t.Errorf("Expected composite probe type name: %s, got %s\n", cName, cProbeType.Name())
+	}
+}
+
+func TestValidComposite(t *testing.T) {
+	cProbe, err := validCompositeProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if errs := cProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid composite probe: %v\n", errs)
+	}
+
+	if _, err := cProbe.SerializeForDB(); err != nil {
+		t.Errorf("Unexpected error serializing valid composite probe: %v\n", err)
+	}
+}
+
+func TestCompositeNoRules(t *testing.T) {
+	cProbe, err := validCompositeProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cProbe.Rules = nil
+	if errs := cProbe.Validate(); errs == nil {
+		t.Error("Expected error for composite probe with no rules")
+	}
+}
+
+func TestInvalidCompositeInputs(t *testing.T) {
+	for _, inputs := range []string{"", "abc", "0", "12 (", "12\n-3"} {
+		cProbe, err := validCompositeProbe()
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		cProbe.Rules[1].Inputs = inputs
+		if errs := cProbe.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid inputs: %q\n", inputs)
+		}
+	}
+}
+
+func TestInvalidCompositeMinCount(t *testing.T) {
+	for _, minCount := range []int{0, 4} {
+		cProbe, err := validCompositeProbe()
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		cProbe.Rules[0].MinCount = minCount
+		if errs := cProbe.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid minimum count: %d\n", minCount)
+		}
+	}
+}
+
+func TestInvalidCompositeResultState(t *testing.T) {
+	cProbe, err := validCompositeProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cProbe.Rules[0].ResultState = "Normal"
+	if errs := cProbe.Validate(); errs == nil {
+		t.Error("Expected error for Normal result state")
+	}
+}
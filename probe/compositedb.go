@@ -0,0 +1,35 @@
+package probe
+
+import (
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+// CompositeDBModel defines the JSON serialization format for saving composite
+// probes' settings in the database.
+type CompositeDBModel struct {
+	CheckPeriodMilli int64
+	Rules            []CompositeRuleDBModel
+}
+
+// CompositeRuleDBModel defines the JSON serialization format for a composite
+// probe rule. The rule puts its subprobe into ResultState when at least
+// MinCount of its inputs have a subprobe in InputState or worse.
+//
+// Several rules may share a subprobe; the subprobe is in the worst state of
+// its triggered rules.
+type CompositeRuleDBModel struct {
+	Subprobe    string
+	Inputs      []CompositeInputDBModel
+	InputState  state.State
+	MinCount    int
+	ResultState state.State
+}
+
+// CompositeInputDBModel defines the JSON serialization format for a composite
+// probe rule's input: the subprobes of another monitor matching a regular
+// expression.
+type CompositeInputDBModel struct {
+	MonitorID db.MonitorID
+	Subprobes string
+}
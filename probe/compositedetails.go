@@ -0,0 +1,55 @@
+package probe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+type compositeDetails struct {
+	state state.State
+	rules []compositeRuleResult
+}
+
+type compositeRuleResult struct {
+	inputState  state.State
+	minCount    int
+	resultState state.State
+	inputCount  int
+
+	state     state.State
+	triggered []compositeTriggeredInput
+}
+
+type compositeTriggeredInput struct {
+	monitorID db.MonitorID
+	subprobes []string
+}
+
+func (d compositeDetails) Text() string {
+	lines := make([]string, 0, len(d.rules))
+	for _, r := range d.rules {
+		lines = append(lines, r.text())
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func (r compositeRuleResult) text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s if at least %d of %d inputs are %s or worse (%d matched)",
+		r.resultState, r.minCount, r.inputCount, r.inputState, len(r.triggered))
+	if r.state == state.Unknown {
+		b.WriteString(" (some inputs could not be loaded)")
+	}
+
+	for _, in := range r.triggered {
+		sort.Strings(in.subprobes)
+		fmt.Fprintf(&b, "\n* Monitor %d: %s", in.monitorID, strings.Join(in.subprobes, ", "))
+	}
+
+	return b.String()
+}
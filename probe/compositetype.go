@@ -0,0 +1,20 @@
+package probe
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/yext/revere/db"
+)
+
+type compositeType struct{}
+
+func init() {
+	registerProbeType(compositeType{})
+}
+
+func (compositeType) ID() db.ProbeType {
+	return CompositeType{}.Id()
+}
+
+func (compositeType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	return newComposite(m, config, readingsSink)
+}
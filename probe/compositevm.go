@@ -0,0 +1,226 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/util"
+)
+
+type CompositeType struct{}
+
+type CompositeProbe struct {
+	CompositeType
+
+	CheckPeriod     int64
+	CheckPeriodType string
+	Rules           []CompositeRuleModel
+}
+
+type CompositeRuleModel struct {
+	Subprobe string
+	// Inputs holds one input per line: a monitor ID optionally followed by
+	// a regular expression matching the monitor's subprobes.
+	Inputs      string
+	InputState  string
+	MinCount    int
+	ResultState string
+}
+
+func init() {
+	addType(CompositeType{})
+}
+
+func (CompositeType) Id() db.ProbeType {
+	return 6
+}
+
+func (CompositeType) Name() string {
+	return "Composite"
+}
+
+func (CompositeType) loadFromParams(probe string) (VM, error) {
+	var p CompositeProbe
+	err := json.Unmarshal([]byte(probe), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (CompositeType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, error) {
+	var p CompositeDBModel
+	err := json.Unmarshal([]byte(encodedProbe), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	checkPeriod, checkPeriodType := util.GetPeriodAndType(p.CheckPeriodMilli)
+
+	rules := make([]CompositeRuleModel, len(p.Rules))
+	for i, r := range p.Rules {
+		inputs := make([]string, len(r.Inputs))
+		for j, in := range r.Inputs {
+			inputs[j] = strings.TrimSpace(fmt.Sprintf("%d %s", in.MonitorID, in.Subprobes))
+		}
+		rules[i] = CompositeRuleModel{
+			Subprobe:    r.Subprobe,
+			Inputs:      strings.Join(inputs, "\n"),
+			InputState:  r.InputState.String(),
+			MinCount:    r.MinCount,
+			ResultState: r.ResultState.String(),
+		}
+	}
+
+	return &CompositeProbe{
+		CheckPeriod:     checkPeriod,
+		CheckPeriodType: checkPeriodType,
+		Rules:           rules,
+	}, nil
+}
+
+func (CompositeType) blank() (VM, error) {
+	return &CompositeProbe{}, nil
+}
+
+func (CompositeType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "composite-edit.html",
+		"view": "composite-view.html",
+	}
+}
+
+func (CompositeType) Scripts() map[string][]string {
+	return map[string][]string{
+		"edit": []string{
+			"composite.js",
+		},
+	}
+}
+
+func (CompositeType) AcceptedResourceTypes() []db.ResourceType {
+	return []db.ResourceType{}
+}
+
+func (p CompositeProbe) HasResource(id db.ResourceID) bool {
+	return false
+}
+
+func (p CompositeProbe) SerializeForFrontend() map[string]string {
+	subprobes := make([]string, len(p.Rules))
+	for i, r := range p.Rules {
+		subprobes[i] = r.Subprobe
+	}
+	return map[string]string{
+		"Subprobes": strings.Join(subprobes, ","),
+	}
+}
+
+func (p CompositeProbe) SerializeForDB() (string, error) {
+	cDB := CompositeDBModel{
+		CheckPeriodMilli: util.GetMs(p.CheckPeriod, p.CheckPeriodType),
+	}
+
+	for _, r := range p.Rules {
+		inputs, err := r.inputs()
+		if err != nil {
+			return "", err
+		}
+		inputState, err := state.FromString(r.InputState)
+		if err != nil {
+			return "", err
+		}
+		resultState, err := state.FromString(r.ResultState)
+		if err != nil {
+			return "", err
+		}
+		cDB.Rules = append(cDB.Rules, CompositeRuleDBModel{
+			Subprobe:    strings.TrimSpace(r.Subprobe),
+			Inputs:      inputs,
+			InputState:  inputState,
+			MinCount:    r.MinCount,
+			ResultState: resultState,
+		})
+	}
+
+	cDBJSON, err := json.Marshal(cDB)
+	return string(cDBJSON), err
+}
+
+// inputs parses the Inputs field.
+func (r CompositeRuleModel) inputs() ([]CompositeInputDBModel, error) {
+	var inputs []CompositeInputDBModel
+	for _, line := range nonEmptyLines(r.Inputs) {
+		fields := strings.SplitN(line, " ", 2)
+		id, err := strconv.Atoi(fields[0])
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid monitor ID in input: %s", line)
+		}
+
+		var subprobes string
+		if len(fields) > 1 {
+			subprobes = strings.TrimSpace(fields[1])
+		}
+		if _, err := regexp.Compile(subprobes); err != nil {
+			return nil, fmt.Errorf("invalid subprobe regular expression in input %s: %v", line, err)
+		}
+
+		inputs = append(inputs, CompositeInputDBModel{
+			MonitorID: db.MonitorID(id),
+			Subprobes: subprobes,
+		})
+	}
+	return inputs, nil
+}
+
+func (p CompositeProbe) Type() VMType {
+	return CompositeType{}
+}
+
+func (p CompositeProbe) Validate() (errs []string) {
+	if !isValidPeriodType(p.CheckPeriodType) {
+		errs = append(errs, "Invalid check period type")
+	}
+
+	if util.GetMs(p.CheckPeriod, p.CheckPeriodType) <= 0 {
+		errs = append(errs, "Invalid check period")
+	}
+
+	if len(p.Rules) == 0 {
+		errs = append(errs, "At least one rule is required")
+	}
+
+	for i, r := range p.Rules {
+		prefix := fmt.Sprintf("Rule %d: ", i+1)
+
+		subprobe := strings.TrimSpace(r.Subprobe)
+		if subprobe == "" || len(subprobe) > 150 {
+			errs = append(errs, prefix+"Invalid subprobe name")
+		}
+
+		inputs, err := r.inputs()
+		if err != nil {
+			errs = append(errs, prefix+err.Error())
+		} else if len(inputs) == 0 {
+			errs = append(errs, prefix+"At least one input is required")
+		} else if r.MinCount < 1 || r.MinCount > len(inputs) {
+			errs = append(errs, prefix+fmt.Sprintf("Minimum count must be between 1 and %d", len(inputs)))
+		}
+
+		if _, err := state.FromString(r.InputState); err != nil {
+			errs = append(errs, prefix+"Invalid input state")
+		}
+
+		resultState, err := state.FromString(r.ResultState)
+		if err != nil || resultState == state.Normal {
+			errs = append(errs, prefix+"Invalid result state")
+		}
+	}
+
+	return
+}
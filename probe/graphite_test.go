@@ -108,7 +108,7 @@ func TestValidGraphiteThresholdPeriodType(t *testing.T) {
 		gtProbe.AuditPeriodType = pt
 		errs = gtProbe.Validate()
 		if errs != nil {
-			t.Error("Unexpected error for audit period type: %s\n", pt)
+			t.Errorf("Unexpected error for audit period type: %s\n", pt)
 		}
 	}
 }
@@ -132,3 +132,152 @@ func TestInvalidGraphiteThresholdPeriodType(t *testing.T) {
 		t.Error("Expected error for invalid audit period type")
 	}
 }
+
+func TestValidGraphiteThresholdAnomaly(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	three := 3.0
+	gtProbe.Thresholds = ThresholdsModel{Warning: &three}
+	gtProbe.Mode = "anomaly"
+	gtProbe.AnomalyBaseline = "shift"
+	gtProbe.AnomalyShift = 7
+	gtProbe.AnomalyShiftType = "day"
+	if errs := gtProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid shifted anomaly probe: %v\n", errs)
+	}
+
+	gtProbe.AnomalyBaseline = "rolling"
+	gtProbe.AnomalyWindow = 6
+	gtProbe.AnomalyWindowType = "hour"
+	if errs := gtProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid rolling anomaly probe: %v\n", errs)
+	}
+}
+
+func TestInvalidGraphiteThresholdAnomaly(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	gtProbe.Mode = "anomaly"
+	gtProbe.AnomalyBaseline = "shift"
+	gtProbe.AnomalyShift = 0
+	gtProbe.AnomalyShiftType = "day"
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Error("Expected error for anomaly probe with no shift")
+	}
+
+	gtProbe.AnomalyBaseline = "seasonal"
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid anomaly baseline: %s\n", gtProbe.AnomalyBaseline)
+	}
+
+	gtProbe.AnomalyBaseline = "rolling"
+	gtProbe.AnomalyWindow = 1
+	gtProbe.AnomalyWindowType = "hour"
+	negative := -2.0
+	gtProbe.Thresholds = ThresholdsModel{Error: &negative}
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Error("Expected error for negative anomaly threshold")
+	}
+
+	gtProbe.Mode = "seasonal"
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid mode: %s\n", gtProbe.Mode)
+	}
+}
+
+func TestValidGraphiteThresholdAuditFunctions(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, f := range []string{"avg", "max", "min", "p50", "p90", "p99", "sum", "last", "nullcount", "rate"} {
+		gtProbe.AuditFunction = f
+		if errs := gtProbe.Validate(); errs != nil {
+			t.Errorf("Unexpected errors for audit function %s: %v\n", f, errs)
+		}
+	}
+}
+
+func TestInvalidGraphiteThresholdAuditFunction(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	gtProbe.AuditFunction = "p101"
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid audit function: %s\n", gtProbe.AuditFunction)
+	}
+}
+
+func TestValidGraphiteThresholdOverrides(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ten := 10.0
+	gtProbe.ThresholdOverrides = []ThresholdOverrideModel{
+		{Subprobes: `^db-large-\d+$`, Thresholds: ThresholdsModel{Error: &ten}},
+	}
+	if errs := gtProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid threshold override: %v\n", errs)
+	}
+}
+
+func TestInvalidGraphiteThresholdOverrides(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	gtProbe.ThresholdOverrides = []ThresholdOverrideModel{{Subprobes: ""}}
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Error("Expected error for threshold override with no subprobe regex")
+	}
+
+	gtProbe.ThresholdOverrides = []ThresholdOverrideModel{{Subprobes: "db-(large"}}
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid threshold override regex: %s\n", gtProbe.ThresholdOverrides[0].Subprobes)
+	}
+}
+
+func TestValidGraphiteThresholdMissingData(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	gtProbe.MinDatapoints = 5
+	for _, md := range []string{"ignore", "Normal", "Warning", "Unknown", "ERROR", "CRITICAL"} {
+		gtProbe.MissingData = md
+		if errs := gtProbe.Validate(); errs != nil {
+			t.Errorf("Unexpected errors for missing data policy %s: %v\n", md, errs)
+		}
+	}
+}
+
+func TestInvalidGraphiteThresholdMissingData(t *testing.T) {
+	gtProbe, err := validGraphiteThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	gtProbe.MissingData = "Broken"
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid missing data policy: %s\n", gtProbe.MissingData)
+	}
+
+	gtProbe.MissingData = "ignore"
+	gtProbe.MinDatapoints = -1
+	if errs := gtProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid minimum datapoints: %d\n", gtProbe.MinDatapoints)
+	}
+}
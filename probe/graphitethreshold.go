@@ -3,6 +3,7 @@ package probe
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,8 +15,14 @@ import (
 	"github.com/yext/revere/state"
 )
 
+const (
+	missingDataIgnore = "ignore"
+	missingDataState  = "state"
+)
+
 // GraphiteThreshold implements a probe that assigns states based on whether a
-// Graphite metric is above or below various constant values.
+// Graphite metric is above or below various constant values. In anomaly mode,
+// the values are instead multiples of the standard deviation of a baseline.
 type GraphiteThreshold struct {
 	*Polling
 
@@ -24,19 +31,23 @@ type GraphiteThreshold struct {
 	timeToAudit        time.Duration
 	recentTimeToIgnore time.Duration
 
-	thresholds      []graphiteThresholdThreshold
-	summarizeValues func(values []float64) float64
-	triggersOn      func(summaryValue, threshold float64) bool
+	thresholds         []threshold
+	thresholdOverrides []thresholdOverride
+	summarizeValues    auditFunction
+	triggersOn         func(summaryValue, threshold float64) bool
+
+	// anomaly is nil unless the probe is in anomaly mode.
+	anomaly *anomaly
+
+	// missingDataState is the state of series with fewer than minDatapoints
+	// values, or nil if such series are skipped.
+	missingDataState *state.State
+	minDatapoints    int
 
 	auditFunctionName string
 	triggerIfText     string
 }
 
-type graphiteThresholdThreshold struct {
-	state     state.State
-	threshold float64
-}
-
 func newGraphiteThreshold(tx *db.Tx, configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
 	gt := GraphiteThreshold{}
 
@@ -76,18 +87,19 @@ func newGraphiteThreshold(tx *db.Tx, configJSON types.JSONText, readingsSink cha
 	gt.timeToAudit = time.Duration(config.TimeToAuditMilli) * time.Millisecond
 	gt.recentTimeToIgnore = time.Duration(config.RecentTimeToIgnoreMilli) * time.Millisecond
 
-	// Must be in increasing severity order.
-	if config.Thresholds.Warning != nil {
-		gt.thresholds = append(gt.thresholds,
-			graphiteThresholdThreshold{state.Warning, *config.Thresholds.Warning})
-	}
-	if config.Thresholds.Error != nil {
-		gt.thresholds = append(gt.thresholds,
-			graphiteThresholdThreshold{state.Error, *config.Thresholds.Error})
-	}
-	if config.Thresholds.Critical != nil {
-		gt.thresholds = append(gt.thresholds,
-			graphiteThresholdThreshold{state.Critical, *config.Thresholds.Critical})
+	gt.thresholds = newThresholds(
+		config.Thresholds.Warning, config.Thresholds.Error, config.Thresholds.Critical)
+
+	for _, o := range config.ThresholdOverrides {
+		subprobes, err := regexp.Compile(o.Subprobes)
+		if err != nil {
+			return nil, errors.Maskf(err, "compile threshold override regexp")
+		}
+		gt.thresholdOverrides = append(gt.thresholdOverrides, thresholdOverride{
+			subprobes: subprobes,
+			thresholds: newThresholds(
+				o.Thresholds.Warning, o.Thresholds.Error, o.Thresholds.Critical),
+		})
 	}
 
 	var ok bool
@@ -105,72 +117,47 @@ func newGraphiteThreshold(tx *db.Tx, configJSON types.JSONText, readingsSink cha
 	gt.auditFunctionName = config.AuditFunction
 	gt.triggerIfText = config.TriggerIf
 
-	return &gt, nil
-}
+	switch config.Mode {
+	case "", graphiteModeThreshold:
+	case graphiteModeAnomaly:
+		gt.anomaly, err = newAnomaly(config.Anomaly)
+		if err != nil {
+			return nil, errors.Mask(err)
+		}
+		gt.thresholds = anomalyThresholds(gt.thresholds, config.TriggerIf)
+		for i := range gt.thresholdOverrides {
+			gt.thresholdOverrides[i].thresholds = anomalyThresholds(
+				gt.thresholdOverrides[i].thresholds, config.TriggerIf)
+		}
+	default:
+		return nil, errors.Errorf("unknown mode: %s", config.Mode)
+	}
 
-var (
-	auditFunctions = map[string]func([]float64) float64{
-		"avg": func(values []float64) float64 {
-			sum := float64(0)
-			count := 0
-			for _, value := range values {
-				if !math.IsNaN(value) {
-					sum += value
-					count += 1
-				}
-			}
-			return sum / float64(count)
-		},
-		"max": func(values []float64) float64 {
-			max := math.Inf(-1)
-			for _, value := range values {
-				if value > max {
-					max = value
-				}
-			}
-			if math.IsInf(max, -1) {
-				return math.NaN()
-			}
-			return max
-		},
-		"min": func(values []float64) float64 {
-			min := math.Inf(+1)
-			for _, value := range values {
-				if value < min {
-					min = value
-				}
-			}
-			if math.IsInf(min, +1) {
-				return math.NaN()
-			}
-			return min
-		},
-	}
-
-	triggerIfFunctions = map[string]func(float64, float64) bool{
-		"<": func(summaryValue, threshold float64) bool {
-			return summaryValue < threshold
-		},
-		"<=": func(summaryValue, threshold float64) bool {
-			return summaryValue <= threshold
-		},
-		">=": func(summaryValue, threshold float64) bool {
-			return summaryValue >= threshold
-		},
-		">": func(summaryValue, threshold float64) bool {
-			return summaryValue > threshold
-		},
+	switch config.MissingData.Policy {
+	case "", missingDataIgnore:
+	case missingDataState:
+		if err := config.MissingData.State.Validate(); err != nil {
+			return nil, errors.Maskf(err, "invalid missing data state")
+		}
+		gt.missingDataState = &config.MissingData.State
+	default:
+		return nil, errors.Errorf("unknown missing data policy: %s", config.MissingData.Policy)
 	}
-)
+	gt.minDatapoints = config.MissingData.MinDatapoints
+
+	return &gt, nil
+}
 
 func (gt *GraphiteThreshold) Check() []Reading {
 	now := time.Now()
 
 	auditEnd := now.Add(-gt.recentTimeToIgnore)
 
-	g := resource.GraphiteDaemon{gt.graphiteBase}
+	g := resource.GraphiteDaemon{Base: gt.graphiteBase}
 
-	series, err := g.Query(gt.expression, auditEnd.Add(-gt.timeToAudit), auditEnd)
+	auditStart := auditEnd.Add(-gt.timeToAudit)
+
+	series, err := g.Query(gt.expression, auditStart, auditEnd)
 	if err != nil {
 		// TODO(eefi): Include this probe's monitor's ID.
 		log.WithError(err).Error("Could not query Graphite.")
@@ -179,25 +166,68 @@ func (gt *GraphiteThreshold) Check() []Reading {
 		return []Reading{{"_", state.Unknown, now, nil}}
 	}
 
+	var baselines map[string]resource.GraphiteSeries
+	if gt.anomaly != nil {
+		baselines, err = gt.anomaly.query(g, gt.expression, auditStart, auditEnd)
+		if err != nil {
+			log.WithError(err).Error("Could not query Graphite for anomaly baseline.")
+			return []Reading{{"_", state.Unknown, now, nil}}
+		}
+	}
+
 	if len(series) == 0 {
-		return []Reading{{"_", state.Normal, now, nil}}
+		if gt.missingDataState == nil {
+			return []Reading{{"_", state.Normal, now, nil}}
+		}
+		return []Reading{{"_", *gt.missingDataState, now, graphiteMissingDataDetails{
+			timeToAudit:   gt.timeToAudit,
+			minDatapoints: gt.minDatapoints,
+			graphite:      &g,
+			expression:    gt.expression,
+			measuredEnd:   auditEnd,
+		}}}
 	}
 
 	readings := make([]Reading, 0, len(series)+1)
 	for _, s := range series {
-		summaryValue := gt.summarizeValues(s.Values)
-		if math.IsNaN(summaryValue) {
-			// Series was all NaNs.
+		summaryValue := gt.summarizeValues(s.Values, s.Step)
+		datapoints := countDatapoints(s.Values)
+		if math.IsNaN(summaryValue) || datapoints < gt.minDatapoints {
+			// Series could not be summarized, e.g. it was all NaNs, or
+			// has too few values to be trusted.
+			if gt.missingDataState != nil {
+				readings = append(readings, Reading{s.Name, *gt.missingDataState, now, graphiteMissingDataDetails{
+					timeToAudit:   gt.timeToAudit,
+					datapoints:    datapoints,
+					minDatapoints: gt.minDatapoints,
+					graphite:      &g,
+					expression:    gt.expression,
+					seriesName:    s.Name,
+					measuredEnd:   auditEnd,
+				}})
+			}
 			continue
 		}
 
 		r := Reading{s.Name, state.Normal, now, nil}
 
-		triggeredThreshold := math.NaN()
-		for _, t := range gt.thresholds {
-			if gt.triggersOn(summaryValue, t.threshold) {
-				r.State = t.state
-				triggeredThreshold = t.threshold
+		thresholds := thresholdsFor(s.Name, gt.thresholds, gt.thresholdOverrides)
+
+		var triggeredThreshold float64
+		var anomalyDetails *graphiteAnomalyDetails
+		if gt.anomaly == nil {
+			r.State, triggeredThreshold = evaluateThresholds(thresholds, gt.triggersOn, summaryValue)
+		} else {
+			anomalyDetails = gt.checkAnomaly(baselines[s.Name], thresholds, summaryValue)
+			if math.IsNaN(anomalyDetails.center) || math.IsNaN(anomalyDetails.stddev) {
+				// No baseline data to compare against.
+				r.State, triggeredThreshold = state.Unknown, math.NaN()
+			} else {
+				var triggeredMultiple float64
+				r.State, triggeredMultiple = evaluateThresholds(
+					thresholds, gt.triggersOn, anomalyDetails.deviation)
+				triggeredThreshold = anomalyDetails.value(triggeredMultiple)
+				anomalyDetails.triggeredMultiple = math.Abs(triggeredMultiple)
 			}
 		}
 
@@ -208,6 +238,7 @@ func (gt *GraphiteThreshold) Check() []Reading {
 
 			measured:  summaryValue,
 			threshold: triggeredThreshold,
+			anomaly:   anomalyDetails,
 
 			graphite:    &g,
 			expression:  gt.expression,
@@ -221,3 +252,34 @@ func (gt *GraphiteThreshold) Check() []Reading {
 
 	return readings
 }
+
+// checkAnomaly compares summaryValue against the baseline computed from
+// baseline. The returned details' center and stddev are NaN if there is no
+// baseline data.
+func (gt *GraphiteThreshold) checkAnomaly(baseline resource.GraphiteSeries, thresholds []threshold, summaryValue float64) *graphiteAnomalyDetails {
+	d := &graphiteAnomalyDetails{
+		baseline:          gt.anomaly.baseline,
+		shift:             gt.anomaly.shift,
+		window:            gt.anomaly.window,
+		center:            gt.anomaly.center(baseline, gt.summarizeValues),
+		stddev:            stddev(baseline.Values),
+		triggeredMultiple: math.NaN(),
+		bandMultiple:      math.NaN(),
+	}
+	d.deviation = deviation(summaryValue, d.center, d.stddev)
+	if len(thresholds) > 0 {
+		d.bandMultiple = math.Abs(thresholds[0].threshold)
+	}
+	return d
+}
+
+// countDatapoints returns the number of non-null values in values.
+func countDatapoints(values []float64) int {
+	n := 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			n++
+		}
+	}
+	return n
+}
@@ -1,5 +1,9 @@
 package probe
 
+import (
+	"github.com/yext/revere/state"
+)
+
 // GraphiteThresholdDBModel defines the JSON serialization format for saving
 // Graphite threshold probes' settings in the database.
 type GraphiteThresholdDBModel struct {
@@ -9,11 +13,57 @@ type GraphiteThresholdDBModel struct {
 	Thresholds GraphiteThresholdThresholdsDBModel
 	TriggerIf  string
 
+	// ThresholdOverrides replace Thresholds for the subprobes they match.
+	// The first matching override applies.
+	ThresholdOverrides []GraphiteThresholdOverrideDBModel
+
 	CheckPeriodMilli int64
 
 	TimeToAuditMilli        int64
 	RecentTimeToIgnoreMilli int64
 	AuditFunction           string
+
+	// Mode is "threshold" (or empty) to compare against the thresholds
+	// directly, or "anomaly" to treat the thresholds as multiples of a
+	// baseline's standard deviation.
+	Mode    string
+	Anomaly GraphiteThresholdAnomalyDBModel
+
+	MissingData GraphiteThresholdMissingDataDBModel
+}
+
+// GraphiteThresholdMissingDataDBModel defines the JSON serialization format
+// for saving how Graphite threshold probes treat missing data in the
+// database.
+type GraphiteThresholdMissingDataDBModel struct {
+	// Policy is "ignore" (or empty) to skip series without enough data and
+	// report Normal when the expression returns no series, or "state" to
+	// report State for both.
+	Policy string
+	State  state.State
+
+	// MinDatapoints is the number of non-null values a series needs in the
+	// audited window to be evaluated.
+	MinDatapoints int
+}
+
+// GraphiteThresholdOverrideDBModel defines the JSON serialization format for
+// saving Graphite threshold probes' per-subprobe thresholds in the database.
+// Subprobes is a regular expression matched against series names.
+type GraphiteThresholdOverrideDBModel struct {
+	Subprobes  string
+	Thresholds GraphiteThresholdThresholdsDBModel
+}
+
+// GraphiteThresholdAnomalyDBModel defines the JSON serialization format for
+// saving Graphite threshold probes' anomaly mode settings in the database.
+type GraphiteThresholdAnomalyDBModel struct {
+	// Baseline is "shift" to compare against the audited window shifted
+	// back by ShiftMilli, or "rolling" to compare against the mean of the
+	// WindowMilli preceding the audited window.
+	Baseline    string
+	ShiftMilli  int64
+	WindowMilli int64
 }
 
 // GraphiteThresholdThresholdsDBModel defines the JSON serialization format for
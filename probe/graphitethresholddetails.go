@@ -23,6 +23,53 @@ type graphiteThresholdDetails struct {
 	expression  string
 	seriesName  string
 	measuredEnd time.Time
+
+	// anomaly is nil unless the probe is in anomaly mode, in which case
+	// threshold is the value corresponding to the triggered deviation
+	// multiple.
+	anomaly *graphiteAnomalyDetails
+}
+
+type graphiteAnomalyDetails struct {
+	baseline string
+	shift    time.Duration
+	window   time.Duration
+
+	center    float64
+	stddev    float64
+	deviation float64
+
+	// triggeredMultiple is NaN if no threshold triggered. bandMultiple is
+	// the least severe threshold's multiple, or NaN if there are none.
+	triggeredMultiple float64
+	bandMultiple      float64
+}
+
+// value returns the value that is multiple standard deviations from the
+// baseline's center.
+func (a graphiteAnomalyDetails) value(multiple float64) float64 {
+	return a.center + multiple*a.stddev
+}
+
+func (a graphiteAnomalyDetails) text(auditFunction string) string {
+	if math.IsNaN(a.center) || math.IsNaN(a.stddev) {
+		return fmt.Sprintf("Baseline: no data for %s", a.baselineText(auditFunction))
+	}
+
+	direction := "above"
+	if a.deviation < 0 {
+		direction = "below"
+	}
+	return fmt.Sprintf("Baseline: %s: %g, standard deviation %g\nDeviation: %.2f standard deviations %s baseline",
+		a.baselineText(auditFunction), a.center, a.stddev, math.Abs(a.deviation), direction)
+}
+
+func (a graphiteAnomalyDetails) baselineText(auditFunction string) string {
+	if a.baseline == anomalyBaselineShift {
+		return fmt.Sprintf("%s of same window %s earlier",
+			auditFunction, durationfmt.ExactMulti().Format(a.shift))
+	}
+	return fmt.Sprintf("avg of preceding %s", durationfmt.ExactMulti().Format(a.window))
 }
 
 func (d graphiteThresholdDetails) Text() string {
@@ -37,16 +84,27 @@ func (d graphiteThresholdDetails) Text() string {
 		thresholdVal = fmt.Sprintf(" %s %g", d.triggerIf, d.threshold)
 	}
 
+	if d.anomaly != nil && !math.IsNaN(d.threshold) {
+		thresholdVal += fmt.Sprintf(" (%g standard deviations)", d.anomaly.triggeredMultiple)
+	}
+
 	firstLine := fmt.Sprintf("%s%s: %g%s",
 		measuredText, thresholdText, d.measured, thresholdVal)
+	if d.anomaly != nil {
+		firstLine += "\n" + d.anomaly.text(d.auditFunction)
+	}
 
 	return fmt.Sprintf("%s\n\nGraph: %s\nValues: %s\n", firstLine, d.graphURL(), d.valuesURL())
 }
 
+func (d graphiteThresholdDetails) GraphImageURL() string {
+	return d.graphURL()
+}
+
 func (d graphiteThresholdDetails) graphURL() string {
 	measuredStart := d.measuredEnd.Add(-d.timeToAudit)
 
-	targets := make([]string, 0, 3)
+	targets := make([]string, 0, 6)
 
 	timeHighlight := fmt.Sprintf(
 		`color(drawAsInfinite(timeSlice(timeFunction("", 1), "%s", "%s")), "yellow")`,
@@ -59,6 +117,10 @@ func (d graphiteThresholdDetails) graphURL() string {
 		targets = append(targets, thresholdLine)
 	}
 
+	if d.anomaly != nil {
+		targets = append(targets, d.anomaly.bandTargets(d.target())...)
+	}
+
 	targets = append(targets, fmt.Sprintf(`color(%s, "green")`, d.target()))
 
 	args := map[string]string{
@@ -78,7 +140,13 @@ func (d graphiteThresholdDetails) graphURL() string {
 	if contextTime < 30*time.Minute {
 		contextTime = 30 * time.Minute
 	}
-	args["from"] = resource.GraphiteTimestamp(measuredStart.Add(-2 * contextTime))
+	from := measuredStart.Add(-2 * contextTime)
+	if d.anomaly != nil && d.anomaly.baseline == anomalyBaselineRolling {
+		if windowStart := measuredStart.Add(-d.anomaly.window); windowStart.Before(from) {
+			from = windowStart
+		}
+	}
+	args["from"] = resource.GraphiteTimestamp(from)
 	args["until"] = resource.GraphiteTimestamp(d.measuredEnd.Add(contextTime))
 
 	return d.graphite.RenderURL(targets, args)
@@ -99,3 +167,90 @@ func (d graphiteThresholdDetails) target() string {
 		d.expression,
 		strings.Replace(regexp.QuoteMeta(d.seriesName), `"`, `\"`, -1))
 }
+
+// bandTargets returns Graphite targets drawing the baseline's center and the
+// band within the least severe threshold's multiple of it. For shifted
+// baselines, the shifted series is drawn as well.
+func (a graphiteAnomalyDetails) bandTargets(target string) []string {
+	var targets []string
+
+	if a.baseline == anomalyBaselineShift {
+		targets = append(targets, fmt.Sprintf(`color(timeShift(%s, "-%ds"), "gray")`,
+			target, int64(a.shift/time.Second)))
+	}
+
+	if math.IsNaN(a.center) || math.IsNaN(a.stddev) {
+		return targets
+	}
+
+	targets = append(targets, fmt.Sprintf(`color(dashed(constantLine(%g)), "gray")`, a.center))
+	if !math.IsNaN(a.bandMultiple) {
+		targets = append(targets,
+			fmt.Sprintf(`color(constantLine(%g), "blue")`, a.value(a.bandMultiple)),
+			fmt.Sprintf(`color(constantLine(%g), "blue")`, a.value(-a.bandMultiple)))
+	}
+	return targets
+}
+
+// graphiteMissingDataDetails describes a series, or an expression with no
+// series if seriesName is empty, that had too little data to be evaluated.
+type graphiteMissingDataDetails struct {
+	timeToAudit   time.Duration
+	datapoints    int
+	minDatapoints int
+
+	graphite    *resource.GraphiteDaemon
+	expression  string
+	seriesName  string
+	measuredEnd time.Time
+}
+
+func (d graphiteMissingDataDetails) Text() string {
+	timeToAuditText := durationfmt.ExactMulti().Format(d.timeToAudit)
+
+	var firstLine string
+	switch {
+	case d.seriesName == "":
+		firstLine = fmt.Sprintf("No series returned in last %s", timeToAuditText)
+	case d.minDatapoints > 0:
+		firstLine = fmt.Sprintf("%d datapoints in last %s, %d required",
+			d.datapoints, timeToAuditText, d.minDatapoints)
+	default:
+		firstLine = fmt.Sprintf("No usable data in last %s", timeToAuditText)
+	}
+
+	return fmt.Sprintf("%s\n\nGraph: %s\n", firstLine, d.graphURL())
+}
+
+func (d graphiteMissingDataDetails) GraphImageURL() string {
+	return d.graphURL()
+}
+
+func (d graphiteMissingDataDetails) graphURL() string {
+	target, title := d.expression, d.expression
+	if d.seriesName != "" {
+		target = graphiteThresholdDetails{expression: d.expression, seriesName: d.seriesName}.target()
+		title = d.seriesName
+	}
+
+	measuredStart := d.measuredEnd.Add(-d.timeToAudit)
+	timeHighlight := fmt.Sprintf(
+		`color(drawAsInfinite(timeSlice(timeFunction("", 1), "%s", "%s")), "yellow")`,
+		resource.GraphiteTimestamp(measuredStart),
+		resource.GraphiteTimestamp(d.measuredEnd))
+
+	contextTime := d.timeToAudit
+	if contextTime < 30*time.Minute {
+		contextTime = 30 * time.Minute
+	}
+
+	return d.graphite.RenderURL([]string{timeHighlight, target}, map[string]string{
+		"hideLegend": "true",
+		"width":      "970",
+		"height":     "600",
+		"tz":         "UTC",
+		"title":      title,
+		"from":       resource.GraphiteTimestamp(measuredStart.Add(-2 * contextTime)),
+		"until":      resource.GraphiteTimestamp(d.measuredEnd.Add(contextTime)),
+	})
+}
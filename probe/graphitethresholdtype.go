@@ -7,7 +7,15 @@ import (
 
 type graphiteThresholdType struct{}
 
+func init() {
+	registerProbeType(graphiteThresholdType{})
+}
+
+func (graphiteThresholdType) ID() db.ProbeType {
+	return GraphiteThresholdType{}.Id()
+}
+
 // TODO: Figure out something better than passing the transaction all the way through
-func (_ graphiteThresholdType) New(tx *db.Tx, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+func (_ graphiteThresholdType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
 	return newGraphiteThreshold(tx, config, readingsSink)
 }
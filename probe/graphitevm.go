@@ -2,12 +2,14 @@ package probe
 
 import (
 	"encoding/json"
+	"regexp"
 	"strconv"
 
 	"github.com/juju/errors"
 
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/resource"
+	"github.com/yext/revere/state"
 	"github.com/yext/revere/util"
 )
 
@@ -28,6 +30,21 @@ type GraphiteThresholdProbe struct {
 	AuditPeriodType   string
 	IgnoredPeriod     int64
 	IgnoredPeriodType string
+
+	// ThresholdOverrides replace Thresholds for the subprobes they match.
+	ThresholdOverrides []ThresholdOverrideModel
+
+	Mode              string
+	AnomalyBaseline   string
+	AnomalyShift      int64
+	AnomalyShiftType  string
+	AnomalyWindow     int64
+	AnomalyWindowType string
+
+	// MissingData is "ignore" (or empty) or the state to enter for series
+	// with no data or fewer than MinDatapoints values.
+	MissingData   string
+	MinDatapoints int
 }
 
 type ThresholdsModel struct {
@@ -36,8 +53,13 @@ type ThresholdsModel struct {
 	Critical *float64
 }
 
+type ThresholdOverrideModel struct {
+	Subprobes  string
+	Thresholds ThresholdsModel
+}
+
 var (
-	validGraphitePeriodTypes = []string{
+	validPeriodTypes = []string{
 		"day",
 		"hour",
 		"minute",
@@ -76,6 +98,30 @@ func (GraphiteThresholdType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, err
 	checkPeriod, checkPeriodType := util.GetPeriodAndType(g.CheckPeriodMilli)
 	auditPeriod, auditPeriodType := util.GetPeriodAndType(g.TimeToAuditMilli)
 	ignoredPeriod, ignoredPeriodType := util.GetPeriodAndType(g.RecentTimeToIgnoreMilli)
+	anomalyShift, anomalyShiftType := util.GetPeriodAndType(g.Anomaly.ShiftMilli)
+	anomalyWindow, anomalyWindowType := util.GetPeriodAndType(g.Anomaly.WindowMilli)
+
+	mode := g.Mode
+	if mode == "" {
+		mode = graphiteModeThreshold
+	}
+
+	missingData := missingDataIgnore
+	if g.MissingData.Policy == missingDataState {
+		missingData = g.MissingData.State.String()
+	}
+
+	overrides := make([]ThresholdOverrideModel, len(g.ThresholdOverrides))
+	for i, o := range g.ThresholdOverrides {
+		overrides[i] = ThresholdOverrideModel{
+			Subprobes: o.Subprobes,
+			Thresholds: ThresholdsModel{
+				o.Thresholds.Warning,
+				o.Thresholds.Error,
+				o.Thresholds.Critical,
+			},
+		}
+	}
 
 	dbds, err := tx.LoadResource(db.ResourceID(g.ResourceID))
 	if err != nil {
@@ -83,7 +129,7 @@ func (GraphiteThresholdType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, err
 	}
 
 	if dbds == nil {
-		return nil, errors.Errorf("no resource found: %d")
+		return nil, errors.Errorf("no resource found: %d", g.ResourceID)
 	}
 
 	ds, err := resource.LoadFromDB(resource.GraphiteResource{}.Id(), dbds.Resource)
@@ -105,19 +151,32 @@ func (GraphiteThresholdType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, err
 			g.Thresholds.Error,
 			g.Thresholds.Critical,
 		},
-		AuditFunction:     g.AuditFunction,
-		CheckPeriod:       checkPeriod,
-		CheckPeriodType:   checkPeriodType,
-		TriggerIf:         g.TriggerIf,
-		AuditPeriod:       auditPeriod,
-		AuditPeriodType:   auditPeriodType,
-		IgnoredPeriod:     ignoredPeriod,
-		IgnoredPeriodType: ignoredPeriodType,
+		ThresholdOverrides: overrides,
+		AuditFunction:      g.AuditFunction,
+		CheckPeriod:        checkPeriod,
+		CheckPeriodType:    checkPeriodType,
+		TriggerIf:          g.TriggerIf,
+		AuditPeriod:        auditPeriod,
+		AuditPeriodType:    auditPeriodType,
+		IgnoredPeriod:      ignoredPeriod,
+		IgnoredPeriodType:  ignoredPeriodType,
+		Mode:               mode,
+		AnomalyBaseline:    g.Anomaly.Baseline,
+		AnomalyShift:       anomalyShift,
+		AnomalyShiftType:   anomalyShiftType,
+		AnomalyWindow:      anomalyWindow,
+		AnomalyWindowType:  anomalyWindowType,
+		MissingData:        missingData,
+		MinDatapoints:      g.MissingData.MinDatapoints,
 	}, nil
 }
 
 func (GraphiteThresholdType) blank() (VM, error) {
-	return &GraphiteThresholdProbe{}, nil
+	return &GraphiteThresholdProbe{
+		Mode:            graphiteModeThreshold,
+		AnomalyBaseline: anomalyBaselineShift,
+		MissingData:     missingDataIgnore,
+	}, nil
 }
 
 func (GraphiteThresholdType) Templates() map[string]string {
@@ -187,6 +246,43 @@ func (g GraphiteThresholdProbe) SerializeForDB() (string, error) {
 		TimeToAuditMilli:        auditPeriodMilli,
 		RecentTimeToIgnoreMilli: ignoredPeriodMilli,
 		AuditFunction:           g.AuditFunction,
+		Mode:                    g.Mode,
+		MissingData: GraphiteThresholdMissingDataDBModel{
+			Policy:        missingDataIgnore,
+			MinDatapoints: g.MinDatapoints,
+		},
+	}
+
+	if g.MissingData != "" && g.MissingData != missingDataIgnore {
+		s, err := state.FromString(g.MissingData)
+		if err != nil {
+			return "", errors.Mask(err)
+		}
+		gtDB.MissingData.Policy = missingDataState
+		gtDB.MissingData.State = s
+	}
+
+	for _, o := range g.ThresholdOverrides {
+		gtDB.ThresholdOverrides = append(gtDB.ThresholdOverrides, GraphiteThresholdOverrideDBModel{
+			Subprobes: o.Subprobes,
+			Thresholds: GraphiteThresholdThresholdsDBModel{
+				Warning:  o.Thresholds.Warning,
+				Error:    o.Thresholds.Error,
+				Critical: o.Thresholds.Critical,
+			},
+		})
+	}
+
+	if g.Mode == graphiteModeAnomaly {
+		gtDB.Anomaly = GraphiteThresholdAnomalyDBModel{
+			Baseline: g.AnomalyBaseline,
+		}
+		switch g.AnomalyBaseline {
+		case anomalyBaselineShift:
+			gtDB.Anomaly.ShiftMilli = util.GetMs(g.AnomalyShift, g.AnomalyShiftType)
+		case anomalyBaselineRolling:
+			gtDB.Anomaly.WindowMilli = util.GetMs(g.AnomalyWindow, g.AnomalyWindowType)
+		}
 	}
 
 	gtDBJSON, err := json.Marshal(gtDB)
@@ -203,7 +299,7 @@ func (g GraphiteThresholdProbe) Validate() (errs []string) {
 	}
 
 	isValidCheckPeriodType := false
-	for _, vpt := range validGraphitePeriodTypes {
+	for _, vpt := range validPeriodTypes {
 		if g.CheckPeriodType == vpt {
 			isValidCheckPeriodType = true
 			break
@@ -214,7 +310,7 @@ func (g GraphiteThresholdProbe) Validate() (errs []string) {
 	}
 
 	isValidAuditPeriodType := false
-	for _, vpt := range validGraphitePeriodTypes {
+	for _, vpt := range validPeriodTypes {
 		if g.AuditPeriodType == vpt {
 			isValidAuditPeriodType = true
 			break
@@ -232,5 +328,82 @@ func (g GraphiteThresholdProbe) Validate() (errs []string) {
 		errs = append(errs, "Invalid audit period")
 	}
 
+	if _, ok := auditFunctions[g.AuditFunction]; !ok {
+		errs = append(errs, "Invalid audit function")
+	}
+
+	if _, ok := triggerIfFunctions[g.TriggerIf]; !ok {
+		errs = append(errs, "Invalid trigger if")
+	}
+
+	for _, o := range g.ThresholdOverrides {
+		if o.Subprobes == "" {
+			errs = append(errs, "Threshold overrides require a subprobe regular expression")
+		} else if _, err := regexp.Compile(o.Subprobes); err != nil {
+			errs = append(errs, "Invalid threshold override regular expression: "+o.Subprobes)
+		}
+	}
+
+	if g.MissingData != "" && g.MissingData != missingDataIgnore {
+		if _, err := state.FromString(g.MissingData); err != nil {
+			errs = append(errs, "Invalid missing data state")
+		}
+	}
+
+	if g.MinDatapoints < 0 {
+		errs = append(errs, "Minimum datapoints must not be negative")
+	}
+
+	switch g.Mode {
+	case "", graphiteModeThreshold:
+	case graphiteModeAnomaly:
+		errs = append(errs, g.validateAnomaly()...)
+	default:
+		errs = append(errs, "Invalid mode")
+	}
+
+	return
+}
+
+func (g GraphiteThresholdProbe) validateAnomaly() (errs []string) {
+	switch g.AnomalyBaseline {
+	case anomalyBaselineShift:
+		if !isValidPeriodType(g.AnomalyShiftType) {
+			errs = append(errs, "Invalid anomaly shift type")
+		}
+		if util.GetMs(g.AnomalyShift, g.AnomalyShiftType) <= 0 {
+			errs = append(errs, "Invalid anomaly shift")
+		}
+	case anomalyBaselineRolling:
+		if !isValidPeriodType(g.AnomalyWindowType) {
+			errs = append(errs, "Invalid anomaly window type")
+		}
+		if util.GetMs(g.AnomalyWindow, g.AnomalyWindowType) <= 0 {
+			errs = append(errs, "Invalid anomaly window")
+		}
+	default:
+		errs = append(errs, "Invalid anomaly baseline")
+	}
+
+	thresholds := []ThresholdsModel{g.Thresholds}
+	for _, o := range g.ThresholdOverrides {
+		thresholds = append(thresholds, o.Thresholds)
+	}
+	for _, ts := range thresholds {
+		if !ts.allPositive() {
+			errs = append(errs, "Anomaly thresholds must be positive multiples of the standard deviation")
+			break
+		}
+	}
+
 	return
 }
+
+func (t ThresholdsModel) allPositive() bool {
+	for _, v := range []*float64{t.Warning, t.Error, t.Critical} {
+		if v != nil && *v <= 0 {
+			return false
+		}
+	}
+	return true
+}
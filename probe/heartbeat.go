@@ -0,0 +1,223 @@
+package probe
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+// heartbeatResyncPeriod is how often a heartbeat probe reloads heartbeats from
+// the database. Heartbeats received by a different Revere process are only
+// seen this often.
+const heartbeatResyncPeriod = time.Minute
+
+// Heartbeat implements a push-based probe. Subprobes check in by recording
+// heartbeats, and a subprobe is in Error state once its grace window has
+// passed without a heartbeat.
+//
+// Heartbeats are persisted in the database so that Revere's web and daemon
+// modes can run in separate processes. Heartbeats received in the same
+// process are also passed to the probe directly via NotifyHeartbeat so that
+// they take effect immediately.
+type Heartbeat struct {
+	monitorID    db.MonitorID
+	db           *db.DB
+	grace        time.Duration
+	expected     []string
+	accepts      func(subprobe string) bool
+	readingsSink chan<- []Reading
+
+	// started stands in for the last heartbeat of expected subprobes that
+	// have never checked in.
+	started  time.Time
+	lastSeen map[string]time.Time
+
+	beats   chan db.Heartbeat
+	stop    chan struct{}
+	stopper sync.Once
+	stopped chan struct{}
+}
+
+var heartbeatProbes = struct {
+	sync.Mutex
+	m map[db.MonitorID]*Heartbeat
+}{m: make(map[db.MonitorID]*Heartbeat)}
+
+func newHeartbeat(m MonitorInfo, configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	var config HeartbeatDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize probe config")
+	}
+
+	grace := time.Duration(config.GraceMilli) * time.Millisecond
+	if grace <= 0 {
+		return nil, errors.Errorf("nonpositive grace window %s", grace)
+	}
+
+	accepts, err := config.acceptor()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &Heartbeat{
+		monitorID:    m.ID,
+		db:           m.DB,
+		grace:        grace,
+		expected:     config.ExpectedSubprobes,
+		accepts:      accepts,
+		readingsSink: readingsSink,
+		lastSeen:     make(map[string]time.Time),
+		beats:        make(chan db.Heartbeat, 100),
+		stop:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}, nil
+}
+
+// NotifyHeartbeat passes a heartbeat that has been recorded in the database
+// to the running probe for its monitor, if there is one in this process.
+func NotifyHeartbeat(h db.Heartbeat) {
+	heartbeatProbes.Lock()
+	defer heartbeatProbes.Unlock()
+
+	p, ok := heartbeatProbes.m[h.MonitorID]
+	if !ok {
+		return
+	}
+
+	select {
+	case p.beats <- h:
+	default:
+		// The probe is backed up; it will see the heartbeat when it
+		// next reloads from the database.
+	}
+}
+
+func (h *Heartbeat) Start() {
+	h.started = time.Now().UTC()
+
+	heartbeatProbes.Lock()
+	heartbeatProbes.m[h.monitorID] = h
+	heartbeatProbes.Unlock()
+
+	go h.run()
+}
+
+func (h *Heartbeat) Stop() {
+	h.stopper.Do(func() {
+		heartbeatProbes.Lock()
+		if heartbeatProbes.m[h.monitorID] == h {
+			delete(heartbeatProbes.m, h.monitorID)
+		}
+		heartbeatProbes.Unlock()
+
+		close(h.stop)
+		<-h.stopped
+	})
+}
+
+func (h *Heartbeat) run() {
+	defer close(h.stopped)
+
+	h.reload()
+	for {
+		now := time.Now().UTC()
+		readings, nextDeadline := h.check(now)
+		select {
+		case h.readingsSink <- readings:
+		case <-h.stop:
+			return
+		}
+
+		wait := heartbeatResyncPeriod
+		if !nextDeadline.IsZero() && nextDeadline.Sub(now) < wait {
+			wait = nextDeadline.Sub(now)
+		}
+		t := time.NewTimer(wait)
+
+		select {
+		case beat := <-h.beats:
+			t.Stop()
+			h.see(beat.Subprobe, beat.Recorded)
+		case <-t.C:
+			h.reload()
+		case <-h.stop:
+			t.Stop()
+			return
+		}
+	}
+}
+
+func (h *Heartbeat) reload() {
+	heartbeats, err := h.db.LoadHeartbeatsForMonitor(h.monitorID)
+	if err != nil {
+		log.WithError(err).WithField("monitor", h.monitorID).
+			Error("Could not load heartbeats. Using last known heartbeats.")
+		return
+	}
+
+	for _, beat := range heartbeats {
+		h.see(beat.Subprobe, beat.Recorded)
+	}
+}
+
+func (h *Heartbeat) see(subprobe string, recorded time.Time) {
+	// Subprobes that checked in before the monitor stopped accepting them
+	// are dropped.
+	if !h.accepts(subprobe) {
+		return
+	}
+	if recorded.After(h.lastSeen[subprobe]) {
+		h.lastSeen[subprobe] = recorded
+	}
+}
+
+// check returns readings for all known subprobes as of now, along with the
+// earliest future time at which a subprobe will miss its grace window. The
+// returned time is zero if there is no such subprobe.
+func (h *Heartbeat) check(now time.Time) ([]Reading, time.Time) {
+	subprobes := make(map[string]time.Time)
+	for _, name := range h.expected {
+		subprobes[name] = time.Time{}
+	}
+	for name, seen := range h.lastSeen {
+		subprobes[name] = seen
+	}
+
+	var nextDeadline time.Time
+	readings := make([]Reading, 0, len(subprobes))
+	for name, seen := range subprobes {
+		since := seen
+		if since.IsZero() {
+			since = h.started
+		}
+		deadline := since.Add(h.grace)
+
+		s := state.Normal
+		if !now.Before(deadline) {
+			s = state.Error
+		} else if nextDeadline.IsZero() || deadline.Before(nextDeadline) {
+			nextDeadline = deadline
+		}
+
+		readings = append(readings, Reading{
+			Subprobe: name,
+			State:    s,
+			Recorded: now,
+			Details: heartbeatDetails{
+				lastSeen: seen,
+				grace:    h.grace,
+				deadline: deadline,
+				now:      now,
+			},
+		})
+	}
+
+	return readings, nextDeadline
+}
@@ -0,0 +1,137 @@
+package probe_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/yext/revere/probe"
+	"github.com/yext/revere/test"
+)
+
+var (
+	hbId        = 5
+	hbName      = "Heartbeat"
+	hbProbeType = HeartbeatType{}
+	validHbJson = test.DefaultHeartbeatProbeJson
+)
+
+func validHeartbeatProbe() (*HeartbeatProbe, error) {
+	probe, err := LoadFromParams(hbProbeType.Id(), validHbJson)
+	if err != nil {
+		return nil, err
+	}
+
+	hbProbe, ok := probe.(HeartbeatProbe)
+	if !ok {
+		return nil, fmt.Errorf("Invalid probe loaded for probe type: %s\n", hbProbeType.Name())
+	}
+
+	return &hbProbe, nil
+}
+
+func TestHeartbeatId(t *testing.T) {
+	if int(hbProbeType.Id()) != hbId {
+		t.Errorf("Expected heartbeat probe type id: %d, got %d\n", hbId, hbProbeType.Id())
+	}
+}
+
+func TestHeartbeatName(t *testing.T) {
+	if hbProbeType.Name() != hbName {
+		t.Errorf("Expected heartbeat probe type name: %s, got %s\n", hbName, hbProbeType.Name())
+	}
+}
+
+func TestValidHeartbeat(t *testing.T) {
+	hbProbe, err := validHeartbeatProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if errs := hbProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid heartbeat probe: %v\n", errs)
+	}
+}
+
+func TestInvalidHeartbeatGracePeriod(t *testing.T) {
+	hbProbe, err := validHeartbeatProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hbProbe.GracePeriod = 0
+	if errs := hbProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid grace period: %d\n", hbProbe.GracePeriod)
+	}
+}
+
+func TestInvalidHeartbeatSubprobe(t *testing.T) {
+	hbProbe, err := validHeartbeatProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hbProbe.ExpectedSubprobes = strings.Repeat("x", 151)
+	if errs := hbProbe.Validate(); errs == nil {
+		t.Error("Expected error for overlong subprobe name")
+	}
+}
+
+func TestInvalidHeartbeatAcceptedSubprobes(t *testing.T) {
+	hbProbe, err := validHeartbeatProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hbProbe.AcceptedSubprobes = "export-("
+	if errs := hbProbe.Validate(); errs == nil {
+		t.Error("Expected error for invalid accepted subprobes regular expression")
+	}
+}
+
+func TestHeartbeatSubprobesRequired(t *testing.T) {
+	hbProbe, err := validHeartbeatProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hbProbe.ExpectedSubprobes = "\n"
+	if errs := hbProbe.Validate(); errs == nil {
+		t.Error("Expected error for heartbeat probe accepting no subprobes")
+	}
+
+	hbProbe.AcceptedSubprobes = "export-.*"
+	if errs := hbProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for heartbeat probe with accepted subprobes: %v\n", errs)
+	}
+}
+
+func TestHeartbeatAccepts(t *testing.T) {
+	cases := []struct {
+		config   HeartbeatDBModel
+		subprobe string
+		expected bool
+	}{
+		{HeartbeatDBModel{}, "nightly-export", false},
+		{HeartbeatDBModel{ExpectedSubprobes: []string{"nightly-export"}}, "nightly-export", true},
+		{HeartbeatDBModel{ExpectedSubprobes: []string{"nightly-export"}}, "hourly-sync", false},
+		{HeartbeatDBModel{AcceptedSubprobes: "shard-[0-9]+"}, "shard-12", true},
+		{HeartbeatDBModel{AcceptedSubprobes: "shard-[0-9]+"}, "evil-shard-12", false},
+		{HeartbeatDBModel{AcceptedSubprobes: "shard-[0-9]+"}, "shard-12-evil", false},
+		{HeartbeatDBModel{AcceptedSubprobes: "a|b"}, "ab", false},
+		{HeartbeatDBModel{ExpectedSubprobes: []string{"nightly-export"}, AcceptedSubprobes: "shard-.*"}, "nightly-export", true},
+	}
+
+	for _, c := range cases {
+		accepted, err := c.config.Accepts(c.subprobe)
+		if err != nil {
+			t.Errorf("Unexpected error for %+v: %s\n", c.config, err.Error())
+		} else if accepted != c.expected {
+			t.Errorf("Expected %+v to accept %s: %t, got %t\n", c.config, c.subprobe, c.expected, accepted)
+		}
+	}
+
+	if _, err := (HeartbeatDBModel{AcceptedSubprobes: "("}).Accepts("x"); err == nil {
+		t.Error("Expected error for invalid accepted subprobes regular expression")
+	}
+}
@@ -0,0 +1,51 @@
+package probe
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+)
+
+// HeartbeatDBModel defines the JSON serialization format for saving heartbeat
+// probes' settings in the database.
+type HeartbeatDBModel struct {
+	GraceMilli int64
+	// ExpectedSubprobes are subprobes that are missed if they do not check
+	// in, even if they have never checked in before.
+	ExpectedSubprobes []string
+	// AcceptedSubprobes, if set, is a regular expression that subprobes
+	// other than the expected ones may check in as if their whole name
+	// matches it.
+	AcceptedSubprobes string
+}
+
+// Accepts returns whether subprobe may check in. Heartbeats are recorded
+// without logging in, so only the expected subprobes and those matching
+// AcceptedSubprobes may.
+func (m HeartbeatDBModel) Accepts(subprobe string) (bool, error) {
+	accepts, err := m.acceptor()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return accepts(subprobe), nil
+}
+
+func (m HeartbeatDBModel) acceptor() (func(string) bool, error) {
+	expected := make(map[string]bool)
+	for _, name := range m.ExpectedSubprobes {
+		expected[name] = true
+	}
+
+	var accepted *regexp.Regexp
+	if m.AcceptedSubprobes != "" {
+		var err error
+		accepted, err = regexp.Compile(`^(?:` + m.AcceptedSubprobes + `)$`)
+		if err != nil {
+			return nil, errors.Maskf(err, "compile accepted subprobes")
+		}
+	}
+
+	return func(subprobe string) bool {
+		return expected[subprobe] || (accepted != nil && accepted.MatchString(subprobe))
+	}, nil
+}
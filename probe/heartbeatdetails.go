@@ -0,0 +1,34 @@
+package probe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yext/revere/durationfmt"
+)
+
+type heartbeatDetails struct {
+	lastSeen time.Time
+	grace    time.Duration
+	deadline time.Time
+	now      time.Time
+}
+
+func (d heartbeatDetails) Text() string {
+	graceText := durationfmt.ExactMulti().Format(d.grace)
+
+	var lastSeenText string
+	if d.lastSeen.IsZero() {
+		lastSeenText = "Never checked in"
+	} else {
+		ago := d.now.Sub(d.lastSeen).Truncate(time.Second)
+		lastSeenText = fmt.Sprintf("Last checked in %s (%s ago)",
+			d.lastSeen.UTC().Format(time.RFC1123), durationfmt.ExactMulti().Format(ago))
+	}
+
+	if d.now.Before(d.deadline) {
+		return fmt.Sprintf("%s\nGrace window: %s", lastSeenText, graceText)
+	}
+	return fmt.Sprintf("%s\nMissed grace window of %s at %s",
+		lastSeenText, graceText, d.deadline.UTC().Format(time.RFC1123))
+}
@@ -0,0 +1,20 @@
+package probe
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/yext/revere/db"
+)
+
+type heartbeatType struct{}
+
+func init() {
+	registerProbeType(heartbeatType{})
+}
+
+func (heartbeatType) ID() db.ProbeType {
+	return HeartbeatType{}.Id()
+}
+
+func (heartbeatType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	return newHeartbeat(m, config, readingsSink)
+}
@@ -0,0 +1,132 @@
+package probe
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/util"
+)
+
+type HeartbeatType struct{}
+
+type HeartbeatProbe struct {
+	HeartbeatType
+
+	GracePeriod     int64
+	GracePeriodType string
+	// ExpectedSubprobes holds one subprobe name per line.
+	ExpectedSubprobes string
+	AcceptedSubprobes string
+}
+
+func init() {
+	addType(HeartbeatType{})
+}
+
+func (HeartbeatType) Id() db.ProbeType {
+	return 5
+}
+
+func (HeartbeatType) Name() string {
+	return "Heartbeat"
+}
+
+func (HeartbeatType) loadFromParams(probe string) (VM, error) {
+	var p HeartbeatProbe
+	err := json.Unmarshal([]byte(probe), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (HeartbeatType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, error) {
+	var p HeartbeatDBModel
+	err := json.Unmarshal([]byte(encodedProbe), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	gracePeriod, gracePeriodType := util.GetPeriodAndType(p.GraceMilli)
+
+	return &HeartbeatProbe{
+		GracePeriod:       gracePeriod,
+		GracePeriodType:   gracePeriodType,
+		ExpectedSubprobes: strings.Join(p.ExpectedSubprobes, "\n"),
+		AcceptedSubprobes: p.AcceptedSubprobes,
+	}, nil
+}
+
+func (HeartbeatType) blank() (VM, error) {
+	return &HeartbeatProbe{}, nil
+}
+
+func (HeartbeatType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "heartbeat-edit.html",
+		"view": "heartbeat-view.html",
+	}
+}
+
+func (HeartbeatType) Scripts() map[string][]string {
+	return map[string][]string{}
+}
+
+func (HeartbeatType) AcceptedResourceTypes() []db.ResourceType {
+	return []db.ResourceType{}
+}
+
+func (p HeartbeatProbe) HasResource(id db.ResourceID) bool {
+	return false
+}
+
+func (p HeartbeatProbe) SerializeForFrontend() map[string]string {
+	return map[string]string{
+		"ExpectedSubprobes": p.ExpectedSubprobes,
+		"AcceptedSubprobes": p.AcceptedSubprobes,
+	}
+}
+
+func (p HeartbeatProbe) SerializeForDB() (string, error) {
+	hDB := HeartbeatDBModel{
+		GraceMilli:        util.GetMs(p.GracePeriod, p.GracePeriodType),
+		ExpectedSubprobes: nonEmptyLines(p.ExpectedSubprobes),
+		AcceptedSubprobes: p.AcceptedSubprobes,
+	}
+
+	hDBJSON, err := json.Marshal(hDB)
+	return string(hDBJSON), err
+}
+
+func (p HeartbeatProbe) Type() VMType {
+	return HeartbeatType{}
+}
+
+func (p HeartbeatProbe) Validate() (errs []string) {
+	if !isValidPeriodType(p.GracePeriodType) {
+		errs = append(errs, "Invalid grace period type")
+	}
+
+	if util.GetMs(p.GracePeriod, p.GracePeriodType) <= 0 {
+		errs = append(errs, "Invalid grace period")
+	}
+
+	expected := nonEmptyLines(p.ExpectedSubprobes)
+	for _, name := range expected {
+		if len(name) > 150 {
+			errs = append(errs, "Subprobe name is too long: "+name)
+		}
+	}
+
+	if p.AcceptedSubprobes != "" {
+		if _, err := regexp.Compile(p.AcceptedSubprobes); err != nil {
+			errs = append(errs, "Invalid accepted subprobes regular expression: "+p.AcceptedSubprobes)
+		}
+	} else if len(expected) == 0 {
+		errs = append(errs, "Heartbeat probes require expected subprobes or an accepted subprobes regular expression")
+	}
+
+	return
+}
@@ -0,0 +1,205 @@
+package probe
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+// httpCheckMaxBody limits how much of each response body is read for
+// matching.
+const httpCheckMaxBody = 1 << 20
+
+// HTTPCheck implements a probe that polls HTTP(S) URLs and assigns states
+// based on the responses' status codes, latencies, bodies and TLS
+// certificates. Each URL is its own subprobe.
+type HTTPCheck struct {
+	*Polling
+
+	urls   []string
+	client *http.Client
+
+	expectedStatusCodes map[int]bool
+	bodyMatch           func(body []byte) bool
+	bodyMatchText       string
+	failureState        state.State
+
+	latencyThresholds    []threshold
+	certExpiryThresholds []threshold
+}
+
+func newHTTPCheck(configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	hc := HTTPCheck{}
+
+	var config HTTPCheckDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize probe config")
+	}
+
+	checkPeriod := time.Duration(config.CheckPeriodMilli) * time.Millisecond
+	hc.Polling, err = NewPolling(checkPeriod, &hc, readingsSink)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	if len(config.URLs) == 0 {
+		return nil, errors.New("no URLs to check")
+	}
+	hc.urls = config.URLs
+
+	timeout := time.Duration(config.TimeoutMilli) * time.Millisecond
+	if timeout <= 0 || timeout > checkPeriod {
+		timeout = checkPeriod
+	}
+	hc.client = &http.Client{Timeout: timeout}
+
+	hc.expectedStatusCodes = make(map[int]bool)
+	for _, code := range config.ExpectedStatusCodes {
+		hc.expectedStatusCodes[code] = true
+	}
+
+	hc.bodyMatchText = config.BodyMatch
+	switch {
+	case config.BodyMatch == "":
+		hc.bodyMatch = nil
+	case config.BodyMatchIsRegexp:
+		re, err := regexp.Compile(config.BodyMatch)
+		if err != nil {
+			return nil, errors.Maskf(err, "compile body match regexp")
+		}
+		hc.bodyMatch = re.Match
+	default:
+		substring := []byte(config.BodyMatch)
+		hc.bodyMatch = func(body []byte) bool {
+			return bytes.Contains(body, substring)
+		}
+	}
+
+	if err := config.FailureState.Validate(); err != nil {
+		return nil, errors.Maskf(err, "failure state")
+	}
+	hc.failureState = config.FailureState
+
+	hc.latencyThresholds = newThresholds(
+		config.LatencyThresholdsMilli.Warning,
+		config.LatencyThresholdsMilli.Error,
+		config.LatencyThresholdsMilli.Critical)
+	hc.certExpiryThresholds = newThresholds(
+		config.CertExpiryThresholdsDays.Warning,
+		config.CertExpiryThresholdsDays.Error,
+		config.CertExpiryThresholdsDays.Critical)
+
+	return &hc, nil
+}
+
+func (hc *HTTPCheck) Check() []Reading {
+	readings := make([]Reading, len(hc.urls))
+
+	var wg sync.WaitGroup
+	for i, url := range hc.urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			readings[i] = hc.check(url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	return readings
+}
+
+func (hc *HTTPCheck) check(url string) Reading {
+	start := time.Now()
+	d := &httpCheckDetails{url: url}
+	r := Reading{Subprobe: url, State: state.Normal, Recorded: start, Details: d}
+
+	fail := func(problem string) {
+		d.problems = append(d.problems, problem)
+		r.State = worseState(r.State, hc.failureState)
+	}
+
+	resp, err := hc.client.Get(url)
+	if err != nil {
+		d.latency = time.Since(start)
+		fail("Request failed: " + err.Error())
+		return r
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, httpCheckMaxBody))
+	d.latency = time.Since(start)
+	d.statusLine = resp.Proto + " " + resp.Status
+	d.body = body
+	if err != nil {
+		fail("Could not read body: " + err.Error())
+	}
+
+	if !hc.isExpectedStatus(resp.StatusCode) {
+		fail("Unexpected status: " + resp.Status)
+	}
+
+	if hc.bodyMatch != nil && !hc.bodyMatch(body) {
+		fail("Body does not match: " + hc.bodyMatchText)
+	}
+
+	latencyMilli := float64(d.latency) / float64(time.Millisecond)
+	latencyState, latencyThreshold := evaluateThresholds(
+		hc.latencyThresholds, triggerIfFunctions[">="], latencyMilli)
+	if latencyState != state.Normal {
+		d.problems = append(d.problems, fmt.Sprintf(
+			"Latency of %.0fms is at least %gms", latencyMilli, latencyThreshold))
+		r.State = worseState(r.State, latencyState)
+	}
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		cert := earliestExpiring(resp.TLS)
+		d.certExpiry = cert.NotAfter
+		daysLeft := cert.NotAfter.Sub(start).Hours() / 24
+		certState, certThreshold := evaluateThresholds(
+			hc.certExpiryThresholds, triggerIfFunctions["<="], daysLeft)
+		if certState != state.Normal {
+			d.problems = append(d.problems, fmt.Sprintf(
+				"TLS certificate expires within %g days", certThreshold))
+			r.State = worseState(r.State, certState)
+		}
+	}
+
+	return r
+}
+
+func (hc *HTTPCheck) isExpectedStatus(code int) bool {
+	if len(hc.expectedStatusCodes) == 0 {
+		return 200 <= code && code < 300
+	}
+	return hc.expectedStatusCodes[code]
+}
+
+func earliestExpiring(cs *tls.ConnectionState) *x509.Certificate {
+	earliest := cs.PeerCertificates[0]
+	for _, cert := range cs.PeerCertificates[1:] {
+		if cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	return earliest
+}
+
+func worseState(a, b state.State) state.State {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,149 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/state"
+)
+
+// newTestHTTPCheck makes an HTTP check with config that fails in the Error
+// state.
+func newTestHTTPCheck(t *testing.T, config HTTPCheckDBModel) *HTTPCheck {
+	config.URLs = []string{"unused"}
+	config.CheckPeriodMilli = 5000
+	config.FailureState = state.Error
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to serialize config: %s\n", err.Error())
+	}
+
+	p, err := newHTTPCheck(configJSON, nil)
+	if err != nil {
+		t.Fatalf("Failed to make HTTP check: %s\n", err.Error())
+	}
+	return p.(*HTTPCheck)
+}
+
+// newTestHTTPServer serves the body "status: ok" with the status code given by
+// the status query parameter, after the delay given by the delay parameter.
+func newTestHTTPServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if delay, err := time.ParseDuration(req.URL.Query().Get("delay")); err == nil {
+			time.Sleep(delay)
+		}
+		status := http.StatusOK
+		fmt.Sscan(req.URL.Query().Get("status"), &status)
+		w.WriteHeader(status)
+		fmt.Fprint(w, "status: ok")
+	}))
+}
+
+func TestHTTPCheckStatus(t *testing.T) {
+	server := newTestHTTPServer()
+	defer server.Close()
+
+	cases := []struct {
+		expected []int
+		status   int
+		state    state.State
+	}{
+		{nil, 200, state.Normal},
+		{nil, 204, state.Normal},
+		{nil, 500, state.Error},
+		{nil, 301, state.Error},
+		{[]int{200, 404}, 404, state.Normal},
+		{[]int{200, 404}, 201, state.Error},
+	}
+
+	for _, c := range cases {
+		hc := newTestHTTPCheck(t, HTTPCheckDBModel{ExpectedStatusCodes: c.expected})
+		r := hc.check(fmt.Sprintf("%s/?status=%d", server.URL, c.status))
+		if r.State != c.state {
+			t.Errorf("Expected %s for status %d expecting %v, got %s: %s\n",
+				c.state, c.status, c.expected, r.State, r.Details.Text())
+		}
+	}
+}
+
+func TestHTTPCheckBodyMatch(t *testing.T) {
+	server := newTestHTTPServer()
+	defer server.Close()
+
+	cases := []struct {
+		match    string
+		isRegexp bool
+		state    state.State
+	}{
+		{"", false, state.Normal},
+		{"status: ok", false, state.Normal},
+		{"status: down", false, state.Error},
+		{"status: o.", false, state.Error},
+		{"^status: (ok|fine)$", true, state.Normal},
+		{"^status: (down|broken)$", true, state.Error},
+	}
+
+	for _, c := range cases {
+		hc := newTestHTTPCheck(t, HTTPCheckDBModel{BodyMatch: c.match, BodyMatchIsRegexp: c.isRegexp})
+		r := hc.check(server.URL)
+		if r.State != c.state {
+			t.Errorf("Expected %s for body match %q (regexp %v), got %s: %s\n",
+				c.state, c.match, c.isRegexp, r.State, r.Details.Text())
+		}
+		if c.state != state.Normal && !strings.Contains(r.Details.Text(), "Body does not match") {
+			t.Errorf("Expected the body mismatch to be reported, got %s\n", r.Details.Text())
+		}
+	}
+}
+
+func TestHTTPCheckLatency(t *testing.T) {
+	server := newTestHTTPServer()
+	defer server.Close()
+
+	warningMilli, errorMilli, criticalMilli := 100.0, 200.0, 300.0
+	hc := newTestHTTPCheck(t, HTTPCheckDBModel{
+		LatencyThresholdsMilli: HTTPCheckThresholdsDBModel{
+			Warning:  &warningMilli,
+			Error:    &errorMilli,
+			Critical: &criticalMilli,
+		},
+	})
+
+	cases := []struct {
+		delay string
+		state state.State
+	}{
+		{"0ms", state.Normal},
+		{"150ms", state.Warning},
+		{"250ms", state.Error},
+		{"350ms", state.Critical},
+	}
+
+	for _, c := range cases {
+		r := hc.check(server.URL + "/?delay=" + c.delay)
+		if r.State != c.state {
+			t.Errorf("Expected %s for a delay of %s, got %s: %s\n", c.state, c.delay, r.State, r.Details.Text())
+		}
+	}
+}
+
+func TestHTTPCheckConnectionFailure(t *testing.T) {
+	server := newTestHTTPServer()
+	url := server.URL
+	// Nothing is listening once the server is closed.
+	server.Close()
+
+	hc := newTestHTTPCheck(t, HTTPCheckDBModel{})
+	r := hc.check(url)
+	if r.State != state.Error {
+		t.Errorf("Expected the failure state Error for a refused connection, got %s\n", r.State)
+	}
+	if !strings.Contains(r.Details.Text(), "Request failed") {
+		t.Errorf("Expected the failed request to be reported, got %s\n", r.Details.Text())
+	}
+}
@@ -0,0 +1,110 @@
+package probe_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/yext/revere/probe"
+	"github.com/yext/revere/test"
+)
+
+var (
+	hcId        = 3
+	hcName      = "HTTP Check"
+	hcProbeType = HTTPCheckType{}
+	validHcJson = test.DefaultHTTPCheckProbeJson
+)
+
+func validHTTPCheckProbe() (*HTTPCheckProbe, error) {
+	probe, err := LoadFromParams(hcProbeType.Id(), validHcJson)
+	if err != nil {
+		return nil, err
+	}
+
+	hcProbe, ok := probe.(HTTPCheckProbe)
+	if !ok {
+		return nil, fmt.Errorf("Invalid probe loaded for probe type: %s\n", hcProbeType.Name())
+	}
+
+	return &hcProbe, nil
+}
+
+func TestHTTPCheckId(t *testing.T) {
+	if int(hcProbeType.Id()) != hcId {
+		t.Errorf("Expected HTTP check probe type id: %d, got %d\n", hcId, hcProbeType.Id())
+	}
+}
+
+func TestHTTPCheckName(t *testing.T) {
+	if hcProbeType.Name() != hcName {
+		t.Errorf("Expected HTTP check probe type name: %s, got %s\n", hcName, hcProbeType.Name())
+	}
+}
+
+func TestValidHTTPCheck(t *testing.T) {
+	hcProbe, err := validHTTPCheckProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if errs := hcProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid HTTP check probe: %v\n", errs)
+	}
+}
+
+func TestInvalidHTTPCheckURLs(t *testing.T) {
+	hcProbe, err := validHTTPCheckProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, urls := range []string{"", "\n", "ftp://example.com", "example.com/health"} {
+		hcProbe.URLs = urls
+		if errs := hcProbe.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid URLs: %q\n", urls)
+		}
+	}
+}
+
+func TestInvalidHTTPCheckStatusCodes(t *testing.T) {
+	hcProbe, err := validHTTPCheckProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, codes := range []string{"200,abc", "99", "600"} {
+		hcProbe.ExpectedStatusCodes = codes
+		if errs := hcProbe.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid status codes: %s\n", codes)
+		}
+	}
+}
+
+func TestInvalidHTTPCheckBodyMatch(t *testing.T) {
+	hcProbe, err := validHTTPCheckProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hcProbe.BodyMatch = "ok("
+	if errs := hcProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid body regexp: %s\n", hcProbe.BodyMatch)
+	}
+
+	hcProbe.BodyMatchType = "substring"
+	if errs := hcProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for substring body match: %v\n", errs)
+	}
+}
+
+func TestInvalidHTTPCheckFailureState(t *testing.T) {
+	hcProbe, err := validHTTPCheckProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	hcProbe.FailureState = "Broken"
+	if errs := hcProbe.Validate(); errs == nil {
+		t.Errorf("Expected error for invalid failure state: %s\n", hcProbe.FailureState)
+	}
+}
@@ -0,0 +1,33 @@
+package probe
+
+import "github.com/yext/revere/state"
+
+// HTTPCheckDBModel defines the JSON serialization format for saving HTTP check
+// probes' settings in the database.
+type HTTPCheckDBModel struct {
+	URLs []string
+
+	CheckPeriodMilli int64
+	TimeoutMilli     int64
+
+	// ExpectedStatusCodes lists the acceptable HTTP status codes. If empty,
+	// any 2xx status code is acceptable.
+	ExpectedStatusCodes []int
+	BodyMatch           string
+	BodyMatchIsRegexp   bool
+
+	// FailureState is the state a URL is in when it cannot be fetched or
+	// its response does not have an expected status code or body.
+	FailureState state.State
+
+	LatencyThresholdsMilli   HTTPCheckThresholdsDBModel
+	CertExpiryThresholdsDays HTTPCheckThresholdsDBModel
+}
+
+// HTTPCheckThresholdsDBModel defines the JSON serialization format for saving
+// HTTP check probes' threshold settings in the database.
+type HTTPCheckThresholdsDBModel struct {
+	Warning  *float64
+	Error    *float64
+	Critical *float64
+}
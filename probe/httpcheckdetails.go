@@ -0,0 +1,61 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// httpCheckBodySnippetLen is the maximum number of bytes of the response body
+// included in alerts.
+const httpCheckBodySnippetLen = 500
+
+type httpCheckDetails struct {
+	url        string
+	statusLine string
+	latency    time.Duration
+	body       []byte
+	certExpiry time.Time
+	problems   []string
+}
+
+func (d httpCheckDetails) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "GET %s\n", d.url)
+	if d.statusLine != "" {
+		fmt.Fprintf(&b, "Status: %s\n", d.statusLine)
+	}
+	fmt.Fprintf(&b, "Latency: %.1fms\n", float64(d.latency)/float64(time.Millisecond))
+	if !d.certExpiry.IsZero() {
+		fmt.Fprintf(&b, "TLS certificate expires: %s\n",
+			d.certExpiry.UTC().Format(time.RFC1123))
+	}
+
+	if len(d.problems) > 0 {
+		b.WriteString("\nProblems:\n")
+		for _, p := range d.problems {
+			fmt.Fprintf(&b, "* %s\n", p)
+		}
+	}
+
+	if len(d.body) > 0 {
+		fmt.Fprintf(&b, "\nBody:\n%s\n", d.bodySnippet())
+	}
+
+	return b.String()
+}
+
+func (d httpCheckDetails) bodySnippet() string {
+	if len(d.body) <= httpCheckBodySnippetLen {
+		return string(d.body)
+	}
+
+	// Avoid cutting a multi-byte character in half.
+	cut := httpCheckBodySnippetLen
+	for cut > 0 && !utf8.RuneStart(d.body[cut]) {
+		cut--
+	}
+	return string(d.body[:cut]) + "..."
+}
@@ -0,0 +1,20 @@
+package probe
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/yext/revere/db"
+)
+
+type httpCheckType struct{}
+
+func init() {
+	registerProbeType(httpCheckType{})
+}
+
+func (httpCheckType) ID() db.ProbeType {
+	return HTTPCheckType{}.Id()
+}
+
+func (httpCheckType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	return newHTTPCheck(config, readingsSink)
+}
@@ -0,0 +1,253 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/util"
+)
+
+const (
+	bodyMatchSubstring = "substring"
+	bodyMatchRegexp    = "regexp"
+)
+
+type HTTPCheckType struct{}
+
+type HTTPCheckProbe struct {
+	HTTPCheckType
+
+	URLs                 string
+	CheckPeriod          int64
+	CheckPeriodType      string
+	Timeout              int64
+	TimeoutType          string
+	ExpectedStatusCodes  string
+	BodyMatch            string
+	BodyMatchType        string
+	FailureState         string
+	LatencyThresholds    ThresholdsModel
+	CertExpiryThresholds ThresholdsModel
+}
+
+func init() {
+	addType(HTTPCheckType{})
+}
+
+func (HTTPCheckType) Id() db.ProbeType {
+	return 3
+}
+
+func (HTTPCheckType) Name() string {
+	return "HTTP Check"
+}
+
+func (HTTPCheckType) loadFromParams(probe string) (VM, error) {
+	var p HTTPCheckProbe
+	err := json.Unmarshal([]byte(probe), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (HTTPCheckType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, error) {
+	var p HTTPCheckDBModel
+	err := json.Unmarshal([]byte(encodedProbe), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	checkPeriod, checkPeriodType := util.GetPeriodAndType(p.CheckPeriodMilli)
+	timeout, timeoutType := util.GetPeriodAndType(p.TimeoutMilli)
+
+	codes := make([]string, len(p.ExpectedStatusCodes))
+	for i, code := range p.ExpectedStatusCodes {
+		codes[i] = strconv.Itoa(code)
+	}
+
+	bodyMatchType := bodyMatchSubstring
+	if p.BodyMatchIsRegexp {
+		bodyMatchType = bodyMatchRegexp
+	}
+
+	return &HTTPCheckProbe{
+		URLs:                strings.Join(p.URLs, "\n"),
+		CheckPeriod:         checkPeriod,
+		CheckPeriodType:     checkPeriodType,
+		Timeout:             timeout,
+		TimeoutType:         timeoutType,
+		ExpectedStatusCodes: strings.Join(codes, ","),
+		BodyMatch:           p.BodyMatch,
+		BodyMatchType:       bodyMatchType,
+		FailureState:        p.FailureState.String(),
+		LatencyThresholds: ThresholdsModel{
+			p.LatencyThresholdsMilli.Warning,
+			p.LatencyThresholdsMilli.Error,
+			p.LatencyThresholdsMilli.Critical,
+		},
+		CertExpiryThresholds: ThresholdsModel{
+			p.CertExpiryThresholdsDays.Warning,
+			p.CertExpiryThresholdsDays.Error,
+			p.CertExpiryThresholdsDays.Critical,
+		},
+	}, nil
+}
+
+func (HTTPCheckType) blank() (VM, error) {
+	return &HTTPCheckProbe{
+		BodyMatchType: bodyMatchSubstring,
+		FailureState:  state.Error.String(),
+	}, nil
+}
+
+func (HTTPCheckType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "http-edit.html",
+		"view": "http-view.html",
+	}
+}
+
+func (HTTPCheckType) Scripts() map[string][]string {
+	return map[string][]string{
+		"edit": []string{
+			"http-check.js",
+		},
+	}
+}
+
+func (HTTPCheckType) AcceptedResourceTypes() []db.ResourceType {
+	return []db.ResourceType{}
+}
+
+func (p HTTPCheckProbe) HasResource(id db.ResourceID) bool {
+	return false
+}
+
+func (p HTTPCheckProbe) SerializeForFrontend() map[string]string {
+	return map[string]string{
+		"URLs":      p.URLs,
+		"BodyMatch": p.BodyMatch,
+	}
+}
+
+func (p HTTPCheckProbe) SerializeForDB() (string, error) {
+	checkPeriodMilli := util.GetMs(p.CheckPeriod, p.CheckPeriodType)
+	timeoutMilli := util.GetMs(p.Timeout, p.TimeoutType)
+
+	codes, err := p.expectedStatusCodes()
+	if err != nil {
+		return "", err
+	}
+
+	failureState, err := state.FromString(p.FailureState)
+	if err != nil {
+		return "", err
+	}
+
+	hcDB := HTTPCheckDBModel{
+		URLs:                nonEmptyLines(p.URLs),
+		CheckPeriodMilli:    checkPeriodMilli,
+		TimeoutMilli:        timeoutMilli,
+		ExpectedStatusCodes: codes,
+		BodyMatch:           p.BodyMatch,
+		BodyMatchIsRegexp:   p.BodyMatchType == bodyMatchRegexp,
+		FailureState:        failureState,
+		LatencyThresholdsMilli: HTTPCheckThresholdsDBModel{
+			Warning:  p.LatencyThresholds.Warning,
+			Error:    p.LatencyThresholds.Error,
+			Critical: p.LatencyThresholds.Critical,
+		},
+		CertExpiryThresholdsDays: HTTPCheckThresholdsDBModel{
+			Warning:  p.CertExpiryThresholds.Warning,
+			Error:    p.CertExpiryThresholds.Error,
+			Critical: p.CertExpiryThresholds.Critical,
+		},
+	}
+
+	hcDBJSON, err := json.Marshal(hcDB)
+	return string(hcDBJSON), err
+}
+
+// expectedStatusCodes parses the comma-separated ExpectedStatusCodes field.
+func (p HTTPCheckProbe) expectedStatusCodes() ([]int, error) {
+	var codes []int
+	for _, s := range strings.Split(p.ExpectedStatusCodes, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func (p HTTPCheckProbe) Type() VMType {
+	return HTTPCheckType{}
+}
+
+func (p HTTPCheckProbe) Validate() (errs []string) {
+	urls := nonEmptyLines(p.URLs)
+	if len(urls) == 0 {
+		errs = append(errs, "At least one URL is required")
+	}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, "Invalid URL: "+u)
+		}
+	}
+
+	if !isValidPeriodType(p.CheckPeriodType) {
+		errs = append(errs, "Invalid check period type")
+	}
+
+	if util.GetMs(p.CheckPeriod, p.CheckPeriodType) <= 0 {
+		errs = append(errs, "Invalid check period")
+	}
+
+	if p.Timeout != 0 {
+		if !isValidPeriodType(p.TimeoutType) {
+			errs = append(errs, "Invalid timeout type")
+		}
+
+		if util.GetMs(p.Timeout, p.TimeoutType) < 0 {
+			errs = append(errs, "Invalid timeout")
+		}
+	}
+
+	codes, err := p.expectedStatusCodes()
+	if err != nil {
+		errs = append(errs, "Invalid expected status codes")
+	}
+	for _, code := range codes {
+		if code < 100 || code > 599 {
+			errs = append(errs, "Invalid expected status code: "+strconv.Itoa(code))
+		}
+	}
+
+	switch p.BodyMatchType {
+	case bodyMatchSubstring:
+	case bodyMatchRegexp:
+		if _, err := regexp.Compile(p.BodyMatch); err != nil {
+			errs = append(errs, "Invalid body match regular expression: "+err.Error())
+		}
+	default:
+		errs = append(errs, "Invalid body match type")
+	}
+
+	if _, err := state.FromString(p.FailureState); err != nil {
+		errs = append(errs, "Invalid failure state")
+	}
+
+	return
+}
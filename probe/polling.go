@@ -5,6 +5,10 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/metrics"
+	"github.com/yext/revere/state"
 )
 
 // Polling helps implement probes that check conditions at regular intervals.
@@ -15,6 +19,9 @@ type Polling struct {
 	checker      Checker
 	readingsSink chan<- []Reading
 
+	// monitor labels the metrics of this probe's checks. It is set by New.
+	monitor string
+
 	stop    chan struct{}
 	stopper sync.Once
 	stopped chan struct{}
@@ -54,13 +61,37 @@ func (p *Polling) poll() {
 	for {
 		select {
 		case <-t.C:
-			p.readingsSink <- p.checker.Check()
+			p.readingsSink <- p.check()
 		case <-p.stop:
 			return
 		}
 	}
 }
 
+// check checks once, recording how long the check took and whether it could
+// tell the state of what is being monitored. Probes report checks that fail,
+// e.g. because a query timed out, as Unknown readings.
+func (p *Polling) check() []Reading {
+	start := time.Now()
+	readings := p.checker.Check()
+	if p.monitor == "" {
+		return readings
+	}
+
+	metrics.ProbeCheckDuration.WithLabelValues(p.monitor).Observe(time.Since(start).Seconds())
+	for _, r := range readings {
+		if r.State == state.Unknown {
+			metrics.ProbeCheckErrors.WithLabelValues(p.monitor).Inc()
+			break
+		}
+	}
+	return readings
+}
+
+func (p *Polling) setMonitor(id db.MonitorID) {
+	p.monitor = metrics.Monitor(int64(id))
+}
+
 // Checker is used by Polling to actually check the thing being monitored.
 type Checker interface {
 	Check() []Reading
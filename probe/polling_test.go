@@ -0,0 +1,49 @@
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/yext/revere/metrics"
+	"github.com/yext/revere/state"
+)
+
+type readingsChecker []Reading
+
+func (c readingsChecker) Check() []Reading {
+	return c
+}
+
+func TestPollingCheckMetrics(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		monitor  string
+		readings []Reading
+		errors   float64
+	}{
+		{"1001", []Reading{{"a", state.Normal, now, nil}, {"b", state.Critical, now, nil}}, 0},
+		{"1002", []Reading{{"_", state.Unknown, now, nil}}, 1},
+		{"1003", []Reading{{"a", state.Unknown, now, nil}, {"b", state.Unknown, now, nil}}, 1},
+	}
+
+	for _, c := range cases {
+		p := &Polling{checker: readingsChecker(c.readings), monitor: c.monitor}
+		p.check()
+
+		var duration dto.Metric
+		err := metrics.ProbeCheckDuration.WithLabelValues(c.monitor).(prometheus.Histogram).Write(&duration)
+		if err != nil {
+			t.Fatalf("Could not read check duration for monitor %s: %s", c.monitor, err)
+		}
+		if n := duration.GetHistogram().GetSampleCount(); n != 1 {
+			t.Errorf("Expected 1 check duration for monitor %s, got %d\n", c.monitor, n)
+		}
+		if errors := testutil.ToFloat64(metrics.ProbeCheckErrors.WithLabelValues(c.monitor)); errors != c.errors {
+			t.Errorf("Expected %v check errors for monitor %s, got %v\n", c.errors, c.monitor, errors)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package probe
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx/types"
@@ -38,13 +39,60 @@ type Details interface {
 	Text() string
 }
 
-// New makes a Probe of the given type and settings. The Probe will send its
-// readings to the provided channel.
-func New(tx *db.Tx, typeID db.ProbeType, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
-	// TODO(eefi): Implement Type dictionary system.
-	if typeID != 1 {
+// GraphDetails is implemented by details that can be shown as a graph image.
+type GraphDetails interface {
+	Details
+
+	// GraphImageURL returns the URL of an image graphing the reading.
+	GraphImageURL() string
+}
+
+// Type defines a common abstraction for types of probes.
+type Type interface {
+	ID() db.ProbeType
+
+	// New returns a new instance of a probe of this type for the monitor
+	// described by m. The probe will send its readings to readingsSink.
+	New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error)
+}
+
+// MonitorInfo describes the monitor a probe is being made for. Probe types
+// that need to consult the database after being made use DB rather than the
+// transaction passed to New.
+type MonitorInfo struct {
+	ID db.MonitorID
+	DB *db.DB
+}
+
+var (
+	daemonProbeTypes = make(map[db.ProbeType]Type)
+)
+
+// New makes a Probe of the given type and settings for the monitor described
+// by m. The Probe will send its readings to the provided channel.
+func New(tx *db.Tx, m MonitorInfo, typeID db.ProbeType, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	probeType, found := daemonProbeTypes[typeID]
+	if !found {
 		return nil, errors.Errorf("unknown probe type %d", typeID)
 	}
 
-	return graphiteThresholdType{}.New(tx, config, readingsSink)
+	p, err := probeType.New(tx, m, config, readingsSink)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Polling probes record metrics about their checks.
+	if p, ok := p.(interface{ setMonitor(db.MonitorID) }); ok {
+		p.setMonitor(m.ID)
+	}
+	return p, nil
+}
+
+// registerProbeType registers a probe type onto a type dictionary.
+func registerProbeType(t Type) {
+	if _, exists := daemonProbeTypes[t.ID()]; !exists {
+		daemonProbeTypes[t.ID()] = t
+	} else {
+		panic(fmt.Sprintf("A probe type with id %d already exists", t.ID()))
+	}
 }
@@ -0,0 +1,92 @@
+package probe_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/yext/revere/probe"
+	"github.com/yext/revere/test"
+)
+
+var (
+	ptId        = 2
+	ptName      = "Prometheus Threshold"
+	ptProbeType = PrometheusThresholdType{}
+	validPtJson = test.DefaultPrometheusProbeJson
+)
+
+func validPrometheusThresholdProbe() (*PrometheusThresholdProbe, error) {
+	probe, err := LoadFromParams(ptProbeType.Id(), validPtJson)
+	if err != nil {
+		return nil, err
+	}
+
+	ptProbe, ok := probe.(PrometheusThresholdProbe)
+	if !ok {
+		return nil, fmt.Errorf("Invalid probe loaded for probe type: %s\n", ptProbeType.Name())
+	}
+
+	return &ptProbe, nil
+}
+
+func TestPrometheusThresholdId(t *testing.T) {
+	if int(ptProbeType.Id()) != ptId {
+		t.Errorf("Expected prometheus threshold probe type id: %d, got %d\n", ptId, ptProbeType.Id())
+	}
+}
+
+func TestPrometheusThresholdName(t *testing.T) {
+	if ptProbeType.Name() != ptName {
+		t.Errorf("Expected prometheus threshold probe type name: %s, got %s\n", ptName, ptProbeType.Name())
+	}
+}
+
+func TestValidPrometheusThreshold(t *testing.T) {
+	ptProbe, err := validPrometheusThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if errs := ptProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid prometheus threshold probe: %v\n", errs)
+	}
+}
+
+func TestInvalidPrometheusThresholdQuery(t *testing.T) {
+	ptProbe, err := validPrometheusThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ptProbe.Query = ""
+	errs := ptProbe.Validate()
+	if errs == nil {
+		t.Error("Expected error for empty prometheus query")
+	}
+}
+
+func TestInvalidPrometheusThresholdStep(t *testing.T) {
+	ptProbe, err := validPrometheusThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ptProbe.Step = 0
+	errs := ptProbe.Validate()
+	if errs == nil {
+		t.Errorf("Expected error for invalid step: %d\n", ptProbe.Step)
+	}
+}
+
+func TestInvalidPrometheusThresholdAuditFunction(t *testing.T) {
+	ptProbe, err := validPrometheusThresholdProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ptProbe.AuditFunction = "median"
+	errs := ptProbe.Validate()
+	if errs == nil {
+		t.Errorf("Expected error for invalid audit function: %s\n", ptProbe.AuditFunction)
+	}
+}
@@ -0,0 +1,163 @@
+package probe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/resource"
+	"github.com/yext/revere/state"
+)
+
+// PrometheusThreshold implements a probe that assigns states based on whether
+// the series returned by a PromQL query are above or below various constant
+// values.
+type PrometheusThreshold struct {
+	*Polling
+
+	prometheusBase     string
+	query              string
+	subprobeLabels     []string
+	step               time.Duration
+	timeToAudit        time.Duration
+	recentTimeToIgnore time.Duration
+
+	thresholds      []threshold
+	summarizeValues auditFunction
+	triggersOn      func(summaryValue, threshold float64) bool
+
+	auditFunctionName string
+	triggerIfText     string
+}
+
+func newPrometheusThreshold(tx *db.Tx, configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	pt := PrometheusThreshold{}
+
+	var config PrometheusThresholdDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize probe config")
+	}
+
+	checkPeriod := time.Duration(config.CheckPeriodMilli) * time.Millisecond
+	pt.Polling, err = NewPolling(checkPeriod, &pt, readingsSink)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	dbds, err := tx.LoadResource(db.ResourceID(config.ResourceID))
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	if dbds == nil {
+		return nil, errors.Errorf("no resource found: %d", config.ResourceID)
+	}
+
+	ds, err := resource.LoadFromDB(resource.Prometheus{}.Id(), dbds.Resource)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	pds, found := ds.(*resource.PrometheusResource)
+	if !found {
+		return nil, errors.New("not a prometheus resource")
+	}
+
+	pt.prometheusBase = fmt.Sprintf("http://%s/", pds.URL)
+	pt.query = config.Query
+	pt.subprobeLabels = config.SubprobeLabels
+	pt.step = time.Duration(config.StepMilli) * time.Millisecond
+	pt.timeToAudit = time.Duration(config.TimeToAuditMilli) * time.Millisecond
+	pt.recentTimeToIgnore = time.Duration(config.RecentTimeToIgnoreMilli) * time.Millisecond
+
+	pt.thresholds = newThresholds(
+		config.Thresholds.Warning, config.Thresholds.Error, config.Thresholds.Critical)
+
+	var ok bool
+
+	pt.summarizeValues, ok = auditFunctions[config.AuditFunction]
+	if !ok {
+		return nil, errors.Errorf("unknown audit function: %s", config.AuditFunction)
+	}
+
+	pt.triggersOn, ok = triggerIfFunctions[config.TriggerIf]
+	if !ok {
+		return nil, errors.Errorf("unknown trigger if: %s", config.TriggerIf)
+	}
+
+	pt.auditFunctionName = config.AuditFunction
+	pt.triggerIfText = config.TriggerIf
+
+	return &pt, nil
+}
+
+func (pt *PrometheusThreshold) Check() []Reading {
+	now := time.Now()
+
+	auditEnd := now.Add(-pt.recentTimeToIgnore)
+
+	p := resource.PrometheusServer{Base: pt.prometheusBase}
+
+	series, err := p.QueryRange(pt.query, auditEnd.Add(-pt.timeToAudit), auditEnd, pt.step)
+	if err != nil {
+		// TODO(eefi): Include this probe's monitor's ID.
+		log.WithError(err).Error("Could not query Prometheus.")
+
+		return []Reading{{"_", state.Unknown, now, nil}}
+	}
+
+	readings := make([]Reading, 0, len(series)+1)
+	for _, s := range series {
+		summaryValue := pt.summarizeValues(s.Values, pt.step)
+		if math.IsNaN(summaryValue) {
+			// Series could not be summarized, e.g. it was all NaNs.
+			continue
+		}
+
+		r := Reading{pt.subprobeName(s), state.Normal, now, nil}
+
+		var triggeredThreshold float64
+		r.State, triggeredThreshold = evaluateThresholds(pt.thresholds, pt.triggersOn, summaryValue)
+
+		r.Details = prometheusThresholdDetails{
+			auditFunction: pt.auditFunctionName,
+			timeToAudit:   pt.timeToAudit,
+			triggerIf:     pt.triggerIfText,
+
+			measured:  summaryValue,
+			threshold: triggeredThreshold,
+
+			prometheus:  &p,
+			query:       pt.query,
+			series:      s,
+			measuredEnd: auditEnd,
+		}
+
+		readings = append(readings, r)
+	}
+	readings = append(readings, Reading{"_", state.Normal, now, nil})
+
+	return readings
+}
+
+// subprobeName maps the label set of s to a subprobe name. If the probe was
+// configured with subprobe labels, the values of those labels are joined with
+// dots, Graphite style. Otherwise, the full label set is used.
+func (pt *PrometheusThreshold) subprobeName(s resource.PrometheusSeries) string {
+	if len(pt.subprobeLabels) == 0 {
+		return s.Name()
+	}
+
+	values := make([]string, len(pt.subprobeLabels))
+	for i, label := range pt.subprobeLabels {
+		values[i] = s.Labels[label]
+	}
+	return strings.Join(values, ".")
+}
@@ -0,0 +1,34 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/yext/revere/resource"
+)
+
+func TestPrometheusSubprobeName(t *testing.T) {
+	s := resource.PrometheusSeries{Labels: map[string]string{
+		"__name__": "up",
+		"job":      "api",
+		"instance": "a:9090",
+		"zone":     "us",
+	}}
+
+	cases := []struct {
+		labels   []string
+		expected string
+	}{
+		{nil, `up{instance="a:9090",job="api",zone="us"}`},
+		{[]string{"job"}, "api"},
+		{[]string{"job", "instance"}, "api.a:9090"},
+		{[]string{"instance", "job"}, "a:9090.api"},
+		{[]string{"zone", "missing", "job"}, "us..api"},
+	}
+
+	for _, c := range cases {
+		pt := &PrometheusThreshold{subprobeLabels: c.labels}
+		if name := pt.subprobeName(s); name != c.expected {
+			t.Errorf("Expected subprobe name %s for labels %v, got %s\n", c.expected, c.labels, name)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package probe
+
+// PrometheusThresholdDBModel defines the JSON serialization format for saving
+// Prometheus threshold probes' settings in the database.
+type PrometheusThresholdDBModel struct {
+	ResourceID     int64
+	Query          string
+	SubprobeLabels []string
+
+	Thresholds PrometheusThresholdThresholdsDBModel
+	TriggerIf  string
+
+	CheckPeriodMilli int64
+	StepMilli        int64
+
+	TimeToAuditMilli        int64
+	RecentTimeToIgnoreMilli int64
+	AuditFunction           string
+}
+
+// PrometheusThresholdThresholdsDBModel defines the JSON serialization format
+// for saving Prometheus threshold probes' threshold settings in the database.
+type PrometheusThresholdThresholdsDBModel struct {
+	Warning  *float64
+	Error    *float64
+	Critical *float64
+}
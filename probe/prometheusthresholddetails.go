@@ -0,0 +1,51 @@
+package probe
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yext/revere/durationfmt"
+	"github.com/yext/revere/resource"
+)
+
+type prometheusThresholdDetails struct {
+	auditFunction string
+	timeToAudit   time.Duration
+	triggerIf     string
+
+	measured  float64
+	threshold float64
+
+	prometheus  *resource.PrometheusServer
+	query       string
+	series      resource.PrometheusSeries
+	measuredEnd time.Time
+}
+
+func (d prometheusThresholdDetails) Text() string {
+	timeToAuditText := durationfmt.ExactMulti().Format(d.timeToAudit)
+	measuredText := fmt.Sprintf("%s of last %s", d.auditFunction, timeToAuditText)
+
+	var thresholdText, thresholdVal string
+	if math.IsNaN(d.threshold) {
+		thresholdText, thresholdVal = "", ""
+	} else {
+		thresholdText = fmt.Sprintf(" %s threshold", d.triggerIf)
+		thresholdVal = fmt.Sprintf(" %s %g", d.triggerIf, d.threshold)
+	}
+
+	firstLine := fmt.Sprintf("%s%s: %g%s",
+		measuredText, thresholdText, d.measured, thresholdVal)
+
+	return fmt.Sprintf("%s\n\nSeries: %s\nGraph: %s\n", firstLine, d.series.Name(), d.graphURL())
+}
+
+func (d prometheusThresholdDetails) graphURL() string {
+	contextTime := d.timeToAudit
+	if contextTime < 30*time.Minute {
+		contextTime = 30 * time.Minute
+	}
+
+	return d.prometheus.GraphURL(d.query, d.measuredEnd.Add(contextTime), d.timeToAudit+3*contextTime)
+}
@@ -0,0 +1,20 @@
+package probe
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/yext/revere/db"
+)
+
+type prometheusThresholdType struct{}
+
+func init() {
+	registerProbeType(prometheusThresholdType{})
+}
+
+func (prometheusThresholdType) ID() db.ProbeType {
+	return PrometheusThresholdType{}.Id()
+}
+
+func (prometheusThresholdType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	return newPrometheusThreshold(tx, config, readingsSink)
+}
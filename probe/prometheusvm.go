@@ -0,0 +1,253 @@
+package probe
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/resource"
+	"github.com/yext/revere/util"
+)
+
+type PrometheusThresholdType struct{}
+
+type PrometheusThresholdProbe struct {
+	PrometheusThresholdType
+
+	URL               string
+	ResourceID        db.ResourceID
+	Query             string
+	SubprobeLabels    string
+	Thresholds        ThresholdsModel
+	AuditFunction     string
+	CheckPeriod       int64
+	CheckPeriodType   string
+	Step              int64
+	StepType          string
+	TriggerIf         string
+	AuditPeriod       int64
+	AuditPeriodType   string
+	IgnoredPeriod     int64
+	IgnoredPeriodType string
+}
+
+func init() {
+	addType(PrometheusThresholdType{})
+}
+
+func (PrometheusThresholdType) Id() db.ProbeType {
+	return 2
+}
+
+func (PrometheusThresholdType) Name() string {
+	return "Prometheus Threshold"
+}
+
+func (PrometheusThresholdType) loadFromParams(probe string) (VM, error) {
+	var p PrometheusThresholdProbe
+	err := json.Unmarshal([]byte(probe), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (PrometheusThresholdType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, error) {
+	var p PrometheusThresholdDBModel
+	err := json.Unmarshal([]byte(encodedProbe), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	checkPeriod, checkPeriodType := util.GetPeriodAndType(p.CheckPeriodMilli)
+	step, stepType := util.GetPeriodAndType(p.StepMilli)
+	auditPeriod, auditPeriodType := util.GetPeriodAndType(p.TimeToAuditMilli)
+	ignoredPeriod, ignoredPeriodType := util.GetPeriodAndType(p.RecentTimeToIgnoreMilli)
+
+	dbds, err := tx.LoadResource(db.ResourceID(p.ResourceID))
+	if err != nil {
+		return nil, err
+	}
+
+	if dbds == nil {
+		return nil, errors.Errorf("no resource found: %d", p.ResourceID)
+	}
+
+	ds, err := resource.LoadFromDB(resource.Prometheus{}.Id(), dbds.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	pds, found := ds.(*resource.PrometheusResource)
+	if !found {
+		return nil, errors.New("not a prometheus resource")
+	}
+
+	return &PrometheusThresholdProbe{
+		URL:            pds.URL,
+		ResourceID:     db.ResourceID(p.ResourceID),
+		Query:          p.Query,
+		SubprobeLabels: strings.Join(p.SubprobeLabels, ","),
+		Thresholds: ThresholdsModel{
+			p.Thresholds.Warning,
+			p.Thresholds.Error,
+			p.Thresholds.Critical,
+		},
+		AuditFunction:     p.AuditFunction,
+		CheckPeriod:       checkPeriod,
+		CheckPeriodType:   checkPeriodType,
+		Step:              step,
+		StepType:          stepType,
+		TriggerIf:         p.TriggerIf,
+		AuditPeriod:       auditPeriod,
+		AuditPeriodType:   auditPeriodType,
+		IgnoredPeriod:     ignoredPeriod,
+		IgnoredPeriodType: ignoredPeriodType,
+	}, nil
+}
+
+func (PrometheusThresholdType) blank() (VM, error) {
+	return &PrometheusThresholdProbe{}, nil
+}
+
+func (PrometheusThresholdType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "prometheus-edit.html",
+		"view": "prometheus-view.html",
+	}
+}
+
+func (PrometheusThresholdType) Scripts() map[string][]string {
+	return map[string][]string{
+		"edit": []string{
+			"prometheus-threshold.js",
+			"graphite-resource-loader.js",
+		},
+	}
+}
+
+func (PrometheusThresholdType) AcceptedResourceTypes() []db.ResourceType {
+	return []db.ResourceType{
+		resource.Prometheus{}.Id(),
+	}
+}
+
+func (p PrometheusThresholdProbe) HasResource(id db.ResourceID) bool {
+	return p.ResourceID == id
+}
+
+func (p PrometheusThresholdProbe) SerializeForFrontend() map[string]string {
+	var warningStr, errorStr, criticalStr string
+	if p.Thresholds.Warning != nil {
+		warningStr = strconv.FormatFloat(*p.Thresholds.Warning, 'f', -1, 64)
+	}
+	if p.Thresholds.Error != nil {
+		errorStr = strconv.FormatFloat(*p.Thresholds.Error, 'f', -1, 64)
+	}
+	if p.Thresholds.Critical != nil {
+		criticalStr = strconv.FormatFloat(*p.Thresholds.Critical, 'f', -1, 64)
+	}
+	return map[string]string{
+		"Query":    p.Query,
+		"URL":      p.URL,
+		"Warning":  warningStr,
+		"Error":    errorStr,
+		"Critical": criticalStr,
+	}
+}
+
+func (p PrometheusThresholdProbe) SerializeForDB() (string, error) {
+	checkPeriodMilli := util.GetMs(p.CheckPeriod, p.CheckPeriodType)
+	stepMilli := util.GetMs(p.Step, p.StepType)
+	auditPeriodMilli := util.GetMs(p.AuditPeriod, p.AuditPeriodType)
+	ignoredPeriodMilli := util.GetMs(p.IgnoredPeriod, p.IgnoredPeriodType)
+
+	ptDB := PrometheusThresholdDBModel{
+		ResourceID:     int64(p.ResourceID),
+		Query:          p.Query,
+		SubprobeLabels: p.subprobeLabels(),
+		Thresholds: PrometheusThresholdThresholdsDBModel{
+			Warning:  p.Thresholds.Warning,
+			Error:    p.Thresholds.Error,
+			Critical: p.Thresholds.Critical,
+		},
+		TriggerIf:               p.TriggerIf,
+		CheckPeriodMilli:        checkPeriodMilli,
+		StepMilli:               stepMilli,
+		TimeToAuditMilli:        auditPeriodMilli,
+		RecentTimeToIgnoreMilli: ignoredPeriodMilli,
+		AuditFunction:           p.AuditFunction,
+	}
+
+	ptDBJSON, err := json.Marshal(ptDB)
+	return string(ptDBJSON), err
+}
+
+// subprobeLabels splits the comma-separated SubprobeLabels field, dropping
+// empty entries.
+func (p PrometheusThresholdProbe) subprobeLabels() []string {
+	var labels []string
+	for _, label := range strings.Split(p.SubprobeLabels, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func (p PrometheusThresholdProbe) Type() VMType {
+	return PrometheusThresholdType{}
+}
+
+func (p PrometheusThresholdProbe) Validate() (errs []string) {
+	if p.Query == "" {
+		errs = append(errs, "Prometheus query is required")
+	}
+
+	if !isValidPeriodType(p.CheckPeriodType) {
+		errs = append(errs, "Invalid check period type")
+	}
+
+	if !isValidPeriodType(p.StepType) {
+		errs = append(errs, "Invalid step type")
+	}
+
+	if !isValidPeriodType(p.AuditPeriodType) {
+		errs = append(errs, "Invalid audit period type")
+	}
+
+	if util.GetMs(p.CheckPeriod, p.CheckPeriodType) <= 0 {
+		errs = append(errs, "Invalid check period")
+	}
+
+	if util.GetMs(p.Step, p.StepType) <= 0 {
+		errs = append(errs, "Invalid step")
+	}
+
+	if util.GetMs(p.AuditPeriod, p.AuditPeriodType) <= 0 {
+		errs = append(errs, "Invalid audit period")
+	}
+
+	if _, ok := auditFunctions[p.AuditFunction]; !ok {
+		errs = append(errs, "Invalid audit function")
+	}
+
+	if _, ok := triggerIfFunctions[p.TriggerIf]; !ok {
+		errs = append(errs, "Invalid trigger if")
+	}
+
+	return
+}
+
+func isValidPeriodType(periodType string) bool {
+	for _, vpt := range validPeriodTypes {
+		if periodType == vpt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+// Reachability implements a probe that checks that TCP addresses accept
+// connections and that DNS names resolve as expected. Each address and name is
+// its own subprobe.
+type Reachability struct {
+	*Polling
+
+	tcpTargets []string
+	dnsTargets []ReachabilityDNSTargetDBModel
+	timeout    time.Duration
+	resolver   *net.Resolver
+
+	latencyThresholds []threshold
+	failureThresholds []threshold
+
+	// consecutiveFailures is keyed by subprobe name. It is only accessed
+	// from Check, which Polling never runs concurrently.
+	consecutiveFailures map[string]int
+}
+
+func newReachability(configJSON types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	r := Reachability{}
+
+	var config ReachabilityDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize probe config")
+	}
+
+	checkPeriod := time.Duration(config.CheckPeriodMilli) * time.Millisecond
+	r.Polling, err = NewPolling(checkPeriod, &r, readingsSink)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	if len(config.TCPTargets) == 0 && len(config.DNSTargets) == 0 {
+		return nil, errors.New("no targets to check")
+	}
+	r.tcpTargets = config.TCPTargets
+	r.dnsTargets = config.DNSTargets
+
+	r.timeout = time.Duration(config.TimeoutMilli) * time.Millisecond
+	if r.timeout <= 0 || r.timeout > checkPeriod {
+		r.timeout = checkPeriod
+	}
+	r.resolver = net.DefaultResolver
+
+	r.latencyThresholds = newThresholds(
+		config.LatencyThresholdsMilli.Warning,
+		config.LatencyThresholdsMilli.Error,
+		config.LatencyThresholdsMilli.Critical)
+	r.failureThresholds = newThresholds(
+		config.FailureThresholds.Warning,
+		config.FailureThresholds.Error,
+		config.FailureThresholds.Critical)
+	if len(r.failureThresholds) == 0 {
+		one := 1.0
+		r.failureThresholds = newThresholds(nil, &one, nil)
+	}
+
+	r.consecutiveFailures = make(map[string]int)
+
+	return &r, nil
+}
+
+func (r *Reachability) Check() []Reading {
+	readings := make([]Reading, 0, len(r.tcpTargets)+len(r.dnsTargets))
+	for _, addr := range r.tcpTargets {
+		readings = append(readings, r.checkTCP(addr))
+	}
+	for _, target := range r.dnsTargets {
+		readings = append(readings, r.checkDNS(target))
+	}
+	return readings
+}
+
+func (r *Reachability) checkTCP(addr string) Reading {
+	start := time.Now()
+	d := &reachabilityDetails{kind: "TCP connect", target: addr}
+
+	conn, err := net.DialTimeout("tcp", addr, r.timeout)
+	d.latency = time.Since(start)
+	if err != nil {
+		d.problems = append(d.problems, err.Error())
+	} else {
+		d.result = "Connected to " + conn.RemoteAddr().String()
+		conn.Close()
+	}
+
+	return r.reading("tcp:"+addr, start, d)
+}
+
+func (r *Reachability) checkDNS(target ReachabilityDNSTargetDBModel) Reading {
+	start := time.Now()
+	d := &reachabilityDetails{kind: "DNS lookup", target: target.Name}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	addrs, err := r.resolver.LookupHost(ctx, target.Name)
+	var cname string
+	if err == nil && hasHostExpectation(target.Expected) {
+		cname, err = r.resolver.LookupCNAME(ctx, target.Name)
+	}
+	d.latency = time.Since(start)
+
+	if err != nil {
+		d.problems = append(d.problems, err.Error())
+	} else {
+		d.result = "Addresses: " + strings.Join(addrs, ", ")
+		if cname != "" {
+			d.result += "\nCanonical name: " + cname
+		}
+		for _, expected := range target.Expected {
+			if !matchesRecord(expected, addrs, cname) {
+				d.problems = append(d.problems, "Expected record not found: "+expected)
+			}
+		}
+	}
+
+	return r.reading("dns:"+target.Name, start, d)
+}
+
+// reading assigns a state to a checked target from its details and records
+// its consecutive failures.
+func (r *Reachability) reading(subprobe string, recorded time.Time, d *reachabilityDetails) Reading {
+	s := state.Normal
+
+	if len(d.problems) > 0 {
+		r.consecutiveFailures[subprobe]++
+		d.consecutiveFailures = r.consecutiveFailures[subprobe]
+
+		failureState, failureThreshold := evaluateThresholds(
+			r.failureThresholds, triggerIfFunctions[">="], float64(d.consecutiveFailures))
+		if failureState != state.Normal {
+			d.problems = append(d.problems, fmt.Sprintf(
+				"%d consecutive failures is at least %g", d.consecutiveFailures, failureThreshold))
+		}
+		s = worseState(s, failureState)
+	} else {
+		delete(r.consecutiveFailures, subprobe)
+
+		latencyMilli := float64(d.latency) / float64(time.Millisecond)
+		latencyState, latencyThreshold := evaluateThresholds(
+			r.latencyThresholds, triggerIfFunctions[">="], latencyMilli)
+		if latencyState != state.Normal {
+			d.problems = append(d.problems, fmt.Sprintf(
+				"Latency of %.0fms is at least %gms", latencyMilli, latencyThreshold))
+		}
+		s = worseState(s, latencyState)
+	}
+
+	return Reading{Subprobe: subprobe, State: s, Recorded: recorded, Details: d}
+}
+
+func hasHostExpectation(expected []string) bool {
+	for _, e := range expected {
+		if net.ParseIP(e) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRecord(expected string, addrs []string, cname string) bool {
+	if ip := net.ParseIP(expected); ip != nil {
+		for _, addr := range addrs {
+			if ip.Equal(net.ParseIP(addr)) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.EqualFold(strings.TrimSuffix(expected, "."), strings.TrimSuffix(cname, "."))
+}
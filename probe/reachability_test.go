@@ -0,0 +1,93 @@
+package probe_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/yext/revere/probe"
+	"github.com/yext/revere/test"
+)
+
+var (
+	rId        = 4
+	rName      = "TCP/DNS Reachability"
+	rProbeType = ReachabilityType{}
+	validRJson = test.DefaultReachabilityProbeJson
+)
+
+func validReachabilityProbe() (*ReachabilityProbe, error) {
+	probe, err := LoadFromParams(rProbeType.Id(), validRJson)
+	if err != nil {
+		return nil, err
+	}
+
+	rProbe, ok := probe.(ReachabilityProbe)
+	if !ok {
+		return nil, fmt.Errorf("Invalid probe loaded for probe type: %s\n", rProbeType.Name())
+	}
+
+	return &rProbe, nil
+}
+
+func TestReachabilityId(t *testing.T) {
+	if int(rProbeType.Id()) != rId {
+		t.Errorf("Expected reachability probe type id: %d, got %d\n", rId, rProbeType.Id())
+	}
+}
+
+func TestReachabilityName(t *testing.T) {
+	if rProbeType.Name() != rName {
+		t.Errorf("Expected reachability probe type name: %s, got %s\n", rName, rProbeType.Name())
+	}
+}
+
+func TestValidReachability(t *testing.T) {
+	rProbe, err := validReachabilityProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if errs := rProbe.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for valid reachability probe: %v\n", errs)
+	}
+}
+
+func TestReachabilityNoTargets(t *testing.T) {
+	rProbe, err := validReachabilityProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	rProbe.TCPTargets = "\n"
+	rProbe.DNSTargets = ""
+	if errs := rProbe.Validate(); errs == nil {
+		t.Error("Expected error for probe with no targets")
+	}
+}
+
+func TestInvalidReachabilityTCPTargets(t *testing.T) {
+	rProbe, err := validReachabilityProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, target := range []string{"db1.example.com", ":3306", "db1.example.com:0", "db1.example.com:http"} {
+		rProbe.TCPTargets = target
+		if errs := rProbe.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid TCP target: %s\n", target)
+		}
+	}
+}
+
+func TestInvalidReachabilityFailureThreshold(t *testing.T) {
+	rProbe, err := validReachabilityProbe()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	zero := 0.0
+	rProbe.FailureThresholds.Warning = &zero
+	if errs := rProbe.Validate(); errs == nil {
+		t.Error("Expected error for failure threshold below 1")
+	}
+}
@@ -0,0 +1,32 @@
+package probe
+
+// ReachabilityDBModel defines the JSON serialization format for saving
+// reachability probes' settings in the database.
+type ReachabilityDBModel struct {
+	// TCPTargets are host:port addresses to connect to.
+	TCPTargets []string
+	DNSTargets []ReachabilityDNSTargetDBModel
+
+	CheckPeriodMilli int64
+	TimeoutMilli     int64
+
+	LatencyThresholdsMilli ReachabilityThresholdsDBModel
+	// FailureThresholds are numbers of consecutive failed checks.
+	FailureThresholds ReachabilityThresholdsDBModel
+}
+
+// ReachabilityDNSTargetDBModel defines the JSON serialization format for a
+// name to resolve. Each expected record is either an IP address that must be
+// among the name's addresses or a host name that must be its canonical name.
+type ReachabilityDNSTargetDBModel struct {
+	Name     string
+	Expected []string
+}
+
+// ReachabilityThresholdsDBModel defines the JSON serialization format for
+// saving reachability probes' threshold settings in the database.
+type ReachabilityThresholdsDBModel struct {
+	Warning  *float64
+	Error    *float64
+	Critical *float64
+}
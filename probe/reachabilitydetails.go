@@ -0,0 +1,38 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type reachabilityDetails struct {
+	kind                string
+	target              string
+	result              string
+	latency             time.Duration
+	consecutiveFailures int
+	problems            []string
+}
+
+func (d reachabilityDetails) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s\n", d.kind, d.target)
+	fmt.Fprintf(&b, "Latency: %.1fms\n", float64(d.latency)/float64(time.Millisecond))
+	if d.consecutiveFailures > 0 {
+		fmt.Fprintf(&b, "Consecutive failures: %d\n", d.consecutiveFailures)
+	}
+	if d.result != "" {
+		fmt.Fprintf(&b, "%s\n", d.result)
+	}
+
+	if len(d.problems) > 0 {
+		b.WriteString("\nProblems:\n")
+		for _, p := range d.problems {
+			fmt.Fprintf(&b, "* %s\n", p)
+		}
+	}
+
+	return b.String()
+}
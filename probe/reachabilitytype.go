@@ -0,0 +1,20 @@
+package probe
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/yext/revere/db"
+)
+
+type reachabilityType struct{}
+
+func init() {
+	registerProbeType(reachabilityType{})
+}
+
+func (reachabilityType) ID() db.ProbeType {
+	return ReachabilityType{}.Id()
+}
+
+func (reachabilityType) New(tx *db.Tx, m MonitorInfo, config types.JSONText, readingsSink chan<- []Reading) (Probe, error) {
+	return newReachability(config, readingsSink)
+}
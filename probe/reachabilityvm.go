@@ -0,0 +1,223 @@
+package probe
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/util"
+)
+
+type ReachabilityType struct{}
+
+type ReachabilityProbe struct {
+	ReachabilityType
+
+	// TCPTargets holds one host:port address per line.
+	TCPTargets string
+	// DNSTargets holds one name per line, optionally followed by the
+	// records it is expected to resolve to.
+	DNSTargets        string
+	CheckPeriod       int64
+	CheckPeriodType   string
+	Timeout           int64
+	TimeoutType       string
+	LatencyThresholds ThresholdsModel
+	FailureThresholds ThresholdsModel
+}
+
+func init() {
+	addType(ReachabilityType{})
+}
+
+func (ReachabilityType) Id() db.ProbeType {
+	return 4
+}
+
+func (ReachabilityType) Name() string {
+	return "TCP/DNS Reachability"
+}
+
+func (ReachabilityType) loadFromParams(probe string) (VM, error) {
+	var p ReachabilityProbe
+	err := json.Unmarshal([]byte(probe), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (ReachabilityType) loadFromDb(encodedProbe string, tx *db.Tx) (VM, error) {
+	var p ReachabilityDBModel
+	err := json.Unmarshal([]byte(encodedProbe), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	checkPeriod, checkPeriodType := util.GetPeriodAndType(p.CheckPeriodMilli)
+	timeout, timeoutType := util.GetPeriodAndType(p.TimeoutMilli)
+
+	dnsLines := make([]string, len(p.DNSTargets))
+	for i, target := range p.DNSTargets {
+		dnsLines[i] = strings.Join(append([]string{target.Name}, target.Expected...), " ")
+	}
+
+	return &ReachabilityProbe{
+		TCPTargets:      strings.Join(p.TCPTargets, "\n"),
+		DNSTargets:      strings.Join(dnsLines, "\n"),
+		CheckPeriod:     checkPeriod,
+		CheckPeriodType: checkPeriodType,
+		Timeout:         timeout,
+		TimeoutType:     timeoutType,
+		LatencyThresholds: ThresholdsModel{
+			p.LatencyThresholdsMilli.Warning,
+			p.LatencyThresholdsMilli.Error,
+			p.LatencyThresholdsMilli.Critical,
+		},
+		FailureThresholds: ThresholdsModel{
+			p.FailureThresholds.Warning,
+			p.FailureThresholds.Error,
+			p.FailureThresholds.Critical,
+		},
+	}, nil
+}
+
+func (ReachabilityType) blank() (VM, error) {
+	return &ReachabilityProbe{}, nil
+}
+
+func (ReachabilityType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "reachability-edit.html",
+		"view": "reachability-view.html",
+	}
+}
+
+func (ReachabilityType) Scripts() map[string][]string {
+	return map[string][]string{
+		"edit": []string{
+			"reachability.js",
+		},
+	}
+}
+
+func (ReachabilityType) AcceptedResourceTypes() []db.ResourceType {
+	return []db.ResourceType{}
+}
+
+func (p ReachabilityProbe) HasResource(id db.ResourceID) bool {
+	return false
+}
+
+func (p ReachabilityProbe) SerializeForFrontend() map[string]string {
+	return map[string]string{
+		"TCPTargets": p.TCPTargets,
+		"DNSTargets": p.DNSTargets,
+	}
+}
+
+func (p ReachabilityProbe) SerializeForDB() (string, error) {
+	rDB := ReachabilityDBModel{
+		TCPTargets:       nonEmptyLines(p.TCPTargets),
+		DNSTargets:       p.dnsTargets(),
+		CheckPeriodMilli: util.GetMs(p.CheckPeriod, p.CheckPeriodType),
+		TimeoutMilli:     util.GetMs(p.Timeout, p.TimeoutType),
+		LatencyThresholdsMilli: ReachabilityThresholdsDBModel{
+			Warning:  p.LatencyThresholds.Warning,
+			Error:    p.LatencyThresholds.Error,
+			Critical: p.LatencyThresholds.Critical,
+		},
+		FailureThresholds: ReachabilityThresholdsDBModel{
+			Warning:  p.FailureThresholds.Warning,
+			Error:    p.FailureThresholds.Error,
+			Critical: p.FailureThresholds.Critical,
+		},
+	}
+
+	rDBJSON, err := json.Marshal(rDB)
+	return string(rDBJSON), err
+}
+
+// dnsTargets parses the DNSTargets field. Each line is a name followed by
+// expected records separated by spaces or commas.
+func (p ReachabilityProbe) dnsTargets() []ReachabilityDNSTargetDBModel {
+	var targets []ReachabilityDNSTargetDBModel
+	for _, line := range nonEmptyLines(p.DNSTargets) {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		targets = append(targets, ReachabilityDNSTargetDBModel{
+			Name:     fields[0],
+			Expected: fields[1:],
+		})
+	}
+	return targets
+}
+
+func (p ReachabilityProbe) Type() VMType {
+	return ReachabilityType{}
+}
+
+func (p ReachabilityProbe) Validate() (errs []string) {
+	tcpTargets := nonEmptyLines(p.TCPTargets)
+	dnsTargets := p.dnsTargets()
+	if len(tcpTargets) == 0 && len(dnsTargets) == 0 {
+		errs = append(errs, "At least one TCP or DNS target is required")
+	}
+
+	for _, addr := range tcpTargets {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || host == "" {
+			errs = append(errs, "Invalid TCP target: "+addr)
+			continue
+		}
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			errs = append(errs, "Invalid TCP target port: "+addr)
+		}
+	}
+
+	if !isValidPeriodType(p.CheckPeriodType) {
+		errs = append(errs, "Invalid check period type")
+	}
+
+	if util.GetMs(p.CheckPeriod, p.CheckPeriodType) <= 0 {
+		errs = append(errs, "Invalid check period")
+	}
+
+	if p.Timeout != 0 {
+		if !isValidPeriodType(p.TimeoutType) {
+			errs = append(errs, "Invalid timeout type")
+		}
+
+		if util.GetMs(p.Timeout, p.TimeoutType) < 0 {
+			errs = append(errs, "Invalid timeout")
+		}
+	}
+
+	for _, t := range []*float64{
+		p.FailureThresholds.Warning,
+		p.FailureThresholds.Error,
+		p.FailureThresholds.Critical,
+	} {
+		if t != nil && *t < 1 {
+			errs = append(errs, "Failure thresholds must be at least 1")
+			break
+		}
+	}
+
+	return
+}
+
+// nonEmptyLines splits s into trimmed lines, dropping empty ones.
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,201 @@
+package probe
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/yext/revere/state"
+)
+
+// threshold associates a state with the summary value at which a threshold
+// probe enters that state.
+type threshold struct {
+	state     state.State
+	threshold float64
+}
+
+// thresholdOverride replaces a probe's thresholds for the subprobes matching a
+// regular expression.
+type thresholdOverride struct {
+	subprobes  *regexp.Regexp
+	thresholds []threshold
+}
+
+// thresholdsFor returns the thresholds of the first override matching
+// subprobe, or defaults if none match.
+func thresholdsFor(subprobe string, defaults []threshold, overrides []thresholdOverride) []threshold {
+	for _, o := range overrides {
+		if o.subprobes.MatchString(subprobe) {
+			return o.thresholds
+		}
+	}
+	return defaults
+}
+
+// newThresholds builds the thresholds for the given optional per-state values.
+// The result is in increasing severity order, as evaluateThresholds requires.
+func newThresholds(warning, error, critical *float64) []threshold {
+	var thresholds []threshold
+	if warning != nil {
+		thresholds = append(thresholds, threshold{state.Warning, *warning})
+	}
+	if error != nil {
+		thresholds = append(thresholds, threshold{state.Error, *error})
+	}
+	if critical != nil {
+		thresholds = append(thresholds, threshold{state.Critical, *critical})
+	}
+	return thresholds
+}
+
+// evaluateThresholds returns the most severe state whose threshold value
+// triggers for summaryValue, along with that threshold value. If no threshold
+// triggers, it returns Normal and NaN.
+func evaluateThresholds(thresholds []threshold, triggersOn func(summaryValue, threshold float64) bool, summaryValue float64) (state.State, float64) {
+	s := state.Normal
+	triggered := math.NaN()
+	for _, t := range thresholds {
+		if triggersOn(summaryValue, t.threshold) {
+			s = t.state
+			triggered = t.threshold
+		}
+	}
+	return s, triggered
+}
+
+// auditFunction summarizes the values of a series whose datapoints are step
+// apart. Missing datapoints are NaN. The result is NaN if the values cannot be
+// summarized, e.g. because they are all missing.
+type auditFunction func(values []float64, step time.Duration) float64
+
+var (
+	auditFunctions = map[string]auditFunction{
+		"avg": func(values []float64, step time.Duration) float64 {
+			sum := float64(0)
+			count := 0
+			for _, value := range values {
+				if !math.IsNaN(value) {
+					sum += value
+					count += 1
+				}
+			}
+			return sum / float64(count)
+		},
+		"max": func(values []float64, step time.Duration) float64 {
+			max := math.Inf(-1)
+			for _, value := range values {
+				if value > max {
+					max = value
+				}
+			}
+			if math.IsInf(max, -1) {
+				return math.NaN()
+			}
+			return max
+		},
+		"min": func(values []float64, step time.Duration) float64 {
+			min := math.Inf(+1)
+			for _, value := range values {
+				if value < min {
+					min = value
+				}
+			}
+			if math.IsInf(min, +1) {
+				return math.NaN()
+			}
+			return min
+		},
+		"p50": percentile(50),
+		"p90": percentile(90),
+		"p99": percentile(99),
+		"sum": func(values []float64, step time.Duration) float64 {
+			sum := float64(0)
+			count := 0
+			for _, value := range values {
+				if !math.IsNaN(value) {
+					sum += value
+					count += 1
+				}
+			}
+			if count == 0 {
+				return math.NaN()
+			}
+			return sum
+		},
+		"last": func(values []float64, step time.Duration) float64 {
+			for i := len(values) - 1; i >= 0; i-- {
+				if !math.IsNaN(values[i]) {
+					return values[i]
+				}
+			}
+			return math.NaN()
+		},
+		"nullcount": func(values []float64, step time.Duration) float64 {
+			count := 0
+			for _, value := range values {
+				if math.IsNaN(value) {
+					count += 1
+				}
+			}
+			return float64(count)
+		},
+		// rate is the per-second change from the first to the last
+		// non-missing datapoint.
+		"rate": func(values []float64, step time.Duration) float64 {
+			first, last := -1, -1
+			for i, value := range values {
+				if !math.IsNaN(value) {
+					if first < 0 {
+						first = i
+					}
+					last = i
+				}
+			}
+			if first == last || step <= 0 {
+				return math.NaN()
+			}
+			elapsed := time.Duration(last-first) * step
+			return (values[last] - values[first]) / elapsed.Seconds()
+		},
+	}
+
+	triggerIfFunctions = map[string]func(float64, float64) bool{
+		"<": func(summaryValue, threshold float64) bool {
+			return summaryValue < threshold
+		},
+		"<=": func(summaryValue, threshold float64) bool {
+			return summaryValue <= threshold
+		},
+		">=": func(summaryValue, threshold float64) bool {
+			return summaryValue >= threshold
+		},
+		">": func(summaryValue, threshold float64) bool {
+			return summaryValue > threshold
+		},
+	}
+)
+
+// percentile returns an audit function computing the pth percentile of the
+// non-missing values using the nearest-rank method.
+func percentile(p float64) auditFunction {
+	return func(values []float64, step time.Duration) float64 {
+		sorted := make([]float64, 0, len(values))
+		for _, value := range values {
+			if !math.IsNaN(value) {
+				sorted = append(sorted, value)
+			}
+		}
+		if len(sorted) == 0 {
+			return math.NaN()
+		}
+		sort.Float64s(sorted)
+
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+}
@@ -0,0 +1,88 @@
+package resource
+
+import (
+	"encoding/json"
+
+	"github.com/yext/revere/db"
+)
+
+type Prometheus struct{}
+
+type PrometheusResource struct {
+	Prometheus
+	URL string
+}
+
+// Eventually implemented in DB layer
+type PrometheusResourceDBModel struct {
+	URL string
+}
+
+func init() {
+	addType(Prometheus{})
+}
+
+func (Prometheus) Id() db.ResourceType {
+	return 1
+}
+
+func (Prometheus) Name() string {
+	return "Prometheus"
+}
+
+func (Prometheus) loadFromParams(ds string) (Resource, error) {
+	var p PrometheusResource
+	err := json.Unmarshal([]byte(ds), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (Prometheus) loadFromDB(ds string) (Resource, error) {
+	var p PrometheusResourceDBModel
+	err := json.Unmarshal([]byte(ds), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrometheusResource{
+		URL: p.URL,
+	}, nil
+}
+
+func (Prometheus) blank() (Resource, error) {
+	return &PrometheusResource{}, nil
+}
+
+func (Prometheus) Templates() string {
+	return "prometheus-resource.html"
+}
+
+func (Prometheus) Scripts() []string {
+	return []string{
+		"prometheus-resource.js",
+	}
+}
+
+func (p PrometheusResource) Serialize() (string, error) {
+	pDB := PrometheusResourceDBModel{
+		p.URL,
+	}
+
+	pDBJSON, err := json.Marshal(pDB)
+	return string(pDBJSON), err
+}
+
+func (p PrometheusResource) Type() ResourceType {
+	return Prometheus{}
+}
+
+func (p PrometheusResource) Validate() []string {
+	var errs []string
+	if p.URL == "" {
+		errs = append(errs, "Url is required")
+	}
+
+	return errs
+}
@@ -0,0 +1,180 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// PrometheusServer represents a remote Prometheus server. For more
+// information, see https://prometheus.io/docs/prometheus/latest/querying/api/ .
+type PrometheusServer struct {
+	// Base is the URL where the server can be found. Base should have a
+	// trailing slash, with the expectation that the range query API
+	// endpoint is at Base + "api/v1/query_range".
+	Base string
+}
+
+// PrometheusSeries encapsulates the data returned by Prometheus for a
+// particular series. Labels identifies the series. The values in Values
+// correspond to the times in Times. Values Prometheus reports as NaN are
+// represented by NaN, mirroring GraphiteSeries, and infinite values by Inf.
+type PrometheusSeries struct {
+	Labels map[string]string
+	Times  []time.Time
+	Values []float64
+}
+
+// Name returns the canonical Prometheus text representation of s's label set,
+// e.g. up{instance="a:9090",job="prometheus"}.
+func (s PrometheusSeries) Name() string {
+	keys := make([]string, 0, len(s.Labels))
+	for k := range s.Labels {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, s.Labels[k])
+	}
+
+	return s.Labels["__name__"] + "{" + strings.Join(pairs, ",") + "}"
+}
+
+type prometheusResponse struct {
+	Status    string
+	ErrorType string
+	Error     string
+	Data      struct {
+		ResultType string
+		Result     []struct {
+			Metric map[string]string
+			Values [][2]interface{}
+		}
+	}
+}
+
+// QueryRange evaluates query over the given time period, with step resolution.
+func (p PrometheusServer) QueryRange(query string, start, end time.Time, step time.Duration) ([]PrometheusSeries, error) {
+	if step <= 0 {
+		return nil, errors.Errorf("cannot query with nonpositive step %s", step)
+	}
+
+	u := p.Base + "api/v1/query_range?" + url.Values{
+		"query": []string{query},
+		"start": []string{PrometheusTimestamp(start)},
+		"end":   []string{PrometheusTimestamp(end)},
+		"step":  []string{strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}.Encode()
+
+	data, err := p.get(u)
+	if err != nil {
+		return nil, errors.Maskf(err, "query Prometheus")
+	}
+
+	series, err := parsePrometheusMatrix(data)
+	if err != nil {
+		return nil, errors.Maskf(err, "parse Prometheus range query response")
+	}
+
+	return series, nil
+}
+
+func (p PrometheusServer) get(url string) ([]byte, error) {
+	r, err := http.Get(url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.Annotatef(err, "Get %s", url)
+	}
+
+	// Prometheus reports query errors with a non-OK status code and a JSON
+	// body describing the error, so only bail out here if the body is not
+	// JSON.
+	if r.StatusCode != http.StatusOK && !json.Valid(b) {
+		return nil, errors.Errorf("Get %s: not-OK HTTP status code: %d", url, r.StatusCode)
+	}
+
+	return b, nil
+}
+
+func parsePrometheusMatrix(data []byte) ([]PrometheusSeries, error) {
+	var resp prometheusResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if resp.Status != "success" {
+		return nil, errors.Errorf("%s: %s", resp.ErrorType, resp.Error)
+	}
+
+	if resp.Data.ResultType != "matrix" {
+		return nil, errors.Errorf("unexpected result type: %s", resp.Data.ResultType)
+	}
+
+	series := make([]PrometheusSeries, 0, len(resp.Data.Result))
+	for _, result := range resp.Data.Result {
+		s := PrometheusSeries{
+			Labels: result.Metric,
+			Times:  make([]time.Time, len(result.Values)),
+			Values: make([]float64, len(result.Values)),
+		}
+
+		for i, pair := range result.Values {
+			ts, ok := pair[0].(float64)
+			if !ok {
+				return nil, errors.Errorf("could not parse sample time: %v", pair[0])
+			}
+			sec, frac := math.Modf(ts)
+			s.Times[i] = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+
+			valueString, ok := pair[1].(string)
+			if !ok {
+				return nil, errors.Errorf("could not parse sample value: %v", pair[1])
+			}
+			value, err := strconv.ParseFloat(valueString, 64)
+			if err != nil {
+				return nil, errors.Errorf("could not parse sample value: %s", valueString)
+			}
+			s.Values[i] = value
+		}
+
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// GraphURL builds a link to the Prometheus expression browser graphing query
+// over the range ending at end.
+func (p PrometheusServer) GraphURL(query string, end time.Time, rangeDuration time.Duration) string {
+	values := url.Values{
+		"g0.expr":        []string{query},
+		"g0.tab":         []string{"0"},
+		"g0.end_input":   []string{end.UTC().Format("2006-01-02 15:04:05")},
+		"g0.range_input": []string{fmt.Sprintf("%ds", int64(rangeDuration/time.Second))},
+	}
+	return p.Base + "graph?" + values.Encode()
+}
+
+// PrometheusTimestamp converts t to a value suitable for use as the start or
+// end argument to the Prometheus query API.
+func PrometheusTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', 3, 64)
+}
@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePrometheusMatrix(t *testing.T) {
+	data := []byte(`{
+		"status": "success",
+		"data": {
+			"resultType": "matrix",
+			"result": [
+				{
+					"metric": {"__name__": "up", "job": "api"},
+					"values": [[1500000000, "1"], [1500000015.5, "NaN"], [1500000030, "+Inf"], [1500000045, "-Inf"]]
+				},
+				{"metric": {}, "values": []}
+			]
+		}
+	}`)
+
+	series, err := parsePrometheusMatrix(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if len(series) != 2 {
+		t.Fatalf("Expected 2 series, got %d\n", len(series))
+	}
+
+	s := series[0]
+	if s.Labels["job"] != "api" || len(s.Times) != 4 || len(s.Values) != 4 {
+		t.Fatalf("Expected the up series with 4 values, got %+v\n", s)
+	}
+	if !s.Times[1].Equal(time.Unix(1500000015, int64(500*time.Millisecond))) {
+		t.Errorf("Expected fractional sample time, got %s\n", s.Times[1])
+	}
+	if s.Values[0] != 1 {
+		t.Errorf("Expected value 1, got %v\n", s.Values[0])
+	}
+	if !math.IsNaN(s.Values[1]) {
+		t.Errorf("Expected NaN, got %v\n", s.Values[1])
+	}
+	if !math.IsInf(s.Values[2], 1) {
+		t.Errorf("Expected +Inf, got %v\n", s.Values[2])
+	}
+	if !math.IsInf(s.Values[3], -1) {
+		t.Errorf("Expected -Inf, got %v\n", s.Values[3])
+	}
+
+	if len(series[1].Times) != 0 || len(series[1].Values) != 0 {
+		t.Errorf("Expected a series without values, got %+v\n", series[1])
+	}
+}
+
+func TestParsePrometheusMatrixEmpty(t *testing.T) {
+	series, err := parsePrometheusMatrix([]byte(`{"status": "success", "data": {"resultType": "matrix", "result": []}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if series == nil || len(series) != 0 {
+		t.Errorf("Expected no series, got %+v\n", series)
+	}
+}
+
+func TestParsePrometheusMatrixErrors(t *testing.T) {
+	cases := map[string]string{
+		"malformed": `{"status": `,
+		"error":     `{"status": "error", "errorType": "bad_data", "error": "parse error"}`,
+		"vector": `{"status": "success", "data": {"resultType": "vector", "result": [
+			{"metric": {}, "value": [1500000000, "1"]}]}}`,
+		"scalar":       `{"status": "success", "data": {"resultType": "scalar", "result": [1500000000, "1"]}}`,
+		"time":         `{"status": "success", "data": {"resultType": "matrix", "result": [{"metric": {}, "values": [["now", "1"]]}]}}`,
+		"number value": `{"status": "success", "data": {"resultType": "matrix", "result": [{"metric": {}, "values": [[1500000000, 1]]}]}}`,
+		"bad value":    `{"status": "success", "data": {"resultType": "matrix", "result": [{"metric": {}, "values": [[1500000000, "one"]]}]}}`,
+	}
+
+	for name, data := range cases {
+		if _, err := parsePrometheusMatrix([]byte(data)); err == nil {
+			t.Errorf("Expected error for %s response\n", name)
+		}
+	}
+}
+
+func TestPrometheusSeriesName(t *testing.T) {
+	cases := []struct {
+		labels   map[string]string
+		expected string
+	}{
+		{map[string]string{}, "{}"},
+		{map[string]string{"__name__": "up"}, "up{}"},
+		{
+			map[string]string{"job": "api", "__name__": "up", "instance": "a:9090"},
+			`up{instance="a:9090",job="api"}`,
+		},
+		{map[string]string{"zone": "us", "az": "b"}, `{az="b",zone="us"}`},
+		{map[string]string{"path": `/a "b"`}, `{path="/a \"b\""}`},
+	}
+
+	for _, c := range cases {
+		if name := (PrometheusSeries{Labels: c.labels}).Name(); name != c.expected {
+			t.Errorf("Expected name %s, got %s\n", c.expected, name)
+		}
+	}
+}
@@ -118,7 +118,7 @@ func addType(resourceType ResourceType) {
 	if _, ok := types[resourceType.Id()]; !ok {
 		types[resourceType.Id()] = resourceType
 	} else {
-		panic(fmt.Sprintf("A resource type with id %d already exists", resourceType.Id))
+		panic(fmt.Sprintf("A resource type with id %d already exists", resourceType.Id()))
 	}
 }
 
@@ -205,9 +205,7 @@ func (vm *VM) Save(tx *db.Tx) error {
 	}
 
 	if vm.IsCreate() {
-		var id db.ResourceID
-		id, err = tx.CreateResource(resource)
-		resource.ResourceID = id
+		vm.ResourceID, err = tx.CreateResource(resource)
 	} else if vm.IsDelete() {
 		err = tx.DeleteResource(vm.ResourceID)
 	} else {
@@ -0,0 +1,167 @@
+// Package schedule works out who is on call for on-call schedules.
+package schedule
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+)
+
+// Person is someone who can be on call.
+type Person struct {
+	Name        string
+	Email       string
+	SlackHandle string
+}
+
+// Layer is a rotation that hands off from one member to the next every Shift,
+// starting with the first member at Start.
+type Layer struct {
+	Start   time.Time
+	Shift   time.Duration
+	Members []Person
+}
+
+// Override puts Person on call between Start and End, regardless of the
+// schedule's layers.
+type Override struct {
+	Person
+	Start time.Time
+	End   time.Time
+}
+
+// Schedule is an on-call schedule. Later layers take precedence over earlier
+// ones, and overrides take precedence over all layers.
+type Schedule struct {
+	ScheduleID db.ScheduleID
+	Name       string
+	Layers     []Layer
+	Overrides  []Override
+}
+
+// Load loads the schedule with the given ID, or returns nil if there is none.
+func Load(DB *db.DB, id db.ScheduleID) (*Schedule, error) {
+	s, err := DB.LoadSchedule(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	layers, err := DB.LoadScheduleLayers(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	overrides, err := DB.LoadScheduleOverrides(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return New(s, layers, overrides)
+}
+
+// New makes a Schedule from its database representation.
+func New(s *db.Schedule, layers []db.ScheduleLayer, overrides []db.ScheduleOverride) (*Schedule, error) {
+	schedule := &Schedule{
+		ScheduleID: s.ScheduleID,
+		Name:       s.Name,
+		Layers:     make([]Layer, len(layers)),
+		Overrides:  make([]Override, len(overrides)),
+	}
+
+	for i, l := range layers {
+		members, err := LoadMembers(l.Members)
+		if err != nil {
+			return nil, errors.Maskf(err, "load members of layer %d", l.ScheduleLayerID)
+		}
+		schedule.Layers[i] = Layer{
+			Start:   l.Start,
+			Shift:   time.Duration(l.ShiftMilli) * time.Millisecond,
+			Members: members,
+		}
+	}
+
+	for i, o := range overrides {
+		schedule.Overrides[i] = Override{
+			Person: Person{
+				Name:        o.Name,
+				Email:       o.Email,
+				SlackHandle: o.SlackHandle,
+			},
+			Start: o.Start,
+			End:   o.End,
+		}
+	}
+
+	return schedule, nil
+}
+
+// LoadMembers deserializes the members of a layer.
+func LoadMembers(members types.JSONText) ([]Person, error) {
+	var people []Person
+	err := members.Unmarshal(&people)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize layer members")
+	}
+	return people, nil
+}
+
+// SerializeMembers serializes the members of a layer for the database.
+func SerializeMembers(people []Person) (types.JSONText, error) {
+	members, err := json.Marshal(people)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize layer members")
+	}
+	return types.JSONText(members), nil
+}
+
+// OnCall returns who is on call at t, or nil if nobody is.
+func (s *Schedule) OnCall(t time.Time) *Person {
+	// Of overlapping overrides, the one that started last wins.
+	var override *Override
+	for i, o := range s.Overrides {
+		if o.Start.After(t) || !o.End.After(t) {
+			continue
+		}
+		if override == nil || o.Start.After(override.Start) {
+			override = &s.Overrides[i]
+		}
+	}
+	if override != nil {
+		return &override.Person
+	}
+
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		if p := s.Layers[i].OnCall(t); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// OnCall returns which member of the layer is on call at t, or nil if the
+// layer has not started yet or has no members.
+func (l *Layer) OnCall(t time.Time) *Person {
+	if len(l.Members) == 0 || l.Shift <= 0 || t.Before(l.Start) {
+		return nil
+	}
+
+	shifts := int64(t.Sub(l.Start) / l.Shift)
+	return &l.Members[shifts%int64(len(l.Members))]
+}
+
+// NextHandoff returns when the member on call at t hands off to the next one.
+func (l *Layer) NextHandoff(t time.Time) time.Time {
+	if l.Shift <= 0 || t.Before(l.Start) {
+		return l.Start
+	}
+
+	shifts := t.Sub(l.Start) / l.Shift
+	return l.Start.Add((shifts + 1) * l.Shift)
+}
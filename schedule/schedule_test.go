@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	alice = Person{Name: "Alice", Email: "alice@example.com"}
+	bob   = Person{Name: "Bob", Email: "bob@example.com"}
+	carol = Person{Name: "Carol", Email: "carol@example.com", SlackHandle: "carol"}
+
+	start = time.Date(2016, time.June, 6, 9, 0, 0, 0, time.UTC)
+)
+
+func weekly() Layer {
+	return Layer{
+		Start:   start,
+		Shift:   7 * 24 * time.Hour,
+		Members: []Person{alice, bob},
+	}
+}
+
+func TestLayerRotates(t *testing.T) {
+	l := weekly()
+
+	cases := []struct {
+		at       time.Time
+		expected *Person
+	}{
+		{start.Add(-time.Minute), nil},
+		{start, &alice},
+		{start.Add(7*24*time.Hour - time.Minute), &alice},
+		{start.Add(7 * 24 * time.Hour), &bob},
+		{start.Add(14 * 24 * time.Hour), &alice},
+	}
+	for _, c := range cases {
+		actual := l.OnCall(c.at)
+		if (actual == nil) != (c.expected == nil) || (actual != nil && *actual != *c.expected) {
+			t.Errorf("Expected %v on call at %s, got %v\n", c.expected, c.at, actual)
+		}
+	}
+}
+
+func TestLayerNextHandoff(t *testing.T) {
+	l := weekly()
+
+	if h := l.NextHandoff(start.Add(-time.Hour)); !h.Equal(start) {
+		t.Errorf("Expected first handoff at %s, got %s\n", start, h)
+	}
+	if h := l.NextHandoff(start.Add(time.Hour)); !h.Equal(start.Add(7 * 24 * time.Hour)) {
+		t.Errorf("Expected handoff after a week, got %s\n", h)
+	}
+}
+
+func TestLaterLayersTakePrecedence(t *testing.T) {
+	weekend := Layer{
+		Start:   start.Add(4 * 24 * time.Hour),
+		Shift:   24 * time.Hour,
+		Members: []Person{carol},
+	}
+	s := &Schedule{Layers: []Layer{weekly(), weekend}}
+
+	if p := s.OnCall(start.Add(time.Hour)); p == nil || *p != alice {
+		t.Errorf("Expected %v on call before the second layer starts, got %v\n", alice, p)
+	}
+	if p := s.OnCall(weekend.Start.Add(time.Hour)); p == nil || *p != carol {
+		t.Errorf("Expected %v on call from the second layer, got %v\n", carol, p)
+	}
+}
+
+func TestOverridesTakePrecedence(t *testing.T) {
+	s := &Schedule{
+		Layers: []Layer{weekly()},
+		Overrides: []Override{
+			{Person: carol, Start: start.Add(time.Hour), End: start.Add(3 * time.Hour)},
+			{Person: bob, Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)},
+		},
+	}
+
+	cases := []struct {
+		at       time.Time
+		expected Person
+	}{
+		{start, alice},
+		{start.Add(time.Hour), carol},
+		{start.Add(2 * time.Hour), bob},
+		{start.Add(3 * time.Hour), alice},
+	}
+	for _, c := range cases {
+		actual := s.OnCall(c.at)
+		if actual == nil || *actual != c.expected {
+			t.Errorf("Expected %v on call at %s, got %v\n", c.expected, c.at, actual)
+		}
+	}
+}
+
+func TestNobodyOnCall(t *testing.T) {
+	s := &Schedule{Layers: []Layer{{Start: start, Shift: time.Hour}}}
+	if p := s.OnCall(start); p != nil {
+		t.Errorf("Expected nobody on call for a layer without members, got %v\n", p)
+	}
+}
+
+func TestMembersRoundTrip(t *testing.T) {
+	members, err := SerializeMembers([]Person{alice, carol})
+	if err != nil {
+		t.Fatalf("Unexpected error serializing members: %v\n", err)
+	}
+
+	people, err := LoadMembers(members)
+	if err != nil {
+		t.Fatalf("Unexpected error loading members: %v\n", err)
+	}
+	if len(people) != 2 || people[0] != alice || people[1] != carol {
+		t.Errorf("Expected members %v, got %v\n", []Person{alice, carol}, people)
+	}
+}
@@ -0,0 +1,93 @@
+package setting
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/yext/revere/db"
+)
+
+type PagerDuty struct{}
+
+type PagerDutySetting struct {
+	PagerDuty
+	IntegrationKey string
+}
+
+type PagerDutySettingDBModel struct {
+	IntegrationKey string
+}
+
+// PagerDutyIntegrationKey matches the integration keys of PagerDuty Events API
+// v2 integrations.
+var PagerDutyIntegrationKey = regexp.MustCompile(`^[0-9a-zA-Z]{32}$`)
+
+func init() {
+	addType(PagerDuty{})
+}
+
+func (PagerDuty) Id() db.SettingType {
+	return 2
+}
+
+func (PagerDuty) Name() string {
+	return "PagerDuty Configuration"
+}
+
+func (PagerDuty) loadFromParams(s string) (Setting, error) {
+	var ps PagerDutySetting
+	err := json.Unmarshal([]byte(s), &ps)
+	if err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
+func (PagerDuty) loadFromDB(s string) (Setting, error) {
+	var ps PagerDutySettingDBModel
+	err := json.Unmarshal([]byte(s), &ps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PagerDutySetting{
+		IntegrationKey: ps.IntegrationKey,
+	}, nil
+}
+
+func (PagerDuty) blank() (Setting, error) {
+	return &PagerDutySetting{}, nil
+}
+
+func (PagerDuty) Template() string {
+	return "_pagerduty.html"
+}
+
+func (PagerDuty) Scripts() []string {
+	return []string{
+		"pagerduty.js",
+	}
+}
+
+func (ps *PagerDutySetting) Serialize() (string, error) {
+	psDB := PagerDutySettingDBModel{
+		IntegrationKey: ps.IntegrationKey,
+	}
+
+	psDBJSON, err := json.Marshal(psDB)
+	return string(psDBJSON), err
+}
+
+func (*PagerDutySetting) Type() SettingType {
+	return PagerDuty{}
+}
+
+func (ps *PagerDutySetting) Validate() []string {
+	var errs []string
+
+	// The key may be left blank if every PagerDuty target has its own.
+	if ps.IntegrationKey != "" && !PagerDutyIntegrationKey.MatchString(ps.IntegrationKey) {
+		errs = append(errs, "Invalid PagerDuty integration key. Should be 32 letters and digits.")
+	}
+	return errs
+}
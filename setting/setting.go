@@ -190,9 +190,7 @@ func (vm *VM) Save(tx *db.Tx) error {
 	}
 
 	if vm.IsCreate() {
-		var id db.SettingID
-		id, err = tx.CreateSetting(setting)
-		setting.SettingID = id
+		vm.SettingID, err = tx.CreateSetting(setting)
 	} else {
 		err = tx.UpdateSetting(setting)
 	}
@@ -1,9 +1,13 @@
 package target
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/durationfmt"
 	"github.com/yext/revere/probe"
 	"github.com/yext/revere/state"
 )
@@ -27,5 +31,68 @@ type Alert struct {
 
 	Details probe.Details
 
+	// Flapping is set on the single alert sent when a subprobe starts
+	// changing state too often. Alerts are then suppressed until it
+	// stabilizes, at which point an alert with StoppedFlapping set is sent
+	// with OldState set to WorstState.
+	Flapping        bool
+	StoppedFlapping bool
+	WorstState      state.State
+	FlapTransitions int
+	FlapWindow      time.Duration
+
 	Host string
 }
+
+// AckURL links to the page for acknowledging the alert's subprobe, which stops
+// repeat alerts while it stays no worse than it is now.
+func (a Alert) AckURL() string {
+	return fmt.Sprintf("%s/monitors/%d/subprobes/%d/ack", a.Host, a.MonitorID, a.SubprobeID)
+}
+
+// SilenceURL links to the silence form for the alert's subprobe. New silences
+// default to starting now and lasting an hour.
+func (a Alert) SilenceURL() string {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(int64(a.MonitorID), 10))
+	v.Set("subprobe", a.SubprobeName)
+	return fmt.Sprintf("%s/redirectToSilence?%s", a.Host, v.Encode())
+}
+
+// GraphImageURL returns the URL of an image graphing the reading that caused
+// the alert, or "" if its probe does not have one.
+func (a Alert) GraphImageURL() string {
+	if d, ok := a.Details.(probe.GraphDetails); ok {
+		return d.GraphImageURL()
+	}
+	return ""
+}
+
+// FlapText describes the alert's change in flapping status, or is empty if the
+// alert is not about flapping.
+func (a Alert) FlapText() string {
+	switch {
+	case a.Flapping:
+		return fmt.Sprintf("Flapping: %d state changes in the last %s, as bad as %s. "+
+			"Alerts are suppressed until it stabilizes.",
+			a.FlapTransitions, durationfmt.ExactMulti().Format(a.FlapWindow), a.WorstState)
+	case a.StoppedFlapping:
+		return fmt.Sprintf("Stopped flapping. Worst state while flapping: %s", a.WorstState)
+	default:
+		return ""
+	}
+}
+
+// GroupSummary describes a group of alerts sent together, e.g. as the subject
+// of a digest email.
+func GroupSummary(alerts []*Alert) string {
+	monitors := make(map[db.MonitorID]string)
+	for _, a := range alerts {
+		monitors[a.MonitorID] = a.MonitorName
+	}
+
+	if len(monitors) == 1 {
+		return fmt.Sprintf("%d alerts for %s", len(alerts), alerts[0].MonitorName)
+	}
+	return fmt.Sprintf("%d alerts across %d monitors", len(alerts), len(monitors))
+}
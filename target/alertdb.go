@@ -0,0 +1,120 @@
+package target
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+// AlertDBModel defines the JSON serialization format for saving alerts in the
+// database. Probe details are saved as their text and graph image URL.
+type AlertDBModel struct {
+	MonitorID    db.MonitorID
+	MonitorName  string
+	SubprobeID   db.SubprobeID
+	SubprobeName string
+
+	Description string
+	Response    string
+
+	OldState state.State
+	NewState state.State
+
+	Recorded     time.Time
+	EnteredState time.Time
+	LastNormal   time.Time
+
+	DetailsText   string
+	GraphImageURL string
+
+	Flapping        bool
+	StoppedFlapping bool
+	WorstState      state.State
+	FlapTransitions int
+	FlapWindowMilli int64
+
+	Host string
+}
+
+// savedDetails stands in for the probe details of alerts loaded from the
+// database.
+type savedDetails struct {
+	text          string
+	graphImageURL string
+}
+
+func (d savedDetails) Text() string {
+	return d.text
+}
+
+func (d savedDetails) GraphImageURL() string {
+	return d.graphImageURL
+}
+
+// Serialize encodes a for saving in the database.
+func (a *Alert) Serialize() (types.JSONText, error) {
+	aDB := AlertDBModel{
+		MonitorID:       a.MonitorID,
+		MonitorName:     a.MonitorName,
+		SubprobeID:      a.SubprobeID,
+		SubprobeName:    a.SubprobeName,
+		Description:     a.Description,
+		Response:        a.Response,
+		OldState:        a.OldState,
+		NewState:        a.NewState,
+		Recorded:        a.Recorded,
+		EnteredState:    a.EnteredState,
+		LastNormal:      a.LastNormal,
+		Flapping:        a.Flapping,
+		StoppedFlapping: a.StoppedFlapping,
+		WorstState:      a.WorstState,
+		FlapTransitions: a.FlapTransitions,
+		FlapWindowMilli: int64(a.FlapWindow / time.Millisecond),
+		Host:            a.Host,
+	}
+	if a.Details != nil {
+		aDB.DetailsText = a.Details.Text()
+	}
+	aDB.GraphImageURL = a.GraphImageURL()
+
+	aDBJSON, err := json.Marshal(aDB)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize alert")
+	}
+	return types.JSONText(aDBJSON), nil
+}
+
+// LoadAlert decodes an alert saved in the database.
+func LoadAlert(encodedAlert types.JSONText) (*Alert, error) {
+	var aDB AlertDBModel
+	err := encodedAlert.Unmarshal(&aDB)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize alert")
+	}
+
+	return &Alert{
+		MonitorID:       aDB.MonitorID,
+		MonitorName:     aDB.MonitorName,
+		SubprobeID:      aDB.SubprobeID,
+		SubprobeName:    aDB.SubprobeName,
+		Description:     aDB.Description,
+		Response:        aDB.Response,
+		OldState:        aDB.OldState,
+		NewState:        aDB.NewState,
+		Recorded:        aDB.Recorded,
+		EnteredState:    aDB.EnteredState,
+		LastNormal:      aDB.LastNormal,
+		Details:         savedDetails{aDB.DetailsText, aDB.GraphImageURL},
+		Flapping:        aDB.Flapping,
+		StoppedFlapping: aDB.StoppedFlapping,
+		WorstState:      aDB.WorstState,
+		FlapTransitions: aDB.FlapTransitions,
+		FlapWindow:      time.Duration(aDB.FlapWindowMilli) * time.Millisecond,
+		Host:            aDB.Host,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package target_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yext/revere/state"
+	. "github.com/yext/revere/target"
+)
+
+type testDetails struct{}
+
+func (testDetails) Text() string {
+	return "value was 12"
+}
+
+func TestAlertRoundTrip(t *testing.T) {
+	recorded := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := &Alert{
+		MonitorID:    3,
+		MonitorName:  "monitor",
+		SubprobeID:   5,
+		SubprobeName: "subprobe",
+		OldState:     state.Normal,
+		NewState:     state.Critical,
+		Recorded:     recorded,
+		Details:      testDetails{},
+		Flapping:     true,
+		WorstState:   state.Critical,
+		FlapWindow:   15 * time.Minute,
+		Host:         "https://revere.example.com",
+	}
+
+	encoded, err := a.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize alert: %s\n", err.Error())
+	}
+	loaded, err := LoadAlert(encoded)
+	if err != nil {
+		t.Fatalf("Failed to load alert: %s\n", err.Error())
+	}
+
+	if loaded.MonitorID != a.MonitorID || loaded.SubprobeName != a.SubprobeName ||
+		loaded.NewState != a.NewState || !loaded.Recorded.Equal(recorded) ||
+		loaded.FlapWindow != a.FlapWindow || loaded.Host != a.Host {
+		t.Errorf("Expected loaded alert %+v, got %+v\n", a, loaded)
+	}
+	if loaded.Details.Text() != "value was 12" {
+		t.Errorf("Expected details text: value was 12, got %s\n", loaded.Details.Text())
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+		MaxAttempts:    10,
+	}
+
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, e := range expected {
+		if b := p.Backoff(int32(i + 1)); b != e {
+			t.Errorf("Expected backoff after %d attempts: %s, got %s\n", i+1, e, b)
+		}
+	}
+}
+
+func TestGroupSummary(t *testing.T) {
+	a := &Alert{MonitorID: 1, MonitorName: "disk"}
+	b := &Alert{MonitorID: 1, MonitorName: "disk"}
+	c := &Alert{MonitorID: 2, MonitorName: "cpu"}
+
+	if s := GroupSummary([]*Alert{a, b}); s != "2 alerts for disk" {
+		t.Errorf("Expected summary: 2 alerts for disk, got %s\n", s)
+	}
+	if s := GroupSummary([]*Alert{a, b, c}); s != "3 alerts across 2 monitors" {
+		t.Errorf("Expected summary: 3 alerts across 2 monitors, got %s\n", s)
+	}
+}
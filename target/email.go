@@ -2,6 +2,7 @@ package target
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/jmoiron/sqlx/types"
 	"github.com/juju/errors"
@@ -39,6 +40,10 @@ func (e *Email) Type() Type {
 	return emailType{}
 }
 
+func (e *Email) Recipients() string {
+	return strings.Join(e.to, ", ")
+}
+
 func (e *Email) To() []string {
 	return e.to[:]
 }
@@ -0,0 +1,219 @@
+package target
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// maxHeaderLineLength is the line length headers are folded at, per
+	// RFC 5322's recommended limit.
+	maxHeaderLineLength = 78
+
+	// maxLineLength is the most characters allowed on any line of an
+	// email, per RFC 5322.
+	maxLineLength = 998
+
+	// base64LineLength is the line length of base64 bodies, per RFC 2045.
+	base64LineLength = 76
+
+	graphContentID = "graph@revere"
+	graphTimeout   = 10 * time.Second
+	maxGraphBytes  = 5 << 20
+)
+
+// emailHeader is a header of an email. Values are encoded and folded as needed
+// when the email is built.
+type emailHeader struct {
+	name  string
+	value string
+}
+
+// emailImage is an image shown inline in the HTML body of an email.
+type emailImage struct {
+	contentID   string
+	contentType string
+	data        []byte
+}
+
+// buildEmail builds a MIME email with plain text and HTML alternatives. If
+// image is set, it is attached to the HTML body for it to refer to by its
+// content ID.
+func buildEmail(headers []emailHeader, text, html []byte, image *emailImage) ([]byte, error) {
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+
+	err := writeQuotedPrintablePart(alternative, "text/plain; charset=utf-8", text)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if image == nil {
+		err = writeQuotedPrintablePart(alternative, "text/html; charset=utf-8", html)
+	} else {
+		err = writeRelatedPart(alternative, html, image)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = alternative.Close()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var b bytes.Buffer
+	for _, h := range headers {
+		b.WriteString(foldHeader(h.name, h.value))
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString(foldHeader("Content-Type",
+		fmt.Sprintf("multipart/alternative; boundary=%q", alternative.Boundary())))
+	b.WriteString("\r\n")
+	b.Write(body.Bytes())
+	return b.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(w *multipart.Writer, contentType string, content []byte) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	_, err = qp.Write(content)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(qp.Close())
+}
+
+// writeRelatedPart writes html and the image it shows as a multipart/related
+// part.
+func writeRelatedPart(w *multipart.Writer, html []byte, image *emailImage) error {
+	var body bytes.Buffer
+	related := multipart.NewWriter(&body)
+
+	err := writeQuotedPrintablePart(related, "text/html; charset=utf-8", html)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	imagePart, err := related.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {image.contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Id":                {fmt.Sprintf("<%s>", image.contentID)},
+		"Content-Disposition":       {"inline"},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = writeBase64Lines(imagePart, image.data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = related.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf(
+			"multipart/related; boundary=%q; type=\"text/html\"", related.Boundary())},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = part.Write(body.Bytes())
+	return errors.Trace(err)
+}
+
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := base64LineLength
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		_, err := io.WriteString(w, encoded[:n]+"\r\n")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// encodeHeaderText encodes s as RFC 2047 encoded-words if it is not plain
+// ASCII. Long values are split into several encoded-words so that the header
+// can be folded between them.
+func encodeHeaderText(s string) string {
+	return mime.QEncoding.Encode("utf-8", s)
+}
+
+// foldHeader formats a header line, folding it at spaces to keep lines within
+// maxHeaderLineLength where possible. Words longer than that, such as long
+// encoded-words, are put on lines of their own.
+func foldHeader(name, value string) string {
+	var b strings.Builder
+	line := name + ":"
+	for _, word := range strings.Split(value, " ") {
+		if len(line)+1+len(word) > maxHeaderLineLength {
+			b.WriteString(line)
+			b.WriteString("\r\n")
+			line = ""
+		}
+		line += " " + word
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// fetchGraph downloads the graph image for an alert so that it can be
+// embedded in the email, since recipients may not be able to reach the server
+// it comes from. It returns nil if the image could not be downloaded.
+func fetchGraph(url string) *emailImage {
+	if url == "" {
+		return nil
+	}
+
+	client := http.Client{Timeout: graphTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(contentType, "image/") {
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxGraphBytes))
+	if err != nil {
+		return nil
+	}
+
+	return &emailImage{
+		contentID:   graphContentID,
+		contentType: contentType,
+		data:        data,
+	}
+}
@@ -0,0 +1,149 @@
+package target
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/state"
+)
+
+func TestFoldHeader(t *testing.T) {
+	value := strings.Repeat("recipient@example.com, ", 10)
+	folded := foldHeader("To", value)
+
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected long header to be folded, got %q\n", folded)
+	}
+	for i, line := range lines {
+		if len(line) > maxHeaderLineLength {
+			t.Errorf("Expected header lines of at most %d characters, got %q\n", maxHeaderLineLength, line)
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("Expected continuation line to start with a space, got %q\n", line)
+		}
+	}
+
+	unfolded := strings.Replace(strings.TrimPrefix(folded, "To: "), "\r\n", "", -1)
+	if unfolded != value {
+		t.Errorf("Expected unfolded header %q, got %q\n", value, unfolded)
+	}
+}
+
+func TestBuildEmail(t *testing.T) {
+	subject := "[Revere] Überwachung/host-ß ist CRITICAL " + strings.Repeat("lang ", 10)
+	headers := []emailHeader{
+		{"From", "revere@example.com"},
+		{"To", "oncall@example.com"},
+		{"Subject", encodeHeaderText(subject)},
+	}
+	image := &emailImage{contentID: graphContentID, contentType: "image/png", data: bytes.Repeat([]byte{0x89}, 200)}
+
+	built, err := buildEmail(headers, []byte("plain ü\n"), []byte("<p>html ü</p>"), image)
+	if err != nil {
+		t.Fatalf("Failed to build email: %s\n", err.Error())
+	}
+
+	header := strings.SplitN(string(built), "\r\n\r\n", 2)[0]
+	for _, line := range strings.Split(header, "\r\n") {
+		if len(line) > maxHeaderLineLength {
+			t.Errorf("Expected header lines of at most %d characters, got %q\n", maxHeaderLineLength, line)
+		}
+	}
+	for _, line := range strings.Split(string(built), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("Expected lines of at most %d characters, got %q\n", maxLineLength, line)
+		}
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(built))
+	if err != nil {
+		t.Fatalf("Failed to parse email: %s\n", err.Error())
+	}
+
+	decoded, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || decoded != subject {
+		t.Errorf("Expected subject %q, got %q (%v)\n", subject, decoded, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative email, got %s (%v)\n", mediaType, err)
+	}
+
+	var types []string
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			break
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		types = append(types, mediaType)
+
+		if mediaType == "text/plain" {
+			body, _ := ioutil.ReadAll(part)
+			if string(body) != "plain ü\r\n" {
+				t.Errorf("Expected plain text body %q, got %q\n", "plain ü\r\n", body)
+			}
+		}
+	}
+	if len(types) != 2 || types[0] != "text/plain" || types[1] != "multipart/related" {
+		t.Errorf("Expected text/plain and multipart/related parts, got %v\n", types)
+	}
+}
+
+func TestEmailHTML(t *testing.T) {
+	a := &Alert{
+		MonitorID:    3,
+		MonitorName:  "disk",
+		SubprobeID:   5,
+		SubprobeName: "host<1>",
+		OldState:     state.Normal,
+		NewState:     state.Critical,
+		Details:      savedDetails{text: "used: 95\n\nGraph: http://graphite/render?target=a"},
+		Host:         "https://revere.example.com",
+	}
+
+	var html bytes.Buffer
+	err := emailHTMLTmpl.Execute(&html, emailData{Alert: a, GraphContentID: graphContentID})
+	if err != nil {
+		t.Fatalf("Failed to render email: %s\n", err.Error())
+	}
+
+	for _, expected := range []string{
+		"host&lt;1&gt;",
+		`<a href="https://revere.example.com/monitors/3/subprobes/5/ack">`,
+		`<a href="http://graphite/render?target=a">`,
+		`<th align="left" valign="top">used</th>`,
+		`src="cid:` + graphContentID + `"`,
+		emailStateColors[state.Critical],
+	} {
+		if !strings.Contains(html.String(), expected) {
+			t.Errorf("Expected email HTML to contain %s\n", expected)
+		}
+	}
+}
+
+func TestTimeRel(t *testing.T) {
+	now := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[time.Time]string{
+		{}:                           "never",
+		now.Add(-10 * time.Second):   "just now",
+		now.Add(-10 * time.Minute):   "10 min. ago",
+		now.Add(-5 * time.Hour):      "5 hours ago",
+		now.Add(-3 * 24 * time.Hour): "3 days ago",
+	}
+	for at, expected := range cases {
+		if actual := timeRel(at, now); actual != expected {
+			t.Errorf("Expected %s for %s, got %s\n", expected, at, actual)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package target
 import (
 	"bytes"
 	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
 	"net/smtp"
 	"strings"
 	"text/template"
@@ -12,6 +14,8 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/durationfmt"
+	"github.com/yext/revere/probe"
 	"github.com/yext/revere/setting"
 	"github.com/yext/revere/state"
 )
@@ -30,12 +34,64 @@ func (_ emailType) New(config types.JSONText) (Target, error) {
 	return newEmail(config)
 }
 
+// Retries waits longer between attempts than other target types, since SMTP
+// outages tend to last a while.
+func (_ emailType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: time.Minute,
+		MaxBackoff:     30 * time.Minute,
+		MaxAttempts:    10,
+	}
+}
+
 func (_ emailType) Alert(Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
-	triggerIDs := make([]db.TriggerID, 0, len(toAlert))
-	for id := range toAlert {
-		triggerIDs = append(triggerIDs, id)
+	graph := fetchGraph(a.GraphImageURL())
+	data := emailData{Alert: a}
+	if graph != nil {
+		data.GraphContentID = graph.contentID
+	}
+
+	var text, html bytes.Buffer
+	err := emailTmpl.Execute(&text, a)
+	if err == nil {
+		err = emailHTMLTmpl.Execute(&html, data)
+	}
+	if err != nil {
+		return []ErrorAndTriggerIDs{{
+			Err: errors.Maskf(err, "render email"),
+			IDs: triggerIDs(toAlert),
+		}}
+	}
+
+	subject := fmt.Sprintf("%s/%s", a.MonitorName, a.SubprobeName)
+	return sendEmail(Db, subject, text.Bytes(), html.Bytes(), graph, toAlert, inactive)
+}
+
+// AlertGroup sends one email listing all of the alerts.
+func (_ emailType) AlertGroup(Db *db.DB, alerts []*Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	var text, html bytes.Buffer
+	err := emailGroupTmpl.Execute(&text, alerts)
+	if err == nil {
+		err = emailGroupHTMLTmpl.Execute(&html, alerts)
+	}
+	if err != nil {
+		return []ErrorAndTriggerIDs{{
+			Err: errors.Maskf(err, "render email"),
+			IDs: triggerIDs(toAlert),
+		}}
 	}
 
+	return sendEmail(Db, GroupSummary(alerts), text.Bytes(), html.Bytes(), nil, toAlert, inactive)
+}
+
+// sendEmail sends an email with the given subject, bodies, and inline image, if
+// any, to the targets in toAlert, with replies going to those and the inactive
+// targets.
+func sendEmail(
+	Db *db.DB, subject string, text, html []byte, image *emailImage,
+	toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	triggerIDs := triggerIDs(toAlert)
+
 	toBuilder := newEmailListBuilder()
 	replyToBuilder := newEmailListBuilder()
 	for _, target := range toAlert {
@@ -54,9 +110,6 @@ func (_ emailType) Alert(Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, i
 		return nil
 	}
 
-	// TODO(eefi): Respect line length limits. Encode headers and body to
-	// avoid UTF-8 causing breaks.
-
 	emailSetting := setting.OutgoingEmailSetting{}
 
 	dbSettings, err := Db.LoadSettingsOfType(emailSetting.Type().Id())
@@ -83,28 +136,23 @@ func (_ emailType) Alert(Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, i
 		}}
 	}
 
-	var b bytes.Buffer
-	b.WriteString(fmt.Sprintf(
-		"Date: %s\n", time.Now().UTC().Format(time.RFC822Z)))
-	b.WriteString(fmt.Sprintf(
-		"From: %s <%s>\n", emailSettings.FromName, emailSettings.FromEmail))
-	b.WriteString(fmt.Sprintf(
-		"Reply-To: %s\n", strings.Join(replyTo, ", ")))
-	b.WriteString(fmt.Sprintf(
-		"To: %s\n", strings.Join(to, ", ")))
-	b.WriteString(fmt.Sprintf(
-		"Subject: [%s] %s/%s\n", emailSettings.SubjectLinePrefix, a.MonitorName, a.SubprobeName))
+	from := mail.Address{Name: emailSettings.FromName, Address: emailSettings.FromEmail}
+	headers := []emailHeader{
+		{"Date", time.Now().UTC().Format(time.RFC1123Z)},
+		{"From", from.String()},
+		{"Reply-To", strings.Join(replyTo, ", ")},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", encodeHeaderText(fmt.Sprintf("[%s] %s", emailSettings.SubjectLinePrefix, subject))},
+	}
 
-	err = emailTmpl.Execute(&b, a)
+	msg, err := buildEmail(headers, text, html, image)
 	if err != nil {
 		return []ErrorAndTriggerIDs{{
-			Err: errors.Maskf(err, "render email"),
+			Err: errors.Maskf(err, "build email"),
 			IDs: triggerIDs,
 		}}
 	}
 
-	msg := []byte(strings.Replace(b.String(), "\n", "\r\n", -1))
-
 	err = smtp.SendMail(emailSettings.SmtpServer, nil, emailSettings.FromEmail, to, msg)
 	if err != nil {
 		return []ErrorAndTriggerIDs{{
@@ -126,8 +174,13 @@ State change: {{.OldState}}->{{.NewState}}
 {{- else -}}
 Has been {{.NewState}} since: {{time .EnteredState}} ({{timerel .EnteredState}})
 {{- end}}
+{{- with .FlapText}}
+{{.}}
+{{- end}}
 {{- if not (isNormal .NewState)}}
 Was last Normal at: {{time .LastNormal}} ({{timerel .LastNormal}})
+
+Acknowledge: {{.AckURL}}
 {{- end}}
 {{if .Description}}
 Description: {{.Description}}
@@ -141,7 +194,139 @@ Probe reading details:
 {{.Details.Text}}
 {{end -}}`
 
-var emailTmpl = template.Must(template.New("email").Funcs(template.FuncMap{
+const emailGroupText = `
+{{len .}} alerts:
+{{range .}}
+{{.NewState}}: {{.MonitorName}}/{{.SubprobeName}} as of {{time .Recorded}}
+{{- if ne .OldState .NewState}} (was {{.OldState}}){{end}}
+{{- with .FlapText}}
+{{.}}
+{{- end}}
+{{.Host}}/monitors/{{.MonitorID}}/subprobes/{{.SubprobeID}}
+{{end -}}`
+
+const emailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #333333;">
+  <div style="background-color: {{stateColor .NewState}}; color: #ffffff; padding: 12px 16px;">
+    <h2 style="margin: 0;">{{.NewState}}: {{.MonitorName}}/{{.SubprobeName}}</h2>
+    <div>as of {{time .Recorded}}</div>
+  </div>
+  <div style="padding: 0 16px;">
+    {{if ne .OldState .NewState}}
+      <p>State change: {{.OldState}} &rarr; {{.NewState}}</p>
+    {{else}}
+      <p>Has been {{.NewState}} since {{time .EnteredState}} ({{timerel .EnteredState}})</p>
+    {{end}}
+    {{with .FlapText}}
+      <p><strong>{{.}}</strong></p>
+    {{end}}
+    {{if not (isNormal .NewState)}}
+      <p>Was last Normal at {{time .LastNormal}} ({{timerel .LastNormal}})</p>
+    {{end}}
+    <p>
+      <a href="{{.Host}}/monitors/{{.MonitorID}}/subprobes/{{.SubprobeID}}">View subprobe</a>
+      {{if not (isNormal .NewState)}}| <a href="{{.AckURL}}">Acknowledge</a>{{end}}
+      | <a href="{{.SilenceURL}}">Silence</a>
+    </p>
+    {{with .Description}}
+      <h3>Description</h3>
+      <p style="white-space: pre-wrap;">{{.}}</p>
+    {{end}}
+    {{with .Response}}
+      <h3>Suggested response</h3>
+      <p style="white-space: pre-wrap;">{{.}}</p>
+    {{end}}
+    {{with detailRows .Details}}
+      <h3>Probe reading details</h3>
+      <table cellpadding="4" style="border-collapse: collapse;">
+        {{range .}}
+          <tr>
+            {{if .Label}}<th align="left" valign="top">{{.Label}}</th><td>{{else}}<td colspan="2">{{end}}
+              {{if .Link}}<a href="{{.Value}}">{{.Value}}</a>{{else}}{{.Value}}{{end}}
+            </td>
+          </tr>
+        {{end}}
+      </table>
+    {{end}}
+    {{with .GraphContentID}}
+      <p><img src="cid:{{.}}" alt="Graph" style="max-width: 100%;"></p>
+    {{end}}
+  </div>
+</body>
+</html>
+`
+
+const emailGroupHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #333333;">
+  <h2>{{len .}} alerts</h2>
+  <table cellpadding="4" style="border-collapse: collapse;">
+    {{range .}}
+      <tr>
+        <td style="background-color: {{stateColor .NewState}}; color: #ffffff;">{{.NewState}}</td>
+        <td>
+          <a href="{{.Host}}/monitors/{{.MonitorID}}/subprobes/{{.SubprobeID}}">{{.MonitorName}}/{{.SubprobeName}}</a>
+          {{if ne .OldState .NewState}}(was {{.OldState}}){{end}}
+          {{with .FlapText}}<br><small>{{.}}</small>{{end}}
+        </td>
+        <td>{{time .Recorded}}</td>
+      </tr>
+    {{end}}
+  </table>
+</body>
+</html>
+`
+
+// emailData is what HTML alert emails are rendered from.
+type emailData struct {
+	*Alert
+
+	// GraphContentID is the content ID of the alert's graph image, if it
+	// is attached.
+	GraphContentID string
+}
+
+// emailDetailRow is a line of probe details. Lines of the form "label: value"
+// are split so that they can be shown as a table.
+type emailDetailRow struct {
+	Label string
+	Value string
+	Link  bool
+}
+
+func emailDetailRows(d probe.Details) []emailDetailRow {
+	if d == nil {
+		return nil
+	}
+
+	var rows []emailDetailRow
+	for _, line := range strings.Split(d.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		row := emailDetailRow{Value: line}
+		if i := strings.Index(line, ": "); i > 0 {
+			row.Label, row.Value = line[:i], line[i+2:]
+		}
+		row.Link = strings.HasPrefix(row.Value, "http://") || strings.HasPrefix(row.Value, "https://")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+var emailStateColors = map[state.State]string{
+	state.Normal:   "#5cb85c",
+	state.Warning:  "#f0ad4e",
+	state.Error:    "#d9534f",
+	state.Critical: "#000000",
+	state.Unknown:  "#808080",
+}
+
+// emailFuncs are shared by the text and HTML email templates.
+var emailFuncs = map[string]interface{}{
 	"isNormal": func(s state.State) bool {
 		return s == state.Normal
 	},
@@ -149,7 +334,31 @@ var emailTmpl = template.Must(template.New("email").Funcs(template.FuncMap{
 		return t.UTC().Format("Mon Jan 2 2006 15:04:05 MST")
 	},
 	"timerel": func(t time.Time) string {
-		// TODO(eefi): Implement.
-		return ""
+		return timeRel(t, time.Now())
+	},
+	"stateColor": func(s state.State) string {
+		return emailStateColors[s]
 	},
-}).Parse(emailText))
+	"detailRows": emailDetailRows,
+}
+
+// timeRel describes how long before now t was.
+func timeRel(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	d := now.Sub(t)
+	if d < time.Minute {
+		return "just now"
+	}
+	return durationfmt.MostSigUnit().Format(d) + " ago"
+}
+
+var emailTmpl = template.Must(template.New("email").Funcs(emailFuncs).Parse(emailText))
+
+var emailGroupTmpl = template.Must(template.New("emailGroup").Funcs(emailFuncs).Parse(emailGroupText))
+
+var emailHTMLTmpl = htmltemplate.Must(htmltemplate.New("emailHTML").Funcs(emailFuncs).Parse(emailHTML))
+
+var emailGroupHTMLTmpl = htmltemplate.Must(htmltemplate.New("emailGroupHTML").Funcs(emailFuncs).Parse(emailGroupHTML))
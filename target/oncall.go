@@ -0,0 +1,77 @@
+package target
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/schedule"
+)
+
+const (
+	onCallViaEmail = "email"
+	onCallViaSlack = "slack"
+)
+
+// OnCall implements a target that alerts whoever is on call for a schedule
+// when the alert is sent, by email or Slack.
+type OnCall struct {
+	ScheduleID db.ScheduleID
+	Via        string
+
+	// onCall describes who the target resolved to when it was last
+	// alerted.
+	onCall string
+}
+
+func newOnCall(configJSON types.JSONText) (Target, error) {
+	var config OnCallDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize target config")
+	}
+
+	return &OnCall{ScheduleID: config.ScheduleID, Via: config.Via}, nil
+}
+
+func (*OnCall) Type() Type {
+	return onCallType{}
+}
+
+func (o *OnCall) Recipients() string {
+	if o.onCall != "" {
+		return o.onCall
+	}
+	return fmt.Sprintf("on-call for schedule %d", o.ScheduleID)
+}
+
+// resolve returns the email or Slack target for whoever is on call for s at
+// t.
+func (o *OnCall) resolve(s *schedule.Schedule, t time.Time) (Target, error) {
+	p := s.OnCall(t)
+	if p == nil {
+		return nil, errors.Errorf("nobody is on call for schedule %s", s.Name)
+	}
+
+	switch o.Via {
+	case onCallViaEmail:
+		if p.Email == "" {
+			return nil, errors.Errorf("%s has no email address in schedule %s", p.Name, s.Name)
+		}
+		o.onCall = fmt.Sprintf("%s <%s> (on call for %s)", p.Name, p.Email, s.Name)
+		return &Email{to: []string{p.Email}, replyTo: []string{p.Email}}, nil
+	case onCallViaSlack:
+		if p.SlackHandle == "" {
+			return nil, errors.Errorf("%s has no Slack handle in schedule %s", p.Name, s.Name)
+		}
+		handle := "@" + strings.TrimPrefix(p.SlackHandle, "@")
+		o.onCall = fmt.Sprintf("%s %s (on call for %s)", p.Name, handle, s.Name)
+		return &Slack{Channel: handle}, nil
+	default:
+		return nil, errors.Errorf("unknown way to alert on-call: %s", o.Via)
+	}
+}
@@ -0,0 +1,65 @@
+package target_test
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+
+	. "github.com/yext/revere/target"
+)
+
+var (
+	onCallTargetType = OnCallType{}
+	onCallId         = 6
+	onCallName       = "On-call Schedule"
+
+	invalidOnCallTargets = []OnCallTarget{
+		{ScheduleID: 0, Via: "email"},
+		{ScheduleID: 1, Via: ""},
+		{ScheduleID: 1, Via: "pager"},
+	}
+	validOnCallTargets = []OnCallTarget{
+		{ScheduleID: 1, Via: "email"},
+		{ScheduleID: 2, Via: "slack"},
+	}
+)
+
+func TestOnCallId(t *testing.T) {
+	if int(onCallTargetType.Id()) != onCallId {
+		t.Errorf("Expected on-call target type id: %d, got %d\n", onCallId, onCallTargetType.Id())
+	}
+}
+
+func TestOnCallName(t *testing.T) {
+	if onCallTargetType.Name() != onCallName {
+		t.Errorf("Expected on-call target type name: %s, got %s\n", onCallName, onCallTargetType.Name())
+	}
+}
+
+func TestInvalidOnCallTargets(t *testing.T) {
+	for _, ot := range invalidOnCallTargets {
+		if errs := ot.Validate(); errs == nil {
+			t.Errorf("Expected error for on-call target: %+v\n", ot)
+		}
+	}
+}
+
+func TestValidOnCallTargets(t *testing.T) {
+	for _, ot := range validOnCallTargets {
+		if errs := ot.Validate(); errs != nil {
+			t.Errorf("Unexpected error for on-call target %+v: %v\n", ot, errs)
+		}
+	}
+}
+
+func TestOnCallRecipientsBeforeAlerting(t *testing.T) {
+	target, err := New(onCallTargetType.Id(), types.JSONText(`{"ScheduleID": 3, "Via": "slack"}`))
+	if err != nil {
+		t.Fatalf("Failed to create on-call target: %s\n", err.Error())
+	}
+
+	expected := "on-call for schedule 3"
+	if r := target.Recipients(); r != expected {
+		t.Errorf("Expected recipients %s, got %s\n", expected, r)
+	}
+}
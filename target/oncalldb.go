@@ -0,0 +1,12 @@
+package target
+
+import (
+	"github.com/yext/revere/db"
+)
+
+// OnCallDBModel defines the JSON serialization format for saving on-call
+// targets' settings in the database.
+type OnCallDBModel struct {
+	ScheduleID db.ScheduleID
+	Via        string
+}
@@ -0,0 +1,106 @@
+package target
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/schedule"
+)
+
+type onCallType struct{}
+
+func init() {
+	registerTargetType(onCallType{})
+}
+
+func (onCallType) ID() db.TargetType {
+	return 6
+}
+
+func (onCallType) New(config types.JSONText) (Target, error) {
+	return newOnCall(config)
+}
+
+func (onCallType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		MaxAttempts:    8,
+	}
+}
+
+// Alert looks up who is on call for each target's schedule, then alerts them
+// as email or Slack targets.
+func (onCallType) Alert(Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	byEmail, bySlack, errs := resolveOnCall(Db, toAlert)
+	if len(byEmail) > 0 {
+		errs = append(errs, emailType{}.Alert(Db, a, byEmail, nil)...)
+	}
+	if len(bySlack) > 0 {
+		errs = append(errs, slackType{}.Alert(Db, a, bySlack, nil)...)
+	}
+	return errs
+}
+
+// AlertGroup is like Alert, but sends one message listing all of the alerts.
+func (onCallType) AlertGroup(Db *db.DB, alerts []*Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	byEmail, bySlack, errs := resolveOnCall(Db, toAlert)
+	if len(byEmail) > 0 {
+		errs = append(errs, emailType{}.AlertGroup(Db, alerts, byEmail, nil)...)
+	}
+	if len(bySlack) > 0 {
+		errs = append(errs, slackType{}.AlertGroup(Db, alerts, bySlack, nil)...)
+	}
+	return errs
+}
+
+// resolveOnCall looks up who is on call for the targets in toAlert, returning
+// the email and Slack targets to alert them with and errors for the targets
+// that could not be resolved.
+func resolveOnCall(Db *db.DB, toAlert map[db.TriggerID]Target) (
+	byEmail, bySlack map[db.TriggerID]Target, errs []ErrorAndTriggerIDs) {
+	byEmail = make(map[db.TriggerID]Target)
+	bySlack = make(map[db.TriggerID]Target)
+
+	now := time.Now().UTC()
+	schedules := make(map[db.ScheduleID]*schedule.Schedule)
+	for id, target := range toAlert {
+		target := target.(*OnCall)
+
+		s, found := schedules[target.ScheduleID]
+		if !found {
+			var err error
+			s, err = schedule.Load(Db, target.ScheduleID)
+			if err != nil {
+				errs = append(errs, ErrorAndTriggerIDs{
+					Err: errors.Maskf(err, "load schedule %d", target.ScheduleID),
+					IDs: []db.TriggerID{id},
+				})
+				continue
+			}
+			if s == nil {
+				errs = append(errs, ErrorAndTriggerIDs{
+					Err: errors.Errorf("schedule %d not found", target.ScheduleID),
+					IDs: []db.TriggerID{id},
+				})
+				continue
+			}
+			schedules[target.ScheduleID] = s
+		}
+
+		t, err := target.resolve(s, now)
+		if err != nil {
+			errs = append(errs, ErrorAndTriggerIDs{Err: err, IDs: []db.TriggerID{id}})
+			continue
+		}
+		if target.Via == onCallViaSlack {
+			bySlack[id] = t
+		} else {
+			byEmail[id] = t
+		}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package target
+
+import (
+	"encoding/json"
+
+	"github.com/yext/revere/db"
+)
+
+type OnCallType struct{}
+
+type OnCallTarget struct {
+	OnCallType
+	ScheduleID db.ScheduleID
+	Via        string
+}
+
+func init() {
+	addType(OnCallType{})
+}
+
+func (OnCallType) Id() db.TargetType {
+	return 6
+}
+
+func (OnCallType) Name() string {
+	return "On-call Schedule"
+}
+
+func (OnCallType) loadFromParams(target string) (VM, error) {
+	var o OnCallTarget
+	err := json.Unmarshal([]byte(target), &o)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (OnCallType) loadFromDb(encodedTarget string) (VM, error) {
+	var o OnCallDBModel
+	err := json.Unmarshal([]byte(encodedTarget), &o)
+	if err != nil {
+		return nil, err
+	}
+
+	return OnCallTarget{
+		ScheduleID: o.ScheduleID,
+		Via:        o.Via,
+	}, nil
+}
+
+func (OnCallType) blank() VM {
+	return OnCallTarget{Via: onCallViaEmail}
+}
+
+func (OnCallType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "oncall-edit.html",
+		"view": "oncall-view.html",
+	}
+}
+
+func (OnCallType) Scripts() map[string][]string {
+	return map[string][]string{}
+}
+
+func (ot OnCallTarget) Serialize() (string, error) {
+	otDB := OnCallDBModel{
+		ScheduleID: ot.ScheduleID,
+		Via:        ot.Via,
+	}
+
+	otDBJSON, err := json.Marshal(otDB)
+	return string(otDBJSON), err
+}
+
+func (OnCallTarget) Type() VMType {
+	return OnCallType{}
+}
+
+func (ot OnCallTarget) Validate() (errs []string) {
+	if ot.ScheduleID <= 0 {
+		errs = append(errs, "A schedule is required.")
+	}
+	if ot.Via != onCallViaEmail && ot.Via != onCallViaSlack {
+		errs = append(errs, "On-call targets must alert by email or Slack.")
+	}
+	return
+}
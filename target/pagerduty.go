@@ -0,0 +1,37 @@
+package target
+
+import (
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+)
+
+type PagerDuty struct {
+	IntegrationKey string
+}
+
+func newPagerDuty(configJSON types.JSONText) (Target, error) {
+	var config PagerDutyDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize target config")
+	}
+
+	return &PagerDuty{IntegrationKey: config.IntegrationKey}, nil
+}
+
+func (PagerDuty) Type() Type {
+	return pagerDutyType{}
+}
+
+// Recipients only shows the end of the integration key, since the key is
+// enough to send events to the service.
+func (p PagerDuty) Recipients() string {
+	if p.IntegrationKey == "" {
+		return "Default integration key"
+	}
+	key := p.IntegrationKey
+	if len(key) > 4 {
+		key = "..." + key[len(key)-4:]
+	}
+	return "Integration key " + key
+}
@@ -0,0 +1,69 @@
+package target_test
+
+import (
+	"testing"
+
+	. "github.com/yext/revere/target"
+)
+
+var (
+	pagerDutyTargetType = PagerDutyType{}
+	pagerDutyId         = 3
+	pagerDutyName       = "PagerDuty"
+
+	invalidIntegrationKeys = []string{"key", "0123456789abcdef0123456789abcde", "0123456789abcdef0123456789abcdef0", "0123456789abcdef-123456789abcdef"}
+	validIntegrationKeys   = []string{"", "0123456789abcdef0123456789abcdef", "R0123456789ABCDEF0123456789ABCDE"}
+)
+
+func TestPagerDutyId(t *testing.T) {
+	if int(pagerDutyTargetType.Id()) != pagerDutyId {
+		t.Errorf("Expected pagerduty target type id: %d, got %d\n", pagerDutyId, pagerDutyTargetType.Id())
+	}
+}
+
+func TestPagerDutyName(t *testing.T) {
+	if pagerDutyTargetType.Name() != pagerDutyName {
+		t.Errorf("Expected pagerduty target type name: %s, got %s\n", pagerDutyName, pagerDutyTargetType.Name())
+	}
+}
+
+func TestLoadEmptyPagerDuty(t *testing.T) {
+	target, err := LoadFromParams(pagerDutyTargetType.Id(), "{}")
+	if err != nil {
+		t.Fatalf("Failed to load empty pagerduty target: %s\n", err.Error())
+	}
+
+	_, ok := target.(PagerDutyTarget)
+	if !ok {
+		t.Fatalf("Invalid target loaded for target type: %s\n", pagerDutyTargetType.Name())
+	}
+}
+
+func TestInvalidPagerDutyIntegrationKey(t *testing.T) {
+	for _, k := range invalidIntegrationKeys {
+		pt := PagerDutyTarget{IntegrationKey: k}
+		if errs := pt.Validate(); errs == nil {
+			t.Errorf("Expected error for integration key: %s\n", k)
+		}
+	}
+}
+
+func TestValidPagerDutyIntegrationKey(t *testing.T) {
+	for _, k := range validIntegrationKeys {
+		pt := PagerDutyTarget{IntegrationKey: k}
+		if errs := pt.Validate(); errs != nil {
+			t.Errorf("Unexpected error for integration key %s: %v\n", k, errs)
+		}
+	}
+}
+
+func TestPagerDutyRecipientsHideKey(t *testing.T) {
+	target, err := New(pagerDutyTargetType.Id(), []byte(`{"IntegrationKey": "0123456789abcdef0123456789abcdef"}`))
+	if err != nil {
+		t.Fatalf("Failed to create pagerduty target: %s\n", err.Error())
+	}
+
+	if r := target.Recipients(); r != "Integration key ...cdef" {
+		t.Errorf("Expected recipients: Integration key ...cdef, got %s\n", r)
+	}
+}
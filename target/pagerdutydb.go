@@ -0,0 +1,9 @@
+package target
+
+// PagerDutyDBModel defines the JSON serialization format for saving PagerDuty
+// targets' settings in the database.
+type PagerDutyDBModel struct {
+	// IntegrationKey overrides the default integration key from the
+	// PagerDuty setting if set.
+	IntegrationKey string
+}
@@ -0,0 +1,124 @@
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+var (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	pagerDutyClient    = &http.Client{Timeout: 10 * time.Second}
+
+	pagerDutySeverities = map[state.State]string{
+		state.Warning:  "warning",
+		state.Unknown:  "info",
+		state.Error:    "error",
+		state.Critical: "critical",
+	}
+)
+
+// pagerDutyEvent is a PagerDuty Events API v2 event.
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Client      string            `json:"client,omitempty"`
+	ClientURL   string            `json:"client_url,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component"`
+	CustomDetails map[string]string `json:"custom_details"`
+}
+
+// newPagerDutyEvent makes the event for a sent to the integration with the
+// given key. The event resolves the subprobe's incident if it is Normal, and
+// triggers it otherwise.
+func newPagerDutyEvent(key string, a *Alert) pagerDutyEvent {
+	e := pagerDutyEvent{
+		RoutingKey: key,
+		DedupKey:   pagerDutyDedupKey(a),
+		Client:     "Revere",
+		ClientURL: fmt.Sprintf("%s/monitors/%d/subprobes/%d",
+			a.Host, a.MonitorID, a.SubprobeID),
+	}
+
+	if a.NewState == state.Normal {
+		e.EventAction = "resolve"
+		return e
+	}
+
+	e.EventAction = "trigger"
+
+	details := map[string]string{
+		"State": a.NewState.String(),
+	}
+	if a.OldState != a.NewState {
+		details["State change"] = fmt.Sprintf("%s->%s", a.OldState, a.NewState)
+	}
+	if flapText := a.FlapText(); flapText != "" {
+		details["Flapping"] = flapText
+	}
+	details["Last Normal"] = a.LastNormal.UTC().Format(timeFormat)
+	if a.Description != "" {
+		details["Description"] = a.Description
+	}
+	if a.Response != "" {
+		details["Suggested response"] = a.Response
+	}
+	if a.Details != nil {
+		details["Details"] = a.Details.Text()
+	}
+
+	e.Payload = &pagerDutyPayload{
+		Summary: fmt.Sprintf("%s/%s is %s",
+			a.MonitorName, a.SubprobeName, a.NewState),
+		Source:        a.SubprobeName,
+		Severity:      pagerDutySeverities[a.NewState],
+		Timestamp:     a.Recorded.UTC().Format(time.RFC3339),
+		Component:     a.MonitorName,
+		CustomDetails: details,
+	}
+	return e
+}
+
+// pagerDutyDedupKey identifies a's subprobe, so that all events for a
+// subprobe apply to the same incident.
+func pagerDutyDedupKey(a *Alert) string {
+	return fmt.Sprintf("revere-%d-%d", a.MonitorID, a.SubprobeID)
+}
+
+func sendPagerDutyEvent(e pagerDutyEvent) error {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return errors.Maskf(err, "formatting pagerduty event")
+	}
+
+	resp, err := pagerDutyClient.Post(pagerDutyEventsURL, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		return errors.Maskf(err, "sending pagerduty event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf(
+			"not-Accepted HTTP status code: %d, when sending pagerduty event: %s",
+			resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package target
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+const testPagerDutyKey = "0123456789abcdef0123456789abcdef"
+
+// withPagerDutyServer sends PagerDuty events to a test server responding with
+// status, for the duration of f, and returns the events it received.
+func withPagerDutyServer(t *testing.T, status int, f func()) []pagerDutyEvent {
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var e pagerDutyEvent
+		if err := json.NewDecoder(req.Body).Decode(&e); err != nil {
+			t.Errorf("Could not decode pagerduty event: %s", err)
+		}
+		events = append(events, e)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	url := pagerDutyEventsURL
+	pagerDutyEventsURL = server.URL
+	defer func() { pagerDutyEventsURL = url }()
+
+	f()
+	return events
+}
+
+func TestPagerDutyResolvesInactiveTargets(t *testing.T) {
+	a := &Alert{
+		MonitorID:  1,
+		SubprobeID: 2,
+		OldState:   state.Warning,
+		NewState:   state.Normal,
+		Recorded:   time.Now(),
+	}
+	if !(pagerDutyType{}).Resolves(a) {
+		t.Fatalf("Expected Warning->Normal to resolve")
+	}
+
+	inactive := []Target{&PagerDuty{IntegrationKey: testPagerDutyKey}}
+	var errs []ErrorAndTriggerIDs
+	events := withPagerDutyServer(t, http.StatusAccepted, func() {
+		// No trigger alerts on exit, so every target is inactive.
+		errs = pagerDutyType{}.Alert(nil, a, map[db.TriggerID]Target{}, inactive)
+	})
+
+	if errs != nil {
+		t.Errorf("Unexpected errors resolving incident: %v", errs)
+	}
+	if len(events) != 1 || events[0].EventAction != "resolve" || events[0].RoutingKey != testPagerDutyKey {
+		t.Errorf("Expected one resolve event for %s, got %+v", testPagerDutyKey, events)
+	}
+}
+
+func TestPagerDutyResolveFailureIsRetried(t *testing.T) {
+	a := &Alert{
+		MonitorID:  1,
+		SubprobeID: 2,
+		OldState:   state.Warning,
+		NewState:   state.Normal,
+		Recorded:   time.Now(),
+	}
+	inactive := []Target{&PagerDuty{IntegrationKey: testPagerDutyKey}}
+
+	var errs []ErrorAndTriggerIDs
+	withPagerDutyServer(t, http.StatusInternalServerError, func() {
+		errs = pagerDutyType{}.Alert(nil, a, map[db.TriggerID]Target{}, inactive)
+	})
+	if len(errs) != 1 || errs[0].Err == nil || len(errs[0].IDs) != 0 {
+		t.Fatalf("Expected an error without triggers for the failed resolve, got %+v", errs)
+	}
+
+	events := withPagerDutyServer(t, http.StatusAccepted, func() {
+		errs = pagerDutyType{}.Alert(nil, a, map[db.TriggerID]Target{}, inactive)
+	})
+	if errs != nil {
+		t.Errorf("Unexpected errors retrying resolve: %v", errs)
+	}
+	if len(events) != 1 || events[0].EventAction != "resolve" {
+		t.Errorf("Expected the retry to resolve the incident, got %+v", events)
+	}
+}
+
+type textDetails string
+
+func (d textDetails) Text() string {
+	return string(d)
+}
+
+func TestNewPagerDutyEvent(t *testing.T) {
+	recorded := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := Alert{
+		MonitorID:    12,
+		MonitorName:  "disk",
+		SubprobeID:   345,
+		SubprobeName: "db1",
+		Recorded:     recorded,
+		LastNormal:   recorded.Add(-time.Hour),
+		Host:         "https://revere.example.com",
+	}
+
+	cases := []struct {
+		name     string
+		modify   func(a *Alert)
+		action   string
+		severity string
+		details  map[string]string
+	}{
+		{
+			name:     "new incident",
+			modify:   func(a *Alert) { a.OldState, a.NewState = state.Normal, state.Error },
+			action:   "trigger",
+			severity: "error",
+			details:  map[string]string{"State change": "Normal->ERROR"},
+		},
+		{
+			name: "repeat with details",
+			modify: func(a *Alert) {
+				a.OldState, a.NewState = state.Critical, state.Critical
+				a.Details = textDetails("Measured: 97\nThreshold: 95")
+				a.Description = "Disk usage"
+			},
+			action:   "trigger",
+			severity: "critical",
+			details: map[string]string{
+				"State":       "CRITICAL",
+				"Details":     "Measured: 97\nThreshold: 95",
+				"Description": "Disk usage",
+			},
+		},
+		{
+			name:   "recovery",
+			modify: func(a *Alert) { a.OldState, a.NewState = state.Warning, state.Normal },
+			action: "resolve",
+		},
+	}
+
+	for _, c := range cases {
+		a := base
+		c.modify(&a)
+		e := newPagerDutyEvent(testPagerDutyKey, &a)
+
+		if e.RoutingKey != testPagerDutyKey {
+			t.Errorf("%s: expected routing key %s, got %s\n", c.name, testPagerDutyKey, e.RoutingKey)
+		}
+		if e.DedupKey != "revere-12-345" {
+			t.Errorf("%s: expected dedup key revere-12-345, got %s\n", c.name, e.DedupKey)
+		}
+		if e.EventAction != c.action {
+			t.Errorf("%s: expected action %s, got %s\n", c.name, c.action, e.EventAction)
+		}
+		if e.ClientURL != "https://revere.example.com/monitors/12/subprobes/345" {
+			t.Errorf("%s: unexpected client URL %s\n", c.name, e.ClientURL)
+		}
+
+		if c.action == "resolve" {
+			if e.Payload != nil {
+				t.Errorf("%s: expected no payload, got %+v\n", c.name, e.Payload)
+			}
+			continue
+		}
+		if e.Payload == nil {
+			t.Fatalf("%s: expected a payload\n", c.name)
+		}
+		if e.Payload.Severity != c.severity {
+			t.Errorf("%s: expected severity %s, got %s\n", c.name, c.severity, e.Payload.Severity)
+		}
+		if e.Payload.Timestamp != "2026-01-02T03:04:05Z" {
+			t.Errorf("%s: unexpected timestamp %s\n", c.name, e.Payload.Timestamp)
+		}
+		for k, v := range c.details {
+			if e.Payload.CustomDetails[k] != v {
+				t.Errorf("%s: expected custom detail %s to be %q, got %q\n", c.name, k, v, e.Payload.CustomDetails[k])
+			}
+		}
+	}
+}
+
+func TestSendPagerDutyEvent(t *testing.T) {
+	cases := []struct {
+		status  int
+		wantErr bool
+	}{
+		{http.StatusAccepted, false},
+		{http.StatusOK, true},
+		{http.StatusBadRequest, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+	}
+
+	for _, c := range cases {
+		e := pagerDutyEvent{RoutingKey: testPagerDutyKey, EventAction: "resolve", DedupKey: "revere-1-2"}
+		var err error
+		events := withPagerDutyServer(t, c.status, func() {
+			err = sendPagerDutyEvent(e)
+		})
+
+		if (err != nil) != c.wantErr {
+			t.Errorf("Status %d: expected error %v, got %v\n", c.status, c.wantErr, err)
+		}
+		if len(events) != 1 || events[0] != e {
+			t.Errorf("Status %d: expected server to receive %+v, got %+v\n", c.status, e, events)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package target
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/setting"
+	"github.com/yext/revere/state"
+)
+
+type pagerDutyType struct{}
+
+func init() {
+	registerTargetType(pagerDutyType{})
+}
+
+func (pagerDutyType) ID() db.TargetType {
+	return 3
+}
+
+func (pagerDutyType) New(config types.JSONText) (Target, error) {
+	return newPagerDuty(config)
+}
+
+// Retries tries again quickly and for longer than other target types, since
+// pages are urgent.
+func (pagerDutyType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		MaxAttempts:    12,
+	}
+}
+
+// Resolves returns whether a is a recovery, so that the subprobe's incident is
+// resolved even if none of its PagerDuty triggers alert on exit.
+func (pagerDutyType) Resolves(a *Alert) bool {
+	return a.NewState == state.Normal && a.OldState != state.Normal
+}
+
+// Alert triggers a PagerDuty incident for the alerting subprobe, or resolves
+// it once the subprobe is Normal again. Incidents are resolved for inactive
+// targets too, so that they are not left open by triggers that don't alert on
+// exit; failing to resolve them is an error without trigger IDs, so that it is
+// retried like the others.
+func (pagerDutyType) Alert(
+	Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	keys := pagerDutyKeys(a, toAlert, inactive)
+
+	// The default integration key is only needed for targets without one of
+	// their own.
+	if ids, ok := keys[""]; ok {
+		defaultKey, err := loadDefaultPagerDutyKey(Db)
+		if err != nil {
+			return []ErrorAndTriggerIDs{{Err: errors.Trace(err), IDs: ids}}
+		}
+
+		if defaultKey != "" {
+			delete(keys, "")
+			keys[defaultKey] = append(keys[defaultKey], ids...)
+		}
+	}
+
+	var errs []ErrorAndTriggerIDs
+	for key, ids := range keys {
+		if key == "" {
+			if len(ids) > 0 {
+				errs = append(errs, ErrorAndTriggerIDs{
+					Err: errors.New("no pagerduty integration key configured"),
+					IDs: ids,
+				})
+			}
+			continue
+		}
+
+		err := sendPagerDutyEvent(newPagerDutyEvent(key, a))
+		if err != nil {
+			errs = append(errs, ErrorAndTriggerIDs{
+				Err: errors.Trace(err),
+				IDs: ids,
+			})
+		}
+	}
+
+	return errs
+}
+
+// pagerDutyKeys returns the integration keys a is sent to, with the triggers
+// sent to each; an empty key stands for the default one. Each integration key
+// only needs one event, which may cover several triggers. Recoveries are sent
+// to the keys of inactive targets too, with no triggers.
+func pagerDutyKeys(a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) map[string][]db.TriggerID {
+	keys := make(map[string][]db.TriggerID)
+	for id, target := range toAlert {
+		key := target.(*PagerDuty).IntegrationKey
+		keys[key] = append(keys[key], id)
+	}
+	if a.NewState == state.Normal {
+		for _, target := range inactive {
+			key := target.(*PagerDuty).IntegrationKey
+			if _, ok := keys[key]; !ok {
+				keys[key] = nil
+			}
+		}
+	}
+	return keys
+}
+
+// loadDefaultPagerDutyKey returns the integration key in PagerDuty's settings,
+// or "" if there is none.
+func loadDefaultPagerDutyKey(Db *db.DB) (string, error) {
+	pagerDutySetting := setting.PagerDutySetting{}
+	dbSettings, err := Db.LoadSettingsOfType(pagerDutySetting.Type().Id())
+	if err != nil {
+		return "", errors.Maskf(err, "getting settings from db")
+	}
+	if len(dbSettings) == 0 {
+		return "", nil
+	}
+
+	settingsFromDB, err := setting.LoadFromDB(pagerDutySetting.Type().Id(), dbSettings[0].Setting)
+	if err != nil {
+		return "", errors.Maskf(err, "unmarshalling db settings")
+	}
+
+	pagerDutySettings, found := settingsFromDB.(*setting.PagerDutySetting)
+	if !found {
+		return "", errors.New("extracting pagerduty settings")
+	}
+	return pagerDutySettings.IntegrationKey, nil
+}
@@ -0,0 +1,84 @@
+package target
+
+import (
+	"encoding/json"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/setting"
+)
+
+type PagerDutyType struct{}
+
+type PagerDutyTarget struct {
+	PagerDutyType
+	IntegrationKey string
+}
+
+func init() {
+	addType(PagerDutyType{})
+}
+
+func (PagerDutyType) Id() db.TargetType {
+	return 3
+}
+
+func (PagerDutyType) Name() string {
+	return "PagerDuty"
+}
+
+func (PagerDutyType) loadFromParams(target string) (VM, error) {
+	var p PagerDutyTarget
+	err := json.Unmarshal([]byte(target), &p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (PagerDutyType) loadFromDb(encodedTarget string) (VM, error) {
+	var p PagerDutyDBModel
+	err := json.Unmarshal([]byte(encodedTarget), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return PagerDutyTarget{
+		IntegrationKey: p.IntegrationKey,
+	}, nil
+}
+
+func (PagerDutyType) blank() VM {
+	return PagerDutyTarget{}
+}
+
+func (PagerDutyType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "pagerduty-edit.html",
+		"view": "pagerduty-view.html",
+	}
+}
+
+func (PagerDutyType) Scripts() map[string][]string {
+	return map[string][]string{}
+}
+
+func (pt PagerDutyTarget) Serialize() (string, error) {
+	ptDB := PagerDutyDBModel{
+		IntegrationKey: pt.IntegrationKey,
+	}
+
+	ptDBJSON, err := json.Marshal(ptDB)
+	return string(ptDBJSON), err
+}
+
+func (PagerDutyTarget) Type() VMType {
+	return PagerDutyType{}
+}
+
+func (pt PagerDutyTarget) Validate() (errs []string) {
+	// A blank key falls back to the default from the PagerDuty setting.
+	if pt.IntegrationKey != "" && !setting.PagerDutyIntegrationKey.MatchString(pt.IntegrationKey) {
+		errs = append(errs, "Invalid PagerDuty integration key. Should be 32 letters and digits.")
+	}
+	return
+}
@@ -0,0 +1,117 @@
+package target
+
+import (
+	"strings"
+)
+
+// Redacted stands in for the secrets of targets, such as PagerDuty keys, where
+// targets are shown to people who may not change them, such as viewers and
+// the history.
+const Redacted = "REDACTED"
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// Redact returns t with its secrets replaced by Redacted.
+func Redact(t VM) VM {
+	switch t := t.(type) {
+	case PagerDutyTarget:
+		t.IntegrationKey = redact(t.IntegrationKey)
+		return t
+	case WebhookTarget:
+		t.HMACSecret = redact(t.HMACSecret)
+		t.Headers = redactHeaders(t.Headers)
+		return t
+	case TeamsTarget:
+		t.WebhookURL = redact(t.WebhookURL)
+		return t
+	default:
+		return t
+	}
+}
+
+// Unredact returns t, as sent back by someone it was shown to redacted, with
+// its redacted secrets taken from old, the target it replaces. It returns
+// false if a secret is redacted and old has none to take its place, because
+// it is not of the same type or lacks the header.
+func Unredact(t, old VM) (VM, bool) {
+	switch t := t.(type) {
+	case PagerDutyTarget:
+		if t.IntegrationKey != Redacted {
+			return t, true
+		}
+		o, ok := old.(PagerDutyTarget)
+		t.IntegrationKey = o.IntegrationKey
+		return t, ok
+	case WebhookTarget:
+		o, ok := old.(WebhookTarget)
+		if t.HMACSecret == Redacted {
+			if !ok {
+				return t, false
+			}
+			t.HMACSecret = o.HMACSecret
+		}
+		t.Headers, ok = unredactHeaders(t.Headers, o.Headers)
+		return t, ok
+	case TeamsTarget:
+		if t.WebhookURL != Redacted {
+			return t, true
+		}
+		o, ok := old.(TeamsTarget)
+		t.WebhookURL = o.WebhookURL
+		return t, ok
+	default:
+		return t, true
+	}
+}
+
+// redactHeaders redacts the values of webhook headers, given one
+// "Name: Value" header per line, as they often hold credentials.
+func redactHeaders(headers string) string {
+	lines := strings.Split(headers, "\n")
+	for i, line := range lines {
+		if name, value, ok := splitHeader(line); ok {
+			lines[i] = name + ": " + redact(value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unredactHeaders returns headers with the values that are redacted taken
+// from the header of the same name in old. It returns false if old has no such
+// header.
+func unredactHeaders(headers, old string) (string, bool) {
+	oldValues := make(map[string]string)
+	for _, line := range strings.Split(old, "\n") {
+		if name, value, ok := splitHeader(line); ok {
+			oldValues[strings.ToLower(name)] = value
+		}
+	}
+
+	lines := strings.Split(headers, "\n")
+	for i, line := range lines {
+		name, value, ok := splitHeader(line)
+		if !ok || value != Redacted {
+			continue
+		}
+		value, ok = oldValues[strings.ToLower(name)]
+		if !ok {
+			return headers, false
+		}
+		lines[i] = name + ": " + value
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// splitHeader splits a "Name: Value" header line.
+func splitHeader(line string) (name, value string, ok bool) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+}
@@ -0,0 +1,73 @@
+package target
+
+import (
+	"testing"
+)
+
+func TestRedactTarget(t *testing.T) {
+	cases := []struct {
+		t, expected VM
+	}{
+		{PagerDutyTarget{IntegrationKey: testPagerDutyKey}, PagerDutyTarget{IntegrationKey: Redacted}},
+		{PagerDutyTarget{}, PagerDutyTarget{}},
+		{
+			WebhookTarget{URL: "https://example.com", HMACSecret: "s3cret"},
+			WebhookTarget{URL: "https://example.com", HMACSecret: Redacted},
+		},
+		{
+			WebhookTarget{Headers: "Authorization: Bearer abc\n\nX-Team: ops"},
+			WebhookTarget{Headers: "Authorization: REDACTED\n\nX-Team: REDACTED"},
+		},
+		{TeamsTarget{WebhookURL: "https://teams/hook"}, TeamsTarget{WebhookURL: Redacted}},
+		{SlackTarget{Channel: "#ops"}, SlackTarget{Channel: "#ops"}},
+	}
+
+	for _, c := range cases {
+		if actual := Redact(c.t); actual != c.expected {
+			t.Errorf("Expected %+v redacted to %+v, got %+v\n", c.t, c.expected, actual)
+		}
+	}
+}
+
+func TestUnRedact(t *testing.T) {
+	pagerDuty := PagerDutyTarget{IntegrationKey: testPagerDutyKey}
+	webhook := WebhookTarget{
+		URL:        "https://example.com",
+		Headers:    "Authorization: Bearer abc\nX-Team: ops",
+		HMACSecret: "s3cret",
+	}
+	teams := TeamsTarget{WebhookURL: "https://teams/hook"}
+
+	cases := []struct {
+		name     string
+		t, old   VM
+		expected VM
+		ok       bool
+	}{
+		{"PagerDuty", Redact(pagerDuty), pagerDuty, pagerDuty, true},
+		{"PagerDuty changed", pagerDuty, PagerDutyTarget{}, pagerDuty, true},
+		{"PagerDuty without old", Redact(pagerDuty), nil, PagerDutyTarget{}, false},
+		{"webhook", Redact(webhook), webhook, webhook, true},
+		{
+			"webhook header renamed",
+			WebhookTarget{Headers: "authorization: REDACTED\nX-New: new"},
+			webhook,
+			WebhookTarget{Headers: "authorization: Bearer abc\nX-New: new"},
+			true,
+		},
+		{"webhook header added", WebhookTarget{Headers: "X-Key: REDACTED"}, webhook, nil, false},
+		{"webhook without old", Redact(webhook), pagerDuty, nil, false},
+		{"Teams", Redact(teams), teams, teams, true},
+		{"Teams without old", Redact(teams), webhook, nil, false},
+		{"Slack", SlackTarget{Channel: "#ops"}, nil, SlackTarget{Channel: "#ops"}, true},
+	}
+
+	for _, c := range cases {
+		actual, ok := Unredact(c.t, c.old)
+		if ok != c.ok {
+			t.Errorf("%s: expected ok %t, got %t\n", c.name, c.ok, ok)
+		} else if ok && actual != c.expected {
+			t.Errorf("%s: expected %+v, got %+v\n", c.name, c.expected, actual)
+		}
+	}
+}
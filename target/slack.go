@@ -1,6 +1,8 @@
 package target
 
 import (
+	"strings"
+
 	"github.com/jmoiron/sqlx/types"
 	"github.com/juju/errors"
 )
@@ -22,3 +24,10 @@ func newSlack(configJSON types.JSONText) (Target, error) {
 func (Slack) Type() Type {
 	return slackType{}
 }
+
+func (s Slack) Recipients() string {
+	if strings.HasPrefix(s.Channel, "#") || strings.HasPrefix(s.Channel, "@") {
+		return s.Channel
+	}
+	return "#" + s.Channel
+}
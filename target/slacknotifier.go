@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/yext/revere/state"
@@ -28,8 +29,11 @@ var (
 	}
 )
 
+// slackNotifier sends a message about alert, or about the alerts in group if
+// it is set.
 type slackNotifier struct {
 	alert *Alert
+	group []*Alert
 	name  string
 	url   string
 }
@@ -88,6 +92,10 @@ func (s slackNotifier) send(channel string) error {
 }
 
 func (s slackNotifier) formatMessage(channel string) (io.Reader, error) {
+	if s.group != nil {
+		return s.formatGroupMessage(channel)
+	}
+
 	var text string
 	if s.alert.OldState != s.alert.NewState {
 		text = fmt.Sprintf("State change: %s->%s", s.alert.OldState, s.alert.NewState)
@@ -96,9 +104,13 @@ func (s slackNotifier) formatMessage(channel string) (io.Reader, error) {
 			s.alert.NewState, s.alert.EnteredState.UTC().Format(timeFormat))
 	}
 
+	if flapText := s.alert.FlapText(); flapText != "" {
+		text = fmt.Sprintf("%s\n%s", text, flapText)
+	}
+
 	if s.alert.NewState != state.Normal {
-		text = fmt.Sprintf("%s\nWas last Normal at: %s",
-			text, s.alert.LastNormal.UTC().Format(timeFormat))
+		text = fmt.Sprintf("%s\nWas last Normal at: %s\n<%s|Acknowledge>",
+			text, s.alert.LastNormal.UTC().Format(timeFormat), s.alert.AckURL())
 	}
 
 	payload := payload{
@@ -124,3 +136,39 @@ func (s slackNotifier) formatMessage(channel string) (io.Reader, error) {
 	}
 	return bytes.NewBuffer(buf), nil
 }
+
+func (s slackNotifier) formatGroupMessage(channel string) (io.Reader, error) {
+	worst := state.Normal
+	lines := make([]string, len(s.group))
+	for i, a := range s.group {
+		if a.NewState > worst {
+			worst = a.NewState
+		}
+		lines[i] = fmt.Sprintf("<%s/monitors/%d/subprobes/%d|%s/%s>: %s",
+			a.Host, a.MonitorID, a.SubprobeID, a.MonitorName, a.SubprobeName, a.NewState)
+		if a.OldState != a.NewState {
+			lines[i] = fmt.Sprintf("%s (was %s)", lines[i], a.OldState)
+		}
+	}
+
+	summary := GroupSummary(s.group)
+	payload := payload{
+		Username: s.name,
+		Channel:  channel,
+		Attachments: []attachment{
+			{
+				Title:     summary,
+				Fallback:  summary,
+				Color:     stateColors[worst],
+				Text:      strings.Join(lines, "\n"),
+				Timestamp: s.group[len(s.group)-1].Recorded.Unix(),
+			},
+		},
+	}
+
+	buf, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewBuffer(buf), nil
+}
@@ -1,6 +1,8 @@
 package target
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx/types"
 	"github.com/juju/errors"
 
@@ -22,12 +24,29 @@ func (slackType) New(config types.JSONText) (Target, error) {
 	return newSlack(config)
 }
 
+func (slackType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		MaxAttempts:    8,
+	}
+}
+
 func (slackType) Alert(
 	Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
-	triggerIDs := make([]db.TriggerID, 0, len(toAlert))
-	for id := range toAlert {
-		triggerIDs = append(triggerIDs, id)
-	}
+	return sendSlack(Db, slackNotifier{alert: a}, toAlert)
+}
+
+// AlertGroup sends one message to each channel listing all of the alerts.
+func (slackType) AlertGroup(
+	Db *db.DB, alerts []*Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	return sendSlack(Db, slackNotifier{group: alerts}, toAlert)
+}
+
+// sendSlack sends notifier's message to the channels of the targets in
+// toAlert, using the bot name and webhook URL from the Slack settings.
+func sendSlack(Db *db.DB, notifier slackNotifier, toAlert map[db.TriggerID]Target) []ErrorAndTriggerIDs {
+	triggerIDs := triggerIDs(toAlert)
 
 	channels := make(map[string]struct{})
 	for _, target := range toAlert {
@@ -60,11 +79,8 @@ func (slackType) Alert(
 		}}
 	}
 
-	notifier := slackNotifier{
-		alert: a,
-		name:  slackSettings.BotName,
-		url:   slackSettings.WebhookURL,
-	}
+	notifier.name = slackSettings.BotName
+	notifier.url = slackSettings.WebhookURL
 	err = notifier.sendAll(channels)
 	if err != nil {
 		return []ErrorAndTriggerIDs{{
@@ -17,6 +17,9 @@ var (
 // Target defines a common abstraction for individual targets.
 type Target interface {
 	Type() Type
+
+	// Recipients describes who the target alerts, for the alert history.
+	Recipients() string
 }
 
 // New makes a Target of the given type and settings.
@@ -27,6 +30,14 @@ func New(typeID db.TargetType, config types.JSONText) (Target, error) {
 	return nil, errors.Errorf("unknown target type %d", typeID)
 }
 
+// LookupType returns the target type with the given ID.
+func LookupType(typeID db.TargetType) (Type, error) {
+	if targetType, found := daemonTargetTypes[typeID]; found {
+		return targetType, nil
+	}
+	return nil, errors.Errorf("unknown target type %d", typeID)
+}
+
 // registerTargetType registers a target type onto a type dictionary
 func registerTargetType(t Type) {
 	if _, exists := daemonTargetTypes[t.ID()]; !exists {
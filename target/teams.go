@@ -0,0 +1,36 @@
+package target
+
+import (
+	"net/url"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+)
+
+type Teams struct {
+	WebhookURL string
+}
+
+func newTeams(configJSON types.JSONText) (Target, error) {
+	var config TeamsDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize target config")
+	}
+
+	return &Teams{WebhookURL: config.WebhookURL}, nil
+}
+
+func (Teams) Type() Type {
+	return teamsType{}
+}
+
+// Recipients only shows the webhook's host, since its path is enough to post
+// to the channel.
+func (t Teams) Recipients() string {
+	u, err := url.Parse(t.WebhookURL)
+	if err != nil {
+		return "Teams webhook"
+	}
+	return "Teams webhook at " + u.Host
+}
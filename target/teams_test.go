@@ -0,0 +1,102 @@
+package target_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	. "github.com/yext/revere/target"
+)
+
+var (
+	teamsTargetType = TeamsType{}
+	teamsId         = 5
+	teamsName       = "Microsoft Teams"
+
+	invalidTeamsWebhookURLs = []string{"", "example.com/webhook", "http://example.com/webhook"}
+	validTeamsWebhookURLs   = []string{"https://example.webhook.office.com/webhookb2/abc"}
+)
+
+func TestTeamsId(t *testing.T) {
+	if int(teamsTargetType.Id()) != teamsId {
+		t.Errorf("Expected teams target type id: %d, got %d\n", teamsId, teamsTargetType.Id())
+	}
+}
+
+func TestTeamsName(t *testing.T) {
+	if teamsTargetType.Name() != teamsName {
+		t.Errorf("Expected teams target type name: %s, got %s\n", teamsName, teamsTargetType.Name())
+	}
+}
+
+func TestInvalidTeamsWebhookURL(t *testing.T) {
+	for _, u := range invalidTeamsWebhookURLs {
+		tt := TeamsTarget{WebhookURL: u}
+		if errs := tt.Validate(); errs == nil {
+			t.Errorf("Expected error for webhook URL: %s\n", u)
+		}
+	}
+}
+
+func TestValidTeamsWebhookURL(t *testing.T) {
+	for _, u := range validTeamsWebhookURLs {
+		tt := TeamsTarget{WebhookURL: u}
+		if errs := tt.Validate(); errs != nil {
+			t.Errorf("Unexpected error for webhook URL %s: %v\n", u, errs)
+		}
+	}
+}
+
+func TestTeamsAlert(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	target, err := New(teamsTargetType.Id(), types.JSONText(`{"WebhookURL": "`+server.URL+`"}`))
+	if err != nil {
+		t.Fatalf("Failed to create teams target: %s\n", err.Error())
+	}
+
+	a := &Alert{
+		MonitorID:    7,
+		MonitorName:  "monitor",
+		SubprobeID:   9,
+		SubprobeName: "db.host-1",
+		OldState:     state.Normal,
+		NewState:     state.Error,
+		Recorded:     time.Now(),
+		Host:         "https://revere.example.com",
+	}
+	errs := target.Type().Alert(nil, a, map[db.TriggerID]Target{1: target}, nil)
+	if errs != nil {
+		t.Fatalf("Unexpected errors sending teams card: %v\n", errs)
+	}
+
+	if !json.Valid([]byte(body)) {
+		t.Fatalf("Invalid teams message: %s\n", body)
+	}
+	expected := []string{
+		`"style":"attention"`,
+		"State change: Normal-\\u003eERROR",
+		"Last Normal",
+		"https://revere.example.com/monitors/7/subprobes/9",
+		"https://revere.example.com/redirectToSilence?id=7\\u0026subprobe=db.host-1",
+	}
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("Expected teams message to contain %s, got %s\n", e, body)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/state"
+)
+
+var (
+	teamsClient = &http.Client{Timeout: 10 * time.Second}
+
+	// teamsStateStyles are the adaptive card container styles used to color
+	// cards by state.
+	teamsStateStyles = map[state.State]string{
+		state.Normal:   "good",
+		state.Warning:  "warning",
+		state.Error:    "attention",
+		state.Critical: "attention",
+		state.Unknown:  "emphasis",
+	}
+
+	// teamsStateColors are the adaptive card text colors used for the state.
+	teamsStateColors = map[state.State]string{
+		state.Normal:   "good",
+		state.Warning:  "warning",
+		state.Error:    "attention",
+		state.Critical: "attention",
+		state.Unknown:  "default",
+	}
+)
+
+type teamsNotifier struct {
+	alert *Alert
+}
+
+// teamsMessage is a Teams incoming webhook message carrying a single adaptive
+// card.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string          `json:"$schema"`
+	Type    string          `json:"type"`
+	Version string          `json:"version"`
+	Body    []teamsElement  `json:"body"`
+	Actions []teamsAction   `json:"actions"`
+	MSTeams teamsCardLayout `json:"msteams"`
+}
+
+type teamsCardLayout struct {
+	Width string `json:"width"`
+}
+
+// teamsElement is an adaptive card element. Only the fields used by the
+// element's type are set.
+type teamsElement struct {
+	Type   string         `json:"type"`
+	Style  string         `json:"style,omitempty"`
+	Bleed  bool           `json:"bleed,omitempty"`
+	Items  []teamsElement `json:"items,omitempty"`
+	Text   string         `json:"text,omitempty"`
+	Size   string         `json:"size,omitempty"`
+	Weight string         `json:"weight,omitempty"`
+	Color  string         `json:"color,omitempty"`
+	Wrap   bool           `json:"wrap,omitempty"`
+	Facts  []teamsFact    `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func (t teamsNotifier) send(webhookURL string) error {
+	message, err := json.Marshal(t.formatMessage())
+	if err != nil {
+		return errors.Maskf(err, "formatting teams message")
+	}
+
+	resp, err := teamsClient.Post(webhookURL, "application/json", bytes.NewReader(message))
+	if err != nil {
+		return errors.Maskf(err, "sending teams notification")
+	}
+	defer resp.Body.Close()
+
+	// Legacy connectors respond 200; workflow webhooks respond 202.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf(
+			"not-OK HTTP status code: %d, when sending teams notification",
+			resp.StatusCode)
+	}
+	return nil
+}
+
+func (t teamsNotifier) formatMessage() teamsMessage {
+	a := t.alert
+
+	var stateText string
+	if a.OldState != a.NewState {
+		stateText = fmt.Sprintf("State change: %s->%s", a.OldState, a.NewState)
+	} else {
+		stateText = fmt.Sprintf("Has been %s since: %s",
+			a.NewState, a.EnteredState.UTC().Format(timeFormat))
+	}
+
+	facts := []teamsFact{
+		{Title: "State", Value: a.NewState.String()},
+		{Title: "Recorded", Value: a.Recorded.UTC().Format(timeFormat)},
+	}
+	if a.NewState != state.Normal {
+		facts = append(facts, teamsFact{
+			Title: "Last Normal",
+			Value: a.LastNormal.UTC().Format(timeFormat),
+		})
+	}
+
+	body := []teamsElement{
+		{
+			Type:  "Container",
+			Style: teamsStateStyles[a.NewState],
+			Bleed: true,
+			Items: []teamsElement{
+				{
+					Type:   "TextBlock",
+					Text:   fmt.Sprintf("%s/%s", a.MonitorName, a.SubprobeName),
+					Size:   "large",
+					Weight: "bolder",
+					Wrap:   true,
+				},
+				{
+					Type:   "TextBlock",
+					Text:   stateText,
+					Weight: "bolder",
+					Color:  teamsStateColors[a.NewState],
+					Wrap:   true,
+				},
+			},
+		},
+	}
+	if flapText := a.FlapText(); flapText != "" {
+		body = append(body, teamsElement{Type: "TextBlock", Text: flapText, Wrap: true})
+	}
+	body = append(body, teamsElement{Type: "FactSet", Facts: facts})
+
+	return teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: teamsCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body:    body,
+					Actions: teamsActions(a),
+					MSTeams: teamsCardLayout{Width: "Full"},
+				},
+			},
+		},
+	}
+}
+
+func teamsActions(a *Alert) []teamsAction {
+	actions := []teamsAction{
+		{
+			Type:  "Action.OpenUrl",
+			Title: "View subprobe",
+			URL: fmt.Sprintf("%s/monitors/%d/subprobes/%d",
+				a.Host, a.MonitorID, a.SubprobeID),
+		},
+	}
+	if a.NewState != state.Normal {
+		actions = append(actions, teamsAction{
+			Type:  "Action.OpenUrl",
+			Title: "Acknowledge",
+			URL:   a.AckURL(),
+		})
+	}
+	return append(actions, teamsAction{
+		Type:  "Action.OpenUrl",
+		Title: "Silence 1h",
+		URL:   a.SilenceURL(),
+	})
+}
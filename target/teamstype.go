@@ -0,0 +1,56 @@
+package target
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+)
+
+type teamsType struct{}
+
+func init() {
+	registerTargetType(teamsType{})
+}
+
+func (teamsType) ID() db.TargetType {
+	return 5
+}
+
+func (teamsType) New(config types.JSONText) (Target, error) {
+	return newTeams(config)
+}
+
+func (teamsType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		MaxAttempts:    8,
+	}
+}
+
+// Alert posts a card to each distinct webhook among the targets in toAlert.
+func (teamsType) Alert(
+	Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	webhooks := make(map[string][]db.TriggerID)
+	for id, target := range toAlert {
+		target := target.(*Teams)
+		webhooks[target.WebhookURL] = append(webhooks[target.WebhookURL], id)
+	}
+
+	notifier := teamsNotifier{alert: a}
+
+	var errs []ErrorAndTriggerIDs
+	for webhookURL, ids := range webhooks {
+		err := notifier.send(webhookURL)
+		if err != nil {
+			errs = append(errs, ErrorAndTriggerIDs{
+				Err: errors.Trace(err),
+				IDs: ids,
+			})
+		}
+	}
+	return errs
+}
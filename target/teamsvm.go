@@ -0,0 +1,83 @@
+package target
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/yext/revere/db"
+)
+
+type TeamsType struct{}
+
+type TeamsTarget struct {
+	TeamsType
+	WebhookURL string
+}
+
+func init() {
+	addType(TeamsType{})
+}
+
+func (TeamsType) Id() db.TargetType {
+	return 5
+}
+
+func (TeamsType) Name() string {
+	return "Microsoft Teams"
+}
+
+func (TeamsType) loadFromParams(target string) (VM, error) {
+	var t TeamsTarget
+	err := json.Unmarshal([]byte(target), &t)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (TeamsType) loadFromDb(encodedTarget string) (VM, error) {
+	var t TeamsDBModel
+	err := json.Unmarshal([]byte(encodedTarget), &t)
+	if err != nil {
+		return nil, err
+	}
+
+	return TeamsTarget{
+		WebhookURL: t.WebhookURL,
+	}, nil
+}
+
+func (TeamsType) blank() VM {
+	return TeamsTarget{}
+}
+
+func (TeamsType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "teams-edit.html",
+		"view": "teams-view.html",
+	}
+}
+
+func (TeamsType) Scripts() map[string][]string {
+	return map[string][]string{}
+}
+
+func (tt TeamsTarget) Serialize() (string, error) {
+	ttDB := TeamsDBModel{
+		WebhookURL: tt.WebhookURL,
+	}
+
+	ttDBJSON, err := json.Marshal(ttDB)
+	return string(ttDBJSON), err
+}
+
+func (TeamsTarget) Type() VMType {
+	return TeamsType{}
+}
+
+func (tt TeamsTarget) Validate() (errs []string) {
+	if u, err := url.Parse(tt.WebhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = append(errs, "Webhook URL is required and must be an https URL.")
+	}
+	return
+}
@@ -1,6 +1,8 @@
 package target
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx/types"
 
 	"github.com/yext/revere/db"
@@ -13,6 +15,10 @@ type Type interface {
 	// New returns a new instance of a target of this type.
 	New(config types.JSONText) (Target, error)
 
+	// Retries returns how alerts that failed to send to targets of this
+	// type are retried.
+	Retries() RetryPolicy
+
 	// Alert sends alert a to the targets in toAlert.
 	//
 	// Targets of this type that are also applicable to the alerting
@@ -26,7 +32,64 @@ type Type interface {
 	Alert(Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs
 }
 
+// GroupAlerter is implemented by target types that can send several alerts in
+// one message, such as when alerts are grouped or sent as a digest. Alerts for
+// types that do not implement it are sent one at a time.
+type GroupAlerter interface {
+	Type
+
+	// AlertGroup sends alerts to the targets in toAlert in one message,
+	// returning errors like Alert.
+	AlertGroup(Db *db.DB, alerts []*Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs
+}
+
+// Resolver is implemented by target types that open something for an alerting
+// subprobe, such as an incident, which has to be closed once the subprobe
+// recovers. Alerts it resolves are sent to Alert even if none of the type's
+// triggers alert on them, in which case every target is inactive.
+type Resolver interface {
+	Type
+
+	// Resolves returns whether a has to be sent to close what earlier
+	// alerts opened.
+	Resolves(a *Alert) bool
+}
+
+// ErrorAndTriggerIDs is a failure sending to the targets of the triggers in
+// IDs. A failure with no IDs is one sending to inactive targets, such as
+// resolving a PagerDuty incident; it is retried along with them.
 type ErrorAndTriggerIDs struct {
 	Err error
 	IDs []db.TriggerID
 }
+
+// triggerIDs returns the trigger IDs of the targets in toAlert.
+func triggerIDs(toAlert map[db.TriggerID]Target) []db.TriggerID {
+	ids := make([]db.TriggerID, 0, len(toAlert))
+	for id := range toAlert {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// RetryPolicy describes how failed alerts are retried: after InitialBackoff,
+// then after doubling waits of at most MaxBackoff, for MaxAttempts attempts in
+// total.
+type RetryPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxAttempts    int32
+}
+
+// Backoff returns how long to wait before the next attempt, once attempts
+// attempts have failed.
+func (p RetryPolicy) Backoff(attempts int32) time.Duration {
+	backoff := p.InitialBackoff
+	for i := int32(1); i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
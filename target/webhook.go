@@ -0,0 +1,219 @@
+package target
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+)
+
+const (
+	webhookSignatureHeader = "X-Revere-Signature"
+
+	defaultWebhookTimeout = 10 * time.Second
+	maxWebhookTimeout     = time.Minute
+	maxWebhookRetries     = 5
+)
+
+// maxWebhookSendTime bounds how long sending an alert to webhooks may take,
+// including retries, so that slow webhooks don't hold up the delivery worker
+// for long. Alerts that still fail are retried later from the alert queue.
+var maxWebhookSendTime = time.Minute
+
+// webhookRetryBackoff is how long to wait before the first retry of a failed
+// webhook request. It doubles for each later retry.
+var webhookRetryBackoff = time.Second
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"time": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+const defaultWebhookTemplate = `{
+  "monitor": {{json .MonitorName}},
+  "subprobe": {{json .SubprobeName}},
+  "oldState": {{json .OldState.String}},
+  "state": {{json .NewState.String}},
+  "recorded": {{json (time .Recorded)}},
+  "enteredState": {{json (time .EnteredState)}},
+  "lastNormal": {{json (time .LastNormal)}},
+  "url": {{json .SubprobeURL}},
+  "description": {{json .Description}},
+  "response": {{json .Response}},
+  "details": {{json .DetailsText}}
+}`
+
+type Webhook struct {
+	URL        string
+	Template   *template.Template
+	Headers    []WebhookHeaderDBModel
+	HMACSecret string
+	Timeout    time.Duration
+	Retries    int
+}
+
+// webhookTemplateData is what webhook templates are rendered from: the alert,
+// plus the alert's details text and subprobe URL, which templates cannot
+// compute for themselves.
+type webhookTemplateData struct {
+	*Alert
+	DetailsText string
+	SubprobeURL string
+	FlapText    string
+}
+
+func newWebhook(configJSON types.JSONText) (Target, error) {
+	var config WebhookDBModel
+	err := configJSON.Unmarshal(&config)
+	if err != nil {
+		return nil, errors.Maskf(err, "deserialize target config")
+	}
+
+	tmpl, err := parseWebhookTemplate(config.Template)
+	if err != nil {
+		return nil, errors.Mask(err)
+	}
+
+	timeout := time.Duration(config.TimeoutMilli) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &Webhook{
+		URL:        config.URL,
+		Template:   tmpl,
+		Headers:    config.Headers,
+		HMACSecret: config.HMACSecret,
+		Timeout:    timeout,
+		Retries:    config.Retries,
+	}, nil
+}
+
+func parseWebhookTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Maskf(err, "parse webhook template")
+	}
+	return tmpl, nil
+}
+
+func (Webhook) Type() Type {
+	return webhookType{}
+}
+
+// Recipients leaves out the URL's query, which may hold a token.
+func (w Webhook) Recipients() string {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return "Webhook"
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path)
+}
+
+// render renders the request body for a.
+func (w *Webhook) render(a *Alert) ([]byte, error) {
+	return renderWebhookTemplate(w.Template, a)
+}
+
+func renderWebhookTemplate(tmpl *template.Template, a *Alert) ([]byte, error) {
+	data := webhookTemplateData{
+		Alert: a,
+		SubprobeURL: fmt.Sprintf("%s/monitors/%d/subprobes/%d",
+			a.Host, a.MonitorID, a.SubprobeID),
+		FlapText: a.FlapText(),
+	}
+	if a.Details != nil {
+		data.DetailsText = a.Details.Text()
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, errors.Maskf(err, "render webhook template")
+	}
+	if !json.Valid(b.Bytes()) {
+		return nil, errors.New("webhook template did not render valid JSON")
+	}
+	return b.Bytes(), nil
+}
+
+// send posts body to the webhook, retrying failures up to the configured
+// number of times until ctx is done.
+func (w *Webhook) send(ctx context.Context, body []byte) error {
+	client := &http.Client{Timeout: w.Timeout}
+	backoff := webhookRetryBackoff
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		retryable, err = w.post(ctx, client, body)
+		if err == nil || !retryable || attempt >= w.Retries {
+			break
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Mask(err)
+		}
+		backoff *= 2
+	}
+	return errors.Mask(err)
+}
+
+// post makes a single request to the webhook, returning whether a failure may
+// succeed if retried.
+func (w *Webhook) post(ctx context.Context, client *http.Client, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Maskf(err, "make webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for _, h := range w.Headers {
+		req.Header.Set(h.Name, h.Value)
+	}
+	if w.HMACSecret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookBody(w.HMACSecret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, errors.Maskf(err, "send webhook request to %s", w.URL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		retryable = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, errors.Errorf(
+			"not-OK HTTP status code: %d, when sending webhook request to %s: %s",
+			resp.StatusCode, w.URL, respBody)
+	}
+	return false, nil
+}
+
+// signWebhookBody returns the hex-encoded HMAC-SHA256 of body under secret.
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
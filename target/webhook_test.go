@@ -0,0 +1,138 @@
+package target_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	. "github.com/yext/revere/target"
+)
+
+var (
+	webhookTargetType = WebhookType{}
+	webhookId         = 4
+	webhookName       = "Webhook"
+)
+
+func TestWebhookId(t *testing.T) {
+	if int(webhookTargetType.Id()) != webhookId {
+		t.Errorf("Expected webhook target type id: %d, got %d\n", webhookId, webhookTargetType.Id())
+	}
+}
+
+func TestWebhookName(t *testing.T) {
+	if webhookTargetType.Name() != webhookName {
+		t.Errorf("Expected webhook target type name: %s, got %s\n", webhookName, webhookTargetType.Name())
+	}
+}
+
+func TestBlankWebhookIsValidExceptURL(t *testing.T) {
+	blank, err := Blank(webhookTargetType.Id())
+	if err != nil {
+		t.Fatalf("Failed to load blank webhook target: %s\n", err.Error())
+	}
+
+	wt := blank.(WebhookTarget)
+	wt.URL = "https://example.com/hook"
+	if errs := wt.Validate(); errs != nil {
+		t.Errorf("Unexpected errors for blank webhook target: %v\n", errs)
+	}
+}
+
+func TestInvalidWebhook(t *testing.T) {
+	valid := WebhookTarget{
+		URL:      "https://example.com/hook",
+		Template: `{"monitor": {{json .MonitorName}}}`,
+		Headers:  "Authorization: Bearer abc\n\nX-Team: ops",
+		Timeout:  10,
+		Retries:  2,
+	}
+	if errs := valid.Validate(); errs != nil {
+		t.Fatalf("Unexpected errors for valid webhook target: %v\n", errs)
+	}
+
+	invalid := map[string]func(*WebhookTarget){
+		"url":             func(wt *WebhookTarget) { wt.URL = "example.com/hook" },
+		"template syntax": func(wt *WebhookTarget) { wt.Template = `{"monitor": {{json .MonitorName}` },
+		"template field":  func(wt *WebhookTarget) { wt.Template = `{"monitor": {{json .Nonexistent}}}` },
+		"non-JSON body":   func(wt *WebhookTarget) { wt.Template = `monitor {{.MonitorName}}` },
+		"header":          func(wt *WebhookTarget) { wt.Headers = "Authorization Bearer abc" },
+		"timeout":         func(wt *WebhookTarget) { wt.Timeout = 61 },
+		"retries":         func(wt *WebhookTarget) { wt.Retries = 6 },
+	}
+	for name, modify := range invalid {
+		wt := valid
+		modify(&wt)
+		if errs := wt.Validate(); errs == nil {
+			t.Errorf("Expected error for invalid webhook %s\n", name)
+		}
+	}
+}
+
+func TestWebhookAlert(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if sig := r.Header.Get("X-Revere-Signature"); sig != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("Unexpected signature: %s\n", sig)
+		}
+		if team := r.Header.Get("X-Team"); team != "ops" {
+			t.Errorf("Expected X-Team header: ops, got %s\n", team)
+		}
+
+		var payload map[string]string
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Invalid request body %s: %s\n", body, err.Error())
+		}
+		if payload["state"] != "ERROR" {
+			t.Errorf("Expected state: ERROR, got %s\n", payload["state"])
+		}
+
+		// Fail the first request to exercise retries.
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	wt := WebhookTarget{
+		URL:        server.URL,
+		Template:   `{"state": {{json .NewState.String}}}`,
+		Headers:    "X-Team: ops",
+		HMACSecret: "secret",
+		Timeout:    5,
+		Retries:    1,
+	}
+	config, err := wt.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize webhook target: %s\n", err.Error())
+	}
+
+	target, err := New(webhookTargetType.Id(), types.JSONText(config))
+	if err != nil {
+		t.Fatalf("Failed to create webhook target: %s\n", err.Error())
+	}
+
+	a := &Alert{NewState: state.Error, Recorded: time.Now()}
+	errs := target.Type().Alert(nil, a, map[db.TriggerID]Target{1: target}, nil)
+	if errs != nil {
+		t.Errorf("Unexpected errors sending webhook: %v\n", errs)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d\n", requests)
+	}
+}
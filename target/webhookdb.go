@@ -0,0 +1,27 @@
+package target
+
+// WebhookDBModel defines the JSON serialization format for saving webhook
+// targets' settings in the database.
+type WebhookDBModel struct {
+	URL string
+
+	// Template is a Go text/template rendering the request body from the
+	// alert. It must render JSON.
+	Template string
+
+	Headers []WebhookHeaderDBModel
+
+	// If HMACSecret is set, requests are signed with an HMAC-SHA256 of the
+	// body, sent in the X-Revere-Signature header.
+	HMACSecret string
+
+	TimeoutMilli int64
+	Retries      int
+}
+
+// WebhookHeaderDBModel defines the JSON serialization format for saving
+// webhook targets' extra request headers in the database.
+type WebhookHeaderDBModel struct {
+	Name  string
+	Value string
+}
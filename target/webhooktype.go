@@ -0,0 +1,59 @@
+package target
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+)
+
+type webhookType struct{}
+
+func init() {
+	registerTargetType(webhookType{})
+}
+
+func (webhookType) ID() db.TargetType {
+	return 4
+}
+
+func (webhookType) New(config types.JSONText) (Target, error) {
+	return newWebhook(config)
+}
+
+// Retries only applies once a webhook's own retries are used up.
+func (webhookType) Retries() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: time.Minute,
+		MaxBackoff:     30 * time.Minute,
+		MaxAttempts:    8,
+	}
+}
+
+// Alert sends a to each webhook in toAlert, taking at most maxWebhookSendTime
+// in all.
+func (webhookType) Alert(
+	Db *db.DB, a *Alert, toAlert map[db.TriggerID]Target, inactive []Target) []ErrorAndTriggerIDs {
+	ctx, cancel := context.WithTimeout(context.Background(), maxWebhookSendTime)
+	defer cancel()
+
+	var errs []ErrorAndTriggerIDs
+	for id, target := range toAlert {
+		webhook := target.(*Webhook)
+
+		body, err := webhook.render(a)
+		if err == nil {
+			err = webhook.send(ctx, body)
+		}
+		if err != nil {
+			errs = append(errs, ErrorAndTriggerIDs{
+				Err: errors.Trace(err),
+				IDs: []db.TriggerID{id},
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,40 @@
+package target
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+func TestWebhookAlertIsBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sendTime, backoff := maxWebhookSendTime, webhookRetryBackoff
+	maxWebhookSendTime, webhookRetryBackoff = 100*time.Millisecond, 40*time.Millisecond
+	defer func() { maxWebhookSendTime, webhookRetryBackoff = sendTime, backoff }()
+
+	tmpl, err := parseWebhookTemplate(defaultWebhookTemplate)
+	if err != nil {
+		t.Fatalf("Could not parse default template: %s", err)
+	}
+	toAlert := make(map[db.TriggerID]Target)
+	for id := db.TriggerID(1); id <= 3; id++ {
+		toAlert[id] = &Webhook{URL: server.URL, Template: tmpl, Timeout: time.Second, Retries: maxWebhookRetries}
+	}
+
+	start := time.Now()
+	errs := webhookType{}.Alert(nil, &Alert{NewState: state.Error, Recorded: start}, toAlert, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected sending to stop after %s, took %s", maxWebhookSendTime, elapsed)
+	}
+	if len(errs) != 3 {
+		t.Errorf("Expected all 3 webhooks to fail, got %v", errs)
+	}
+}
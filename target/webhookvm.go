@@ -0,0 +1,176 @@
+package target
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+type WebhookType struct{}
+
+type WebhookTarget struct {
+	WebhookType
+	URL      string
+	Template string
+
+	// Headers holds one "Name: Value" header per line.
+	Headers    string
+	HMACSecret string
+
+	// Timeout is in seconds.
+	Timeout int64
+	Retries int
+}
+
+func init() {
+	addType(WebhookType{})
+}
+
+func (WebhookType) Id() db.TargetType {
+	return 4
+}
+
+func (WebhookType) Name() string {
+	return "Webhook"
+}
+
+func (WebhookType) loadFromParams(target string) (VM, error) {
+	var w WebhookTarget
+	err := json.Unmarshal([]byte(target), &w)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (WebhookType) loadFromDb(encodedTarget string) (VM, error) {
+	var w WebhookDBModel
+	err := json.Unmarshal([]byte(encodedTarget), &w)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]string, len(w.Headers))
+	for i, h := range w.Headers {
+		headers[i] = h.Name + ": " + h.Value
+	}
+
+	return WebhookTarget{
+		URL:        w.URL,
+		Template:   w.Template,
+		Headers:    strings.Join(headers, "\n"),
+		HMACSecret: w.HMACSecret,
+		Timeout:    int64(time.Duration(w.TimeoutMilli) * time.Millisecond / time.Second),
+		Retries:    w.Retries,
+	}, nil
+}
+
+func (WebhookType) blank() VM {
+	return WebhookTarget{
+		Template: defaultWebhookTemplate,
+		Timeout:  int64(defaultWebhookTimeout / time.Second),
+	}
+}
+
+func (WebhookType) Templates() map[string]string {
+	return map[string]string{
+		"edit": "webhook-edit.html",
+		"view": "webhook-view.html",
+	}
+}
+
+func (WebhookType) Scripts() map[string][]string {
+	return map[string][]string{}
+}
+
+func (wt WebhookTarget) Serialize() (string, error) {
+	headers, _ := parseWebhookHeaders(wt.Headers)
+
+	wtDB := WebhookDBModel{
+		URL:          wt.URL,
+		Template:     wt.Template,
+		Headers:      headers,
+		HMACSecret:   wt.HMACSecret,
+		TimeoutMilli: int64(time.Duration(wt.Timeout) * time.Second / time.Millisecond),
+		Retries:      wt.Retries,
+	}
+
+	wtDBJSON, err := json.Marshal(wtDB)
+	return string(wtDBJSON), err
+}
+
+func (WebhookTarget) Type() VMType {
+	return WebhookType{}
+}
+
+func (wt WebhookTarget) Validate() (errs []string) {
+	if u, err := url.Parse(wt.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Sprintf("Invalid webhook URL: %s", wt.URL))
+	}
+
+	if tmpl, err := parseWebhookTemplate(wt.Template); err != nil {
+		errs = append(errs, fmt.Sprintf("Invalid webhook template: %s", err.Error()))
+	} else if _, err := renderWebhookTemplate(tmpl, sampleWebhookAlert()); err != nil {
+		errs = append(errs, fmt.Sprintf("Webhook template does not render valid JSON: %s", err.Error()))
+	}
+
+	if _, err := parseWebhookHeaders(wt.Headers); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if wt.Timeout < 0 || time.Duration(wt.Timeout)*time.Second > maxWebhookTimeout {
+		errs = append(errs, fmt.Sprintf("Webhook timeout must be between 0 and %d seconds.",
+			int64(maxWebhookTimeout/time.Second)))
+	}
+	if wt.Retries < 0 || wt.Retries > maxWebhookRetries {
+		errs = append(errs, fmt.Sprintf("Webhook retries must be between 0 and %d.", maxWebhookRetries))
+	}
+	return
+}
+
+// parseWebhookHeaders parses one "Name: Value" header per line, skipping
+// blank lines.
+func parseWebhookHeaders(text string) ([]WebhookHeaderDBModel, error) {
+	var headers []WebhookHeaderDBModel
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("Invalid webhook header: %s. Should be Name: Value.", line)
+		}
+		headers = append(headers, WebhookHeaderDBModel{
+			Name:  name,
+			Value: strings.TrimSpace(parts[1]),
+		})
+	}
+	return headers, nil
+}
+
+// sampleWebhookAlert returns an alert for checking that webhook templates
+// render.
+func sampleWebhookAlert() *Alert {
+	now := time.Now()
+	return &Alert{
+		MonitorID:    1,
+		MonitorName:  "Sample monitor",
+		SubprobeID:   1,
+		SubprobeName: "sample.subprobe",
+		Description:  "Sample description",
+		Response:     "Sample response",
+		OldState:     state.Normal,
+		NewState:     state.Error,
+		Recorded:     now,
+		EnteredState: now,
+		LastNormal:   now,
+	}
+}
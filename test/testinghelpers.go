@@ -14,6 +14,67 @@ const (
 		"auditPeriod": 10,
 		"auditPeriodType": "minute"
 	}`
+	DefaultPrometheusProbeJson = `{
+		"Query": "sum(rate(http_requests_total{code=~\"5..\"}[5m])) by (job)",
+		"SubprobeLabels": "job",
+		"Thresholds": {"Warning": 1, "Error": 5, "Critical": 10},
+		"AuditFunction": "max",
+		"CheckPeriod": 1,
+		"CheckPeriodType": "minute",
+		"Step": 15,
+		"StepType": "second",
+		"TriggerIf": ">",
+		"AuditPeriod": 10,
+		"AuditPeriodType": "minute"
+	}`
+	DefaultHTTPCheckProbeJson = `{
+		"URLs": "https://example.com/health\nhttp://example.org/status",
+		"CheckPeriod": 1,
+		"CheckPeriodType": "minute",
+		"Timeout": 10,
+		"TimeoutType": "second",
+		"ExpectedStatusCodes": "200,204",
+		"BodyMatch": "ok|healthy",
+		"BodyMatchType": "regexp",
+		"FailureState": "ERROR",
+		"LatencyThresholds": {"Warning": 500, "Error": 2000},
+		"CertExpiryThresholds": {"Warning": 14, "Critical": 3}
+	}`
+	DefaultReachabilityProbeJson = `{
+		"TCPTargets": "db1.example.com:3306\n10.0.0.5:6379",
+		"DNSTargets": "example.com 93.184.216.34\nwww.example.com example.com.",
+		"CheckPeriod": 30,
+		"CheckPeriodType": "second",
+		"Timeout": 5,
+		"TimeoutType": "second",
+		"LatencyThresholds": {"Warning": 100, "Error": 500},
+		"FailureThresholds": {"Warning": 1, "Error": 3, "Critical": 10}
+	}`
+	DefaultHeartbeatProbeJson = `{
+		"GracePeriod": 2,
+		"GracePeriodType": "hour",
+		"ExpectedSubprobes": "nightly-export\nhourly-sync"
+	}`
+	DefaultCompositeProbeJson = `{
+		"CheckPeriod": 1,
+		"CheckPeriodType": "minute",
+		"Rules": [
+			{
+				"Subprobe": "checkout",
+				"Inputs": "12\n13\n14",
+				"InputState": "ERROR",
+				"MinCount": 2,
+				"ResultState": "CRITICAL"
+			},
+			{
+				"Subprobe": "checkout",
+				"Inputs": "15 ^db-.*$",
+				"InputState": "CRITICAL",
+				"MinCount": 1,
+				"ResultState": "ERROR"
+			}
+		]
+	}`
 	DefaultTargetJson = `{
 		"Addresses": [
 			{"To":"test@ex.com", "ReplyTo":"test2@ex.com"}
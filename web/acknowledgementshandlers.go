@@ -0,0 +1,138 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func AcknowledgementsEdit(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		subprobe, status, err := loadAcknowledgementSubprobe(DB, p)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		ack := subprobe.Ack
+		if ack == nil {
+			ack = vm.BlankAcknowledgement(subprobe)
+			ack.AckedBy = auth.UserName(req)
+		}
+
+		renderable := renderables.NewAcknowledgementEdit(ack, subprobe)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve acknowledgement: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func AcknowledgementsSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		subprobe, status, err := loadAcknowledgementSubprobe(DB, p)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		var a *vm.Acknowledgement
+		body := new(bytes.Buffer)
+		_, err = body.ReadFrom(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save acknowledgement: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		err = json.Unmarshal(body.Bytes(), &a)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save acknowledgement: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		// Logged in users always acknowledge under their own name.
+		if u := auth.UserFrom(req); u != nil {
+			a.AckedBy = u.Name
+		}
+
+		errs := a.Validate(subprobe)
+		if len(errs) > 0 {
+			writeJsonResponse(w, "save acknowledgement", map[string]interface{}{"errors": errs})
+			return
+		}
+
+		err = a.Save(DB, subprobe)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save acknowledgement: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		logSave(a, body.Bytes(), req)
+
+		writeJsonResponse(w, "save acknowledgement", map[string]interface{}{
+			"redirect": fmt.Sprintf("/monitors/%d/subprobes/%d", subprobe.MonitorID, subprobe.SubprobeID),
+		})
+	}
+}
+
+func AcknowledgementsDelete(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		subprobe, status, err := loadAcknowledgementSubprobe(DB, p)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		err = vm.DeleteAcknowledgement(DB, subprobe.SubprobeID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to remove acknowledgement: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		writeJsonResponse(w, "remove acknowledgement", map[string]interface{}{
+			"redirect": fmt.Sprintf("/monitors/%d/subprobes/%d", subprobe.MonitorID, subprobe.SubprobeID),
+		})
+	}
+}
+
+// loadAcknowledgementSubprobe loads the subprobe named by the monitor and
+// subprobe IDs in p. On failure, it also returns the HTTP status to respond
+// with.
+func loadAcknowledgementSubprobe(DB *db.DB, p httprouter.Params) (*vm.Subprobe, int, error) {
+	mId, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		return nil, http.StatusNotFound, errors.Errorf("Monitor not found: %s", p.ByName("id"))
+	}
+
+	id, err := strconv.Atoi(p.ByName("subprobeId"))
+	if err != nil {
+		return nil, http.StatusNotFound, errors.Errorf("Subprobe not found: %s", p.ByName("subprobeId"))
+	}
+
+	subprobe, err := vm.NewSubprobe(DB, db.SubprobeID(id))
+	if err != nil {
+		return nil, http.StatusInternalServerError,
+			errors.Errorf("Unable to retrieve subprobe: %s", err.Error())
+	}
+
+	if subprobe.MonitorID != db.MonitorID(mId) {
+		return nil, http.StatusNotFound,
+			errors.Errorf("Subprobe %d does not exist for monitor: %d", id, mId)
+	}
+
+	return subprobe, http.StatusOK, nil
+}
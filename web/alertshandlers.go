@@ -0,0 +1,59 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func AlertsIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		filter := new(vm.AlertFilter)
+		err := filter.SetHtmlParams(req.URL.Query())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to filter alerts: %s", err.Error()),
+				http.StatusBadRequest)
+			return
+		}
+
+		alerts, err := vm.FilteredAlerts(DB, filter)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alerts: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		var (
+			monitors []*vm.Monitor
+			labels   []*vm.Label
+		)
+		err = DB.Tx(func(tx *db.Tx) error {
+			var err error
+			monitors, err = vm.AllMonitors(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			labels, err = vm.AllLabels(tx)
+			return errors.Trace(err)
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alerts: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewAlertsIndex(alerts, filter, monitors, labels)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alerts: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Package api implements Revere's versioned JSON API, which allows monitors,
+labels, silences, resources and settings to be managed by scripts. Requests
+and responses are JSON. Saves go through the same validation as the web UI,
+and validation failures are returned as a list of errors with status 422.
+*/
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/web/vm"
+)
+
+// Prefix is the path all version 1 API routes are under.
+const Prefix = "/api/v1"
+
+// errorResponse is the body of every unsuccessful response.
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to write response: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...string) {
+	body, err := json.Marshal(errorResponse{Errors: errs})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// readJSON decodes the request body into v, returning the raw body for
+// logging.
+func readJSON(req *http.Request, v interface{}) ([]byte, error) {
+	body := new(bytes.Buffer)
+	_, err := body.ReadFrom(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body.Bytes(), json.Unmarshal(body.Bytes(), v)
+}
+
+// parseID parses the ID in the named path parameter. IDs must be positive.
+func parseID(p httprouter.Params, name string) (int, bool) {
+	id, err := strconv.Atoi(p.ByName(name))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// redactSecrets returns whether the secrets of targets, such as PagerDuty keys,
+// should be redacted from the response to req. Only editors and admins, who
+// can change them, see them, as does everyone when authentication is
+// disabled, since everyone can change them then.
+func redactSecrets(req *http.Request) bool {
+	u := auth.UserFrom(req)
+	return u != nil && u.Role < auth.Editor
+}
+
+// logSave logs a save of c, along with who made it.
+func logSave(c vm.NamedComponent, body []byte, req *http.Request) {
+	log.WithFields(log.Fields{
+		"Component": c.ComponentName(),
+		"ID":        c.Id(),
+		"Method":    req.Method,
+		"URL":       req.URL.String(),
+		"User":      auth.UserName(req),
+	}).Info(string(body))
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/web/vm"
+)
+
+// Label is a label with its triggers and the monitors it is applied to.
+type Label struct {
+	LabelID     db.LabelID
+	Name        string
+	Description string
+	Triggers    []*Trigger
+	Monitors    []*LabelMonitor
+}
+
+// LabelMonitor applies a label to the subprobes of a monitor matching
+// Subprobes. Name is read-only.
+type LabelMonitor struct {
+	MonitorID db.MonitorID
+	Name      string
+	Subprobes string
+}
+
+// newLabel converts l for a response, with the secrets of its triggers'
+// targets redacted if redact is set.
+func newLabel(l *vm.Label, redact bool) (*Label, error) {
+	label := &Label{
+		LabelID:     l.LabelID,
+		Name:        l.Name,
+		Description: l.Description,
+		Triggers:    make([]*Trigger, len(l.Triggers)),
+		Monitors:    make([]*LabelMonitor, len(l.Monitors)),
+	}
+
+	for i, lt := range l.Triggers {
+		t, err := newTrigger(lt.Trigger, redact)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		label.Triggers[i] = t
+	}
+
+	for i, lm := range l.Monitors {
+		label.Monitors[i] = &LabelMonitor{
+			MonitorID: lm.Monitor.MonitorID,
+			Name:      lm.Monitor.Name,
+			Subprobes: lm.Subprobes,
+		}
+	}
+
+	return label, nil
+}
+
+// toVM converts l to a label that can be validated and saved. old is the label
+// being replaced, or nil if l is new. Triggers and monitors of old that are
+// not in l are removed.
+func (l *Label) toVM(old *vm.Label) (*vm.Label, []string) {
+	label := &vm.Label{
+		Name:        l.Name,
+		Description: l.Description,
+	}
+
+	oldTriggers := make(map[db.TriggerID]*vm.LabelTrigger)
+	oldMonitors := make(map[db.MonitorID]*vm.LabelMonitor)
+	if old != nil {
+		label.LabelID = old.LabelID
+
+		for _, lt := range old.Triggers {
+			oldTriggers[lt.Trigger.TriggerID] = lt
+		}
+		for _, lm := range old.Monitors {
+			oldMonitors[lm.Monitor.MonitorID] = lm
+		}
+	}
+
+	var errs []string
+	for _, lt := range l.Triggers {
+		var oldTrigger *vm.Trigger
+		if lt.TriggerID != 0 {
+			if old, ok := oldTriggers[lt.TriggerID]; ok {
+				oldTrigger = old.Trigger
+			} else {
+				errs = append(errs, fmt.Sprintf("Trigger %d does not belong to this label.", lt.TriggerID))
+			}
+			delete(oldTriggers, lt.TriggerID)
+		}
+		t, tErrs := lt.toVM(oldTrigger)
+		errs = append(errs, tErrs...)
+		label.Triggers = append(label.Triggers, &vm.LabelTrigger{
+			Trigger: t,
+			LabelID: label.LabelID,
+		})
+	}
+
+	seenMonitors := make(map[db.MonitorID]bool)
+	for _, lm := range l.Monitors {
+		if seenMonitors[lm.MonitorID] {
+			errs = append(errs, fmt.Sprintf("Monitor %d is labeled more than once.", lm.MonitorID))
+			continue
+		}
+		seenMonitors[lm.MonitorID] = true
+
+		_, attached := oldMonitors[lm.MonitorID]
+		delete(oldMonitors, lm.MonitorID)
+		label.Monitors = append(label.Monitors, &vm.LabelMonitor{
+			Monitor:   &vm.Monitor{MonitorID: lm.MonitorID},
+			LabelID:   label.LabelID,
+			Subprobes: lm.Subprobes,
+			Create:    !attached,
+		})
+	}
+
+	if old == nil {
+		return label, errs
+	}
+
+	for _, lt := range old.Triggers {
+		if _, ok := oldTriggers[lt.Trigger.TriggerID]; !ok {
+			continue
+		}
+		t, err := deletedTrigger(lt.Trigger)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		lt.Trigger = t
+		label.Triggers = append(label.Triggers, lt)
+	}
+
+	for _, lm := range old.Monitors {
+		if _, ok := oldMonitors[lm.Monitor.MonitorID]; !ok {
+			continue
+		}
+		lm.Delete = true
+		label.Monitors = append(label.Monitors, lm)
+	}
+
+	return label, errs
+}
+
+func loadLabel(DB *db.DB, id db.LabelID) (*vm.Label, error) {
+	var l *vm.Label
+	err := DB.Tx(func(tx *db.Tx) error {
+		var err error
+		l, err = vm.NewLabel(tx, id)
+		return errors.Trace(err)
+	})
+	return l, errors.Trace(err)
+}
+
+// writeLabel responds with l, redacting its secrets from those who may not see
+// them.
+func writeLabel(w http.ResponseWriter, req *http.Request, status int, l *vm.Label) {
+	label, err := newLabel(l, redactSecrets(req))
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to write label: %s", err.Error()))
+		return
+	}
+	writeJSON(w, status, label)
+}
+
+func LabelsIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		labels := []*Label{}
+		err := DB.Tx(func(tx *db.Tx) error {
+			ls, err := vm.AllLabels(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			for _, l := range ls {
+				l, err = vm.NewLabel(tx, l.LabelID)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				label, err := newLabel(l, redactSecrets(req))
+				if err != nil {
+					return errors.Trace(err)
+				}
+				labels = append(labels, label)
+			}
+			return nil
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve labels: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, labels)
+	}
+}
+
+func LabelsView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingLabel(db.LabelID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Label not found: %s", p.ByName("id")))
+			return
+		}
+
+		l, err := loadLabel(DB, db.LabelID(id))
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve label: %s", err.Error()))
+			return
+		}
+
+		writeLabel(w, req, http.StatusOK, l)
+	}
+}
+
+func LabelsCreate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var label Label
+		body, err := readJSON(req, &label)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid label: %s", err.Error()))
+			return
+		}
+
+		l, errs := label.toVM(nil)
+		errs = append(errs, l.Validate(DB)...)
+		if errs != nil {
+			writeErrors(w, http.StatusUnprocessableEntity, errs...)
+			return
+		}
+
+		saveLabel(DB, w, req, l, body, http.StatusCreated)
+	}
+}
+
+func LabelsUpdate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingLabel(db.LabelID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Label not found: %s", p.ByName("id")))
+			return
+		}
+
+		var label Label
+		body, err := readJSON(req, &label)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid label: %s", err.Error()))
+			return
+		}
+
+		var old *vm.Label
+		err = DB.Tx(func(tx *db.Tx) error {
+			var err error
+			old, err = vm.NewLabel(tx, db.LabelID(id))
+			return errors.Trace(err)
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve label: %s", err.Error()))
+			return
+		}
+
+		l, errs := label.toVM(old)
+		errs = append(errs, l.Validate(DB)...)
+		if errs != nil {
+			writeErrors(w, http.StatusUnprocessableEntity, errs...)
+			return
+		}
+
+		saveLabel(DB, w, req, l, body, http.StatusOK)
+	}
+}
+
+func saveLabel(DB *db.DB, w http.ResponseWriter, req *http.Request, l *vm.Label, body []byte, status int) {
+	err := DB.Tx(func(tx *db.Tx) error {
+		err := l.Save(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return history.Record(tx, l, auth.UserName(req))
+	})
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to save label: %s", err.Error()))
+		return
+	}
+	logSave(l, body, req)
+
+	l, err = loadLabel(DB, l.LabelID)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve label: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/labels/%d", Prefix, l.LabelID))
+	writeLabel(w, req, status, l)
+}
+
+// LabelsDelete deletes a label and its triggers, and removes it from the
+// monitors it was applied to.
+func LabelsDelete(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingLabel(db.LabelID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Label not found: %s", p.ByName("id")))
+			return
+		}
+
+		err := DB.Tx(func(tx *db.Tx) error {
+			err := history.RecordDeleted(tx, &vm.Label{LabelID: db.LabelID(id)}, auth.UserName(req))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return vm.DeleteLabel(tx, db.LabelID(id))
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to delete label: %s", err.Error()))
+			return
+		}
+		logSave(&vm.Label{LabelID: db.LabelID(id)}, nil, req)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,416 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/web/vm"
+)
+
+// Monitor is a monitor with its triggers and the labels applied to it. Probe
+// has the same fields as the probe's form in the web UI. Changed, Version and
+// Archived are read-only; if Version is set on an update, it must match the
+// monitor's current version.
+type Monitor struct {
+	MonitorID   db.MonitorID
+	Name        string
+	Owner       string
+	Description string
+	Response    string
+	ProbeType   db.ProbeType
+	Probe       json.RawMessage
+	Changed     time.Time
+	Version     int32
+	Archived    *time.Time
+
+	SustainReadings   int32
+	SustainPeriod     int64
+	SustainPeriodType string
+
+	FlapTransitions int32
+	FlapWindow      int64
+	FlapWindowType  string
+
+	Triggers []*MonitorTrigger
+	Labels   []*MonitorLabel
+}
+
+// MonitorTrigger is a trigger of a monitor, which applies to the subprobes
+// matching Subprobes.
+type MonitorTrigger struct {
+	Trigger
+	Subprobes string
+}
+
+// MonitorLabel applies a label to the subprobes of a monitor matching
+// Subprobes. Name is read-only.
+type MonitorLabel struct {
+	LabelID   db.LabelID
+	Name      string
+	Subprobes string
+}
+
+// newMonitor converts m for a response, with the secrets of its triggers'
+// targets redacted if redact is set.
+func newMonitor(m *vm.Monitor, redact bool) (*Monitor, error) {
+	probeJSON, err := json.Marshal(m.Probe)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize probe of monitor %d", m.MonitorID)
+	}
+
+	monitor := &Monitor{
+		MonitorID:   m.MonitorID,
+		Name:        m.Name,
+		Owner:       m.Owner,
+		Description: m.Description,
+		Response:    m.Response,
+		ProbeType:   m.ProbeType,
+		Probe:       json.RawMessage(probeJSON),
+		Changed:     m.Changed,
+		Version:     m.Version,
+		Archived:    m.Archived,
+
+		SustainReadings:   m.SustainReadings,
+		SustainPeriod:     m.SustainPeriod,
+		SustainPeriodType: m.SustainPeriodType,
+
+		FlapTransitions: m.FlapTransitions,
+		FlapWindow:      m.FlapWindow,
+		FlapWindowType:  m.FlapWindowType,
+
+		Triggers: make([]*MonitorTrigger, len(m.Triggers)),
+		Labels:   make([]*MonitorLabel, len(m.Labels)),
+	}
+
+	for i, mt := range m.Triggers {
+		t, err := newTrigger(mt.Trigger, redact)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		monitor.Triggers[i] = &MonitorTrigger{Trigger: *t, Subprobes: mt.Subprobes}
+	}
+
+	for i, ml := range m.Labels {
+		monitor.Labels[i] = &MonitorLabel{
+			LabelID:   ml.Label.LabelID,
+			Name:      ml.Label.Name,
+			Subprobes: ml.Subprobes,
+		}
+	}
+
+	return monitor, nil
+}
+
+// toVM converts m to a monitor that can be validated and saved. old is the
+// monitor being replaced, or nil if m is new. Triggers and labels of old that
+// are not in m are deleted.
+func (m *Monitor) toVM(old *vm.Monitor) (*vm.Monitor, []string) {
+	monitor := &vm.Monitor{
+		Name:        m.Name,
+		Owner:       m.Owner,
+		Description: m.Description,
+		Response:    m.Response,
+		ProbeType:   m.ProbeType,
+		ProbeParams: string(m.Probe),
+
+		SustainReadings:   m.SustainReadings,
+		SustainPeriod:     m.SustainPeriod,
+		SustainPeriodType: m.SustainPeriodType,
+
+		FlapTransitions: m.FlapTransitions,
+		FlapWindow:      m.FlapWindow,
+		FlapWindowType:  m.FlapWindowType,
+	}
+
+	oldTriggers := make(map[db.TriggerID]*vm.MonitorTrigger)
+	oldLabels := make(map[db.LabelID]*vm.MonitorLabel)
+	if old != nil {
+		monitor.MonitorID = old.MonitorID
+		monitor.Changed = old.Changed
+		monitor.Version = old.Version
+		monitor.Archived = old.Archived
+
+		for _, mt := range old.Triggers {
+			oldTriggers[mt.Trigger.TriggerID] = mt
+		}
+		for _, ml := range old.Labels {
+			oldLabels[ml.Label.LabelID] = ml
+		}
+	}
+
+	var errs []string
+	for _, mt := range m.Triggers {
+		var oldTrigger *vm.Trigger
+		if mt.TriggerID != 0 {
+			if old, ok := oldTriggers[mt.TriggerID]; ok {
+				oldTrigger = old.Trigger
+			} else {
+				errs = append(errs, fmt.Sprintf("Trigger %d does not belong to this monitor.", mt.TriggerID))
+			}
+			delete(oldTriggers, mt.TriggerID)
+		}
+		t, tErrs := mt.Trigger.toVM(oldTrigger)
+		errs = append(errs, tErrs...)
+		monitor.Triggers = append(monitor.Triggers, &vm.MonitorTrigger{
+			Trigger:   t,
+			MonitorID: monitor.MonitorID,
+			Subprobes: mt.Subprobes,
+		})
+	}
+
+	seenLabels := make(map[db.LabelID]bool)
+	for _, ml := range m.Labels {
+		if seenLabels[ml.LabelID] {
+			errs = append(errs, fmt.Sprintf("Label %d is applied more than once.", ml.LabelID))
+			continue
+		}
+		seenLabels[ml.LabelID] = true
+
+		_, attached := oldLabels[ml.LabelID]
+		delete(oldLabels, ml.LabelID)
+		monitor.Labels = append(monitor.Labels, &vm.MonitorLabel{
+			Label:     &vm.Label{LabelID: ml.LabelID},
+			MonitorID: monitor.MonitorID,
+			Subprobes: ml.Subprobes,
+			Create:    !attached,
+		})
+	}
+
+	if old == nil {
+		return monitor, errs
+	}
+
+	for _, mt := range old.Triggers {
+		if _, ok := oldTriggers[mt.Trigger.TriggerID]; !ok {
+			continue
+		}
+		t, err := deletedTrigger(mt.Trigger)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		mt.Trigger = t
+		monitor.Triggers = append(monitor.Triggers, mt)
+	}
+
+	for _, ml := range old.Labels {
+		if _, ok := oldLabels[ml.Label.LabelID]; !ok {
+			continue
+		}
+		ml.Delete = true
+		monitor.Labels = append(monitor.Labels, ml)
+	}
+
+	return monitor, errs
+}
+
+func loadMonitor(DB *db.DB, id db.MonitorID) (*vm.Monitor, error) {
+	var m *vm.Monitor
+	err := DB.Tx(func(tx *db.Tx) error {
+		var err error
+		m, err = vm.NewMonitor(tx, id)
+		return errors.Trace(err)
+	})
+	return m, errors.Trace(err)
+}
+
+// writeMonitor responds with m, redacting its secrets from those who may not
+// see them.
+func writeMonitor(w http.ResponseWriter, req *http.Request, status int, m *vm.Monitor) {
+	monitor, err := newMonitor(m, redactSecrets(req))
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to write monitor: %s", err.Error()))
+		return
+	}
+	writeJSON(w, status, monitor)
+}
+
+// MonitorsIndex lists every monitor, or only those with the label given by
+// the label query parameter.
+func MonitorsIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var labelID db.LabelID
+		if l := req.URL.Query().Get("label"); l != "" {
+			id, err := strconv.Atoi(l)
+			if err != nil || id <= 0 {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid label: %s", l))
+				return
+			}
+			labelID = db.LabelID(id)
+		}
+
+		monitors := []*Monitor{}
+		err := DB.Tx(func(tx *db.Tx) error {
+			var ms []*vm.Monitor
+			var err error
+			if labelID == 0 {
+				ms, err = vm.AllMonitors(tx)
+			} else {
+				ms, err = vm.AllMonitorsForLabel(tx, labelID)
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			for _, m := range ms {
+				m, err = vm.NewMonitor(tx, m.MonitorID)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				monitor, err := newMonitor(m, redactSecrets(req))
+				if err != nil {
+					return errors.Trace(err)
+				}
+				monitors = append(monitors, monitor)
+			}
+			return nil
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve monitors: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, monitors)
+	}
+}
+
+func MonitorsView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingMonitor(db.MonitorID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Monitor not found: %s", p.ByName("id")))
+			return
+		}
+
+		m, err := loadMonitor(DB, db.MonitorID(id))
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve monitor: %s", err.Error()))
+			return
+		}
+
+		writeMonitor(w, req, http.StatusOK, m)
+	}
+}
+
+func MonitorsCreate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var monitor Monitor
+		body, err := readJSON(req, &monitor)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid monitor: %s", err.Error()))
+			return
+		}
+
+		m, errs := monitor.toVM(nil)
+		errs = append(errs, m.Validate(DB)...)
+		if errs != nil {
+			writeErrors(w, http.StatusUnprocessableEntity, errs...)
+			return
+		}
+
+		saveMonitor(DB, w, req, m, body, http.StatusCreated)
+	}
+}
+
+func MonitorsUpdate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingMonitor(db.MonitorID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Monitor not found: %s", p.ByName("id")))
+			return
+		}
+
+		var monitor Monitor
+		body, err := readJSON(req, &monitor)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid monitor: %s", err.Error()))
+			return
+		}
+
+		var old *vm.Monitor
+		err = DB.Tx(func(tx *db.Tx) error {
+			var err error
+			old, err = vm.NewMonitor(tx, db.MonitorID(id))
+			return errors.Trace(err)
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve monitor: %s", err.Error()))
+			return
+		}
+
+		if monitor.Version != 0 && monitor.Version != old.Version {
+			writeErrors(w, http.StatusConflict,
+				fmt.Sprintf("Monitor has been changed since version %d; it is now at version %d.", monitor.Version, old.Version))
+			return
+		}
+
+		m, errs := monitor.toVM(old)
+		errs = append(errs, m.Validate(DB)...)
+		if errs != nil {
+			writeErrors(w, http.StatusUnprocessableEntity, errs...)
+			return
+		}
+
+		saveMonitor(DB, w, req, m, body, http.StatusOK)
+	}
+}
+
+func saveMonitor(DB *db.DB, w http.ResponseWriter, req *http.Request, m *vm.Monitor, body []byte, status int) {
+	err := DB.Tx(func(tx *db.Tx) error {
+		err := m.Save(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return history.Record(tx, m, auth.UserName(req))
+	})
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to save monitor: %s", err.Error()))
+		return
+	}
+	logSave(m, body, req)
+
+	m, err = loadMonitor(DB, m.MonitorID)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve monitor: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/monitors/%d", Prefix, m.MonitorID))
+	writeMonitor(w, req, status, m)
+}
+
+// MonitorsDelete archives a monitor. Archived monitors are no longer run, but
+// their history is kept.
+func MonitorsDelete(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingMonitor(db.MonitorID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Monitor not found: %s", p.ByName("id")))
+			return
+		}
+
+		err := DB.Tx(func(tx *db.Tx) error {
+			err := vm.ArchiveMonitor(tx, db.MonitorID(id))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return history.RecordArchived(tx, db.MonitorID(id), auth.UserName(req))
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to archive monitor: %s", err.Error()))
+			return
+		}
+		logSave(&vm.Monitor{MonitorID: db.MonitorID(id)}, nil, req)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/probe"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+	"github.com/yext/revere/test"
+	"github.com/yext/revere/web/vm"
+)
+
+func existingMonitor(t *testing.T) *vm.Monitor {
+	p, err := probe.LoadFromParams(probe.GraphiteThresholdType{}.Id(), test.DefaultProbeJson)
+	if err != nil {
+		t.Fatalf("Failed to load probe: %s\n", err.Error())
+	}
+
+	trigger := func(id db.TriggerID) *vm.MonitorTrigger {
+		return &vm.MonitorTrigger{
+			MonitorID: 7,
+			Subprobes: ".*",
+			Trigger: &vm.Trigger{
+				TriggerID:  id,
+				Level:      state.Warning,
+				Period:     1,
+				PeriodType: "hour",
+				TargetType: target.Default().Id(),
+				Target:     target.Default(),
+			},
+		}
+	}
+	label := func(id db.LabelID) *vm.MonitorLabel {
+		return &vm.MonitorLabel{
+			Label:     &vm.Label{LabelID: id, Name: "label"},
+			MonitorID: 7,
+			Subprobes: ".*",
+		}
+	}
+
+	return &vm.Monitor{
+		MonitorID: 7,
+		Name:      "disk",
+		ProbeType: p.Id(),
+		Probe:     p,
+		Version:   3,
+		Triggers:  []*vm.MonitorTrigger{trigger(1), trigger(2)},
+		Labels:    []*vm.MonitorLabel{label(3), label(4)},
+	}
+}
+
+func TestMonitorRoundTrip(t *testing.T) {
+	old := existingMonitor(t)
+	monitor, err := newMonitor(old, false)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+
+	// Clients send back what they receive, so make sure it survives JSON.
+	body, err := json.Marshal(monitor)
+	if err != nil {
+		t.Fatalf("Failed to serialize monitor: %s\n", err.Error())
+	}
+	var decoded Monitor
+	err = json.Unmarshal(body, &decoded)
+	if err != nil {
+		t.Fatalf("Failed to parse monitor: %s\n", err.Error())
+	}
+
+	m, errs := decoded.toVM(existingMonitor(t))
+	if errs != nil {
+		t.Fatalf("Unexpected errors: %v\n", errs)
+	}
+	if m.MonitorID != 7 || m.Version != 3 {
+		t.Errorf("Expected monitor 7 at version 3, got monitor %d at version %d\n", m.MonitorID, m.Version)
+	}
+	if _, err := probe.LoadFromParams(m.ProbeType, m.ProbeParams); err != nil {
+		t.Errorf("Failed to load probe from %s: %s\n", m.ProbeParams, err.Error())
+	}
+	for _, mt := range m.Triggers {
+		if mt.Trigger.Delete || mt.Trigger.LevelText != "Warning" {
+			t.Errorf("Expected trigger %d to be kept at Warning, got %+v\n", mt.Trigger.TriggerID, mt.Trigger)
+		}
+		if _, err := target.LoadFromParams(mt.Trigger.TargetType, mt.Trigger.TargetParams); err != nil {
+			t.Errorf("Failed to load target from %s: %s\n", mt.Trigger.TargetParams, err.Error())
+		}
+	}
+	for _, ml := range m.Labels {
+		if ml.Create || ml.Delete {
+			t.Errorf("Expected label %d to be kept, got %+v\n", ml.Label.LabelID, ml)
+		}
+	}
+}
+
+func TestMonitorMerge(t *testing.T) {
+	monitor, err := newMonitor(existingMonitor(t), false)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+
+	added := monitor.Triggers[0].Trigger
+	added.TriggerID = 0
+	added.Level = "CRITICAL"
+	monitor.Triggers = []*MonitorTrigger{
+		monitor.Triggers[0],
+		{Trigger: added, Subprobes: "db.*"},
+	}
+	monitor.Triggers[0].Level = "ERROR"
+	monitor.Labels = []*MonitorLabel{{LabelID: 4}, {LabelID: 5}}
+
+	m, errs := monitor.toVM(existingMonitor(t))
+	if errs != nil {
+		t.Fatalf("Unexpected errors: %v\n", errs)
+	}
+
+	expectedTriggers := map[db.TriggerID]struct {
+		level  string
+		delete bool
+	}{
+		1: {"ERROR", false},
+		0: {"CRITICAL", false},
+		2: {"Warning", true},
+	}
+	if len(m.Triggers) != len(expectedTriggers) {
+		t.Fatalf("Expected %d triggers, got %d\n", len(expectedTriggers), len(m.Triggers))
+	}
+	for _, mt := range m.Triggers {
+		expected := expectedTriggers[mt.Trigger.TriggerID]
+		if mt.Trigger.LevelText != expected.level || mt.Trigger.Delete != expected.delete {
+			t.Errorf("Expected trigger %d to be %+v, got %+v\n", mt.Trigger.TriggerID, expected, mt.Trigger)
+		}
+		if mt.Trigger.TargetParams == "" {
+			t.Errorf("Expected trigger %d to have target params\n", mt.Trigger.TriggerID)
+		}
+	}
+
+	expectedLabels := map[db.LabelID][2]bool{
+		3: {false, true},
+		4: {false, false},
+		5: {true, false},
+	}
+	if len(m.Labels) != len(expectedLabels) {
+		t.Fatalf("Expected %d labels, got %d\n", len(expectedLabels), len(m.Labels))
+	}
+	for _, ml := range m.Labels {
+		expected := expectedLabels[ml.Label.LabelID]
+		if ml.Create != expected[0] || ml.Delete != expected[1] {
+			t.Errorf("Expected label %d to have create, delete %v, got %v, %v\n",
+				ml.Label.LabelID, expected, ml.Create, ml.Delete)
+		}
+	}
+}
+
+func TestMonitorMergeErrors(t *testing.T) {
+	monitor, err := newMonitor(existingMonitor(t), false)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+
+	monitor.Triggers[0].TriggerID = 9
+	monitor.Triggers[1].Level = "Loud"
+	monitor.Labels = []*MonitorLabel{{LabelID: 4}, {LabelID: 4}}
+
+	_, errs := monitor.toVM(existingMonitor(t))
+	if len(errs) != 3 {
+		t.Errorf("Expected errors for the unknown trigger, level and repeated label, got %v\n", errs)
+	}
+
+	_, errs = monitor.toVM(nil)
+	if len(errs) < 1 {
+		t.Errorf("Expected an error for a trigger ID on a new monitor\n")
+	}
+}
+
+// secretMonitor is existingMonitor with triggers whose targets have secrets.
+func secretMonitor(t *testing.T) *vm.Monitor {
+	m := existingMonitor(t)
+	targets := []target.VM{
+		target.PagerDutyTarget{IntegrationKey: "0123456789abcdef0123456789abcdef"},
+		target.WebhookTarget{
+			URL:        "https://example.com/hook",
+			Headers:    "Authorization: Bearer s3cret-token",
+			HMACSecret: "s3cret-hmac",
+		},
+		target.TeamsTarget{WebhookURL: "https://example.webhook.office.com/s3cret"},
+	}
+	base := *m.Triggers[0].Trigger
+	m.Triggers = nil
+	for i, tv := range targets {
+		trigger := base
+		trigger.TriggerID = db.TriggerID(i + 1)
+		trigger.TargetType = tv.Id()
+		trigger.Target = tv
+		m.Triggers = append(m.Triggers, &vm.MonitorTrigger{
+			MonitorID: m.MonitorID,
+			Subprobes: ".*",
+			Trigger:   &trigger,
+		})
+	}
+	return m
+}
+
+var secrets = []string{
+	"0123456789abcdef0123456789abcdef",
+	"s3cret-token",
+	"s3cret-hmac",
+	"https://example.webhook.office.com/s3cret",
+}
+
+func TestMonitorSecrets(t *testing.T) {
+	a, err := auth.New(auth.AuthJSONModel{
+		Mode:        auth.Proxy,
+		Roles:       map[string]string{"ed": "editor"},
+		DefaultRole: "viewer",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create auth: %s\n", err.Error())
+	}
+	h := a.Require(auth.Viewer)(func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		writeMonitor(w, req, http.StatusOK, secretMonitor(t))
+	})
+
+	for _, user := range []string{"vic", "ed"} {
+		req := httptest.NewRequest("GET", Prefix+"/monitors/7", nil)
+		req.Header.Set("X-Forwarded-User", user)
+		w := httptest.NewRecorder()
+		h(w, req, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for %s, got %d: %s\n", user, w.Code, w.Body.String())
+		}
+
+		for _, secret := range secrets {
+			shown := strings.Contains(w.Body.String(), secret)
+			if user == "vic" && shown {
+				t.Errorf("Expected %q to be redacted from the viewer, got %s\n", secret, w.Body.String())
+			}
+			if user == "ed" && !shown {
+				t.Errorf("Expected %q to be shown to the editor, got %s\n", secret, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestMonitorKeepsRedactedSecrets(t *testing.T) {
+	monitor, err := newMonitor(secretMonitor(t), true)
+	if err != nil {
+		t.Fatalf("Failed to convert monitor: %s\n", err.Error())
+	}
+	added := monitor.Triggers[0].Trigger
+	added.TriggerID = 0
+	monitor.Triggers = append(monitor.Triggers, &MonitorTrigger{Trigger: added})
+
+	m, errs := monitor.toVM(secretMonitor(t))
+	if len(errs) != 1 {
+		t.Errorf("Expected an error for the new trigger with a redacted key only, got %v\n", errs)
+	}
+
+	var params string
+	for _, mt := range m.Triggers[:3] {
+		params += mt.Trigger.TargetParams
+	}
+	for _, secret := range secrets {
+		if !strings.Contains(params, secret) {
+			t.Errorf("Expected %q to be kept, got %s\n", secret, params)
+		}
+	}
+}
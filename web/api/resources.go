@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/resource"
+	"github.com/yext/revere/web/vm"
+)
+
+// Resource is a resource, such as a Graphite server, that probes read from.
+// Resource has the same fields as the resource's form in the web UI.
+type Resource struct {
+	ResourceID   db.ResourceID
+	ResourceType db.ResourceType
+	Resource     json.RawMessage
+}
+
+func newResource(r *resource.VM) (*Resource, error) {
+	resourceJSON, err := json.Marshal(r.Resource)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize resource %d", r.ResourceID)
+	}
+
+	return &Resource{
+		ResourceID:   r.ResourceID,
+		ResourceType: r.ResourceType,
+		Resource:     json.RawMessage(resourceJSON),
+	}, nil
+}
+
+func (r *Resource) toVM() *resource.VM {
+	return &resource.VM{
+		ResourceID:     r.ResourceID,
+		ResourceType:   r.ResourceType,
+		ResourceParams: string(r.Resource),
+	}
+}
+
+// loadResource returns the resource with the given ID, or nil if there is
+// none.
+func loadResource(DB *db.DB, id db.ResourceID) (*Resource, error) {
+	rs, err := resource.All(DB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for _, r := range rs {
+		if r.ResourceID == id {
+			return newResource(r)
+		}
+	}
+	return nil, nil
+}
+
+func ResourcesIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		rs, err := resource.All(DB)
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve resources: %s", err.Error()))
+			return
+		}
+
+		resources := make([]*Resource, len(rs))
+		for i, r := range rs {
+			resources[i], err = newResource(r)
+			if err != nil {
+				writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve resources: %s", err.Error()))
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, resources)
+	}
+}
+
+func ResourcesView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingResource(db.ResourceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Resource not found: %s", p.ByName("id")))
+			return
+		}
+
+		r, err := loadResource(DB, db.ResourceID(id))
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve resource: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, r)
+	}
+}
+
+func ResourcesCreate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var r Resource
+		body, err := readJSON(req, &r)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid resource: %s", err.Error()))
+			return
+		}
+		r.ResourceID = 0
+
+		saveResource(DB, w, req, r.toVM(), body, http.StatusCreated)
+	}
+}
+
+func ResourcesUpdate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingResource(db.ResourceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Resource not found: %s", p.ByName("id")))
+			return
+		}
+
+		var r Resource
+		body, err := readJSON(req, &r)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid resource: %s", err.Error()))
+			return
+		}
+		r.ResourceID = db.ResourceID(id)
+
+		saveResource(DB, w, req, r.toVM(), body, http.StatusOK)
+	}
+}
+
+func saveResource(DB *db.DB, w http.ResponseWriter, req *http.Request, r *resource.VM, body []byte, status int) {
+	errs := r.Validate()
+	if errs != nil {
+		writeErrors(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	err := DB.Tx(func(tx *db.Tx) error {
+		return r.Save(tx)
+	})
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to save resource: %s", err.Error()))
+		return
+	}
+	logSave(r, body, req)
+
+	saved, err := loadResource(DB, r.ResourceID)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve resource: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/resources/%d", Prefix, r.ResourceID))
+	writeJSON(w, status, saved)
+}
+
+// ResourcesDelete deletes a resource, unless a monitor's probe reads from it.
+func ResourcesDelete(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingResource(db.ResourceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Resource not found: %s", p.ByName("id")))
+			return
+		}
+
+		var inUse bool
+		err := DB.Tx(func(tx *db.Tx) error {
+			monitors, err := vm.AllMonitors(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, m := range monitors {
+				if m.Probe.HasResource(db.ResourceID(id)) {
+					inUse = true
+					return nil
+				}
+			}
+			return errors.Trace(tx.DeleteResource(db.ResourceID(id)))
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to delete resource: %s", err.Error()))
+			return
+		}
+		if inUse {
+			writeErrors(w, http.StatusConflict, fmt.Sprintf("Can't delete a resource currently used by a monitor. ID: %d", id))
+			return
+		}
+		logSave(&resource.VM{ResourceID: db.ResourceID(id)}, nil, req)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/setting"
+)
+
+// Setting is a global setting, such as the outgoing email server. There is at
+// most one setting of each type, so settings are addressed by type. Setting
+// has the same fields as the setting's form in the web UI.
+type Setting struct {
+	SettingID   db.SettingID
+	SettingType db.SettingType
+	Setting     json.RawMessage
+}
+
+func newSetting(s *setting.VM) (*Setting, error) {
+	settingJSON, err := json.Marshal(s.Setting)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize setting of type %d", s.SettingType)
+	}
+
+	return &Setting{
+		SettingID:   s.SettingID,
+		SettingType: s.SettingType,
+		Setting:     json.RawMessage(settingJSON),
+	}, nil
+}
+
+// loadSetting returns the setting of the given type, which is blank if it has
+// not been saved yet, or nil if there is no such type.
+func loadSetting(DB *db.DB, settingType db.SettingType) (*setting.VM, error) {
+	ss, err := setting.All(DB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for _, s := range ss {
+		if s.SettingType == settingType {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// SettingsIndex lists the settings of every type, including those that have
+// not been saved yet.
+func SettingsIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		ss, err := setting.All(DB)
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve settings: %s", err.Error()))
+			return
+		}
+
+		settings := make([]*Setting, len(ss))
+		for i, s := range ss {
+			settings[i], err = newSetting(s)
+			if err != nil {
+				writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve settings: %s", err.Error()))
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
+
+// SettingsUpdate saves the setting of the type given in the path.
+func SettingsUpdate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		st, err := strconv.Atoi(p.ByName("settingType"))
+		var old *setting.VM
+		if err == nil {
+			old, err = loadSetting(DB, db.SettingType(st))
+			if err != nil {
+				writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve setting: %s", err.Error()))
+				return
+			}
+		}
+		if old == nil {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Setting type not found: %s", p.ByName("settingType")))
+			return
+		}
+
+		var update Setting
+		body, err := readJSON(req, &update)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid setting: %s", err.Error()))
+			return
+		}
+
+		s := &setting.VM{
+			SettingID:     old.SettingID,
+			SettingType:   old.SettingType,
+			SettingParams: string(update.Setting),
+		}
+		errs := s.Validate()
+		if errs != nil {
+			writeErrors(w, http.StatusUnprocessableEntity, errs...)
+			return
+		}
+
+		err = DB.Tx(func(tx *db.Tx) error {
+			err := s.Save(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return history.Record(tx, s, auth.UserName(req))
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to save setting: %s", err.Error()))
+			return
+		}
+		logSave(s, body, req)
+
+		saved, err := loadSetting(DB, s.SettingType)
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve setting: %s", err.Error()))
+			return
+		}
+		resp, err := newSetting(saved)
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve setting: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
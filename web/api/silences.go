@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/web/vm"
+)
+
+// SilencesIndex lists every silence. Silences are read and written in the same
+// form as the web UI uses, in which MonitorName is read-only.
+func SilencesIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var silences []*vm.Silence
+		err := DB.Tx(func(tx *db.Tx) error {
+			var err error
+			silences, err = vm.AllSilences(tx)
+			return errors.Trace(err)
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve silences: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, silences)
+	}
+}
+
+func SilencesView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingSilence(db.SilenceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Silence not found: %s", p.ByName("id")))
+			return
+		}
+
+		s, err := vm.NewSilence(DB, db.SilenceID(id))
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve silence: %s", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, s)
+	}
+}
+
+func SilencesCreate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var s vm.Silence
+		body, err := readJSON(req, &s)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid silence: %s", err.Error()))
+			return
+		}
+		s.SilenceID = 0
+
+		saveSilence(DB, w, req, &s, body, http.StatusCreated)
+	}
+}
+
+func SilencesUpdate(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingSilence(db.SilenceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Silence not found: %s", p.ByName("id")))
+			return
+		}
+
+		var s vm.Silence
+		body, err := readJSON(req, &s)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Invalid silence: %s", err.Error()))
+			return
+		}
+		s.SilenceID = db.SilenceID(id)
+
+		saveSilence(DB, w, req, &s, body, http.StatusOK)
+	}
+}
+
+func saveSilence(DB *db.DB, w http.ResponseWriter, req *http.Request, s *vm.Silence, body []byte, status int) {
+	var errs []string
+	if s.MonitorID != 0 && !DB.IsExistingMonitor(s.MonitorID) {
+		errs = append(errs, fmt.Sprintf("Invalid monitor: %d", s.MonitorID))
+	}
+	errs = append(errs, s.Validate(DB)...)
+	if errs != nil {
+		writeErrors(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	err := DB.Tx(func(tx *db.Tx) error {
+		err := s.Save(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return history.Record(tx, s, auth.UserName(req))
+	})
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to save silence: %s", err.Error()))
+		return
+	}
+	logSave(s, body, req)
+
+	saved, err := vm.NewSilence(DB, s.SilenceID)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to retrieve silence: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/silences/%d", Prefix, s.SilenceID))
+	writeJSON(w, status, saved)
+}
+
+func SilencesDelete(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, ok := parseID(p, "id")
+		if !ok || !DB.IsExistingSilence(db.SilenceID(id)) {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("Silence not found: %s", p.ByName("id")))
+			return
+		}
+
+		err := DB.Tx(func(tx *db.Tx) error {
+			err := history.RecordDeleted(tx, &vm.Silence{SilenceID: db.SilenceID(id)}, auth.UserName(req))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return vm.DeleteSilence(tx, db.SilenceID(id))
+		})
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("Unable to delete silence: %s", err.Error()))
+			return
+		}
+		logSave(&vm.Silence{SilenceID: db.SilenceID(id)}, nil, req)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+	"github.com/yext/revere/web/vm"
+)
+
+// Trigger is a trigger of a monitor or a label. Level is a state name, such as
+// "Warning" or "CRITICAL", and Target has the same fields as the target's form
+// in the web UI.
+type Trigger struct {
+	TriggerID       db.TriggerID
+	Level           string
+	Period          int32
+	PeriodType      string
+	TriggerOnExit   bool
+	GroupWindow     int64
+	GroupWindowType string
+	Digest          int64
+	DigestType      string
+	TargetType      db.TargetType
+	Target          json.RawMessage
+}
+
+// newTrigger converts t for a response, with its target's secrets redacted if
+// redact is set.
+func newTrigger(t *vm.Trigger, redact bool) (*Trigger, error) {
+	targetVM := t.Target
+	if redact {
+		targetVM = target.Redact(targetVM)
+	}
+	targetJSON, err := json.Marshal(targetVM)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize target of trigger %d", t.TriggerID)
+	}
+
+	return &Trigger{
+		TriggerID:       t.TriggerID,
+		Level:           t.Level.String(),
+		Period:          t.Period,
+		PeriodType:      t.PeriodType,
+		TriggerOnExit:   t.TriggerOnExit,
+		GroupWindow:     t.GroupWindow,
+		GroupWindowType: t.GroupWindowType,
+		Digest:          t.Digest,
+		DigestType:      t.DigestType,
+		TargetType:      t.TargetType,
+		Target:          json.RawMessage(targetJSON),
+	}, nil
+}
+
+// toVM converts t to a trigger that can be validated and saved. old is the
+// trigger being replaced, or nil if t is new; secrets of t's target sent back
+// redacted are kept from old's.
+func (t *Trigger) toVM(old *vm.Trigger) (*vm.Trigger, []string) {
+	var errs []string
+	level, err := state.FromString(t.Level)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Invalid level for trigger: %q", t.Level))
+	}
+
+	params := string(t.Target)
+	if targetVM, err := target.LoadFromParams(t.TargetType, params); err == nil {
+		// Validation reports targets that cannot be loaded.
+		var oldTarget target.VM
+		if old != nil {
+			oldTarget = old.Target
+		}
+		targetVM, ok := target.Unredact(targetVM, oldTarget)
+		if !ok && old == nil {
+			errs = append(errs, fmt.Sprintf("New %s trigger has redacted secrets.", targetVM.Name()))
+		} else if !ok {
+			errs = append(errs, fmt.Sprintf(
+				"Trigger %d has redacted secrets that its current %s target does not have to keep.",
+				t.TriggerID, old.Target.Name()))
+		}
+		if targetJSON, err := json.Marshal(targetVM); err == nil {
+			params = string(targetJSON)
+		}
+	}
+
+	return &vm.Trigger{
+		TriggerID:       t.TriggerID,
+		Level:           level,
+		LevelText:       t.Level,
+		Period:          t.Period,
+		PeriodType:      t.PeriodType,
+		TriggerOnExit:   t.TriggerOnExit,
+		GroupWindow:     t.GroupWindow,
+		GroupWindowType: t.GroupWindowType,
+		Digest:          t.Digest,
+		DigestType:      t.DigestType,
+		TargetType:      t.TargetType,
+		TargetParams:    params,
+	}, errs
+}
+
+// deletedTrigger marks an existing trigger for deletion, filling in the fields
+// that saving it expects from the web UI.
+func deletedTrigger(t *vm.Trigger) (*vm.Trigger, error) {
+	targetJSON, err := json.Marshal(t.Target)
+	if err != nil {
+		return nil, errors.Maskf(err, "serialize target of trigger %d", t.TriggerID)
+	}
+
+	t.LevelText = t.Level.String()
+	t.TargetParams = string(targetJSON)
+	t.Delete = true
+	return t, nil
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func AlertDeliveriesIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		deliveries, err := vm.AllAlertDeliveries(DB)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alert deliveries: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewAlertDeliveriesIndex(deliveries)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alert deliveries: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func AlertDeliveriesRetry(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Alert delivery not found: %s", p.ByName("id")),
+				http.StatusNotFound)
+			return
+		}
+
+		err = vm.RetryAlertDelivery(DB, db.AlertDeliveryID(id))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retry alert delivery: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func AlertDeliveriesDiscard(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Alert delivery not found: %s", p.ByName("id")),
+				http.StatusNotFound)
+			return
+		}
+
+		err = vm.DiscardAlertDelivery(DB, db.AlertDeliveryID(id))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to discard alert delivery: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
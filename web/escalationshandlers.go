@@ -0,0 +1,187 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/juju/errors"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func EscalationPoliciesIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var policies []*vm.EscalationPolicy
+		err := DB.Tx(func(tx *db.Tx) error {
+			var err error
+			policies, err = vm.AllEscalationPolicies(tx)
+			return errors.Trace(err)
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policies: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewEscalationPoliciesIndex(policies)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policies: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func EscalationPoliciesView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id := p.ByName("id")
+		if id == "new" {
+			http.Redirect(w, req, "/escalations/new/edit", http.StatusMovedPermanently)
+			return
+		}
+
+		policy, monitors, labels, err := loadEscalationPolicyViewModel(DB, id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		saveStatus, err := getFlash(w, req, "saveStatus")
+		if err != nil {
+			log.Errorf("Unable to load flash cookie for escalation policy: %s", err.Error())
+		}
+
+		renderable := renderables.NewEscalationPolicyView(policy, monitors, labels, saveStatus)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func EscalationPoliciesEdit(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id := p.ByName("id")
+		if id == "" {
+			http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			return
+		}
+
+		policy, monitors, labels, err := loadEscalationPolicyViewModel(DB, id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewEscalationPolicyEdit(policy, monitors, labels)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func EscalationPoliciesSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		var ep *vm.EscalationPolicy
+		body := new(bytes.Buffer)
+		_, err := body.ReadFrom(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		err = json.Unmarshal(body.Bytes(), &ep)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		errs := ep.Validate(DB)
+		if len(errs) > 0 {
+			writeJsonResponse(w, "save escalation policy", map[string]interface{}{"errors": errs})
+			return
+		}
+
+		var saveStatus string
+		if ep.IsCreate() {
+			saveStatus = "created"
+		} else {
+			saveStatus = "updated"
+		}
+
+		err = DB.Tx(func(tx *db.Tx) error {
+			return errors.Trace(ep.Save(tx))
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save escalation policy: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		logSave(ep, body.Bytes(), req)
+
+		setFlash(w, "saveStatus", []byte(saveStatus))
+
+		writeJsonResponse(w, "save escalation policy", map[string]interface{}{
+			"redirect": fmt.Sprintf("/escalations/%d", ep.EscalationPolicyID),
+		})
+	}
+}
+
+// loadEscalationPolicyViewModel loads a policy along with all monitors and
+// labels, which it may be attached to.
+func loadEscalationPolicyViewModel(DB *db.DB, unparsedId string) (*vm.EscalationPolicy, []*vm.Monitor, []*vm.Label, error) {
+	var id int
+	if unparsedId != "new" {
+		var err error
+		id, err = strconv.Atoi(unparsedId)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+	}
+
+	var (
+		policy   *vm.EscalationPolicy
+		monitors []*vm.Monitor
+		labels   []*vm.Label
+	)
+	err := DB.Tx(func(tx *db.Tx) error {
+		var err error
+		if unparsedId == "new" {
+			policy = vm.BlankEscalationPolicy()
+		} else {
+			policy, err = vm.NewEscalationPolicy(tx, db.EscalationPolicyID(id))
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+
+		monitors, err = vm.AllMonitors(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		labels, err = vm.AllLabels(tx)
+		return errors.Trace(err)
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+
+	return policy, monitors, labels, nil
+}
@@ -20,6 +20,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/probe"
 	"github.com/yext/revere/setting"
@@ -148,7 +149,9 @@ func decode(src string) ([]byte, error) {
 	return base64.URLEncoding.DecodeString(src)
 }
 
-func logSave(c vm.NamedComponent, body []byte, url string) {
+// logSave logs a save of c, along with who made it.
+func logSave(c vm.NamedComponent, body []byte, req *http.Request) {
+	url := req.URL.String()
 	b := new(bytes.Buffer)
 	err := json.Indent(b, body, "", "\t")
 	if err != nil {
@@ -163,10 +166,12 @@ func logSave(c vm.NamedComponent, body []byte, url string) {
 		"Component": c.ComponentName(),
 		"ID":        c.Id(),
 		"URL":       url,
+		"User":      auth.UserName(req),
 	}).Info(b.String())
 }
 
-func logSaveArray(c []vm.NamedComponent, body []byte, url string) {
+func logSaveArray(c []vm.NamedComponent, body []byte, req *http.Request) {
+	url := req.URL.String()
 	b := new(bytes.Buffer)
 	if len(c) > 0 {
 		first := c[0]
@@ -182,6 +187,7 @@ func logSaveArray(c []vm.NamedComponent, body []byte, url string) {
 		log.WithFields(log.Fields{
 			"Component": first.ComponentName(),
 			"URL":       url,
+			"User":      auth.UserName(req),
 		}).Info(b.String())
 	}
 }
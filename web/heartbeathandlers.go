@@ -0,0 +1,82 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/probe"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxSubprobeNameLen matches the width of the subprobe name columns.
+const maxSubprobeNameLen = 150
+
+func RecordHeartbeat(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, err := strconv.Atoi(p.ByName("monitor"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Monitor not found: %s", p.ByName("monitor")),
+				http.StatusNotFound)
+			return
+		}
+
+		subprobe := p.ByName("subprobe")
+		if subprobe == "" || len(subprobe) > maxSubprobeNameLen {
+			http.Error(w, fmt.Sprintf("Invalid subprobe: %s", subprobe),
+				http.StatusBadRequest)
+			return
+		}
+
+		monitor, err := DB.LoadMonitor(db.MonitorID(id))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to load monitor: %v", err),
+				http.StatusInternalServerError)
+			return
+		}
+		if monitor == nil || monitor.Archived != nil || monitor.ProbeType != (probe.HeartbeatType{}).Id() {
+			http.Error(w, fmt.Sprintf("Heartbeat monitor not found: %d", id),
+				http.StatusNotFound)
+			return
+		}
+
+		// Heartbeats do not log in, so only the subprobes the monitor
+		// names may check in.
+		var config probe.HeartbeatDBModel
+		err = monitor.Probe.Unmarshal(&config)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to load monitor: %v", err),
+				http.StatusInternalServerError)
+			return
+		}
+		accepted, err := config.Accepts(subprobe)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to load monitor: %v", err),
+				http.StatusInternalServerError)
+			return
+		}
+		if !accepted {
+			http.Error(w, fmt.Sprintf("Subprobe not accepted by monitor %d: %s", id, subprobe),
+				http.StatusNotFound)
+			return
+		}
+
+		h := db.Heartbeat{
+			MonitorID: monitor.MonitorID,
+			Subprobe:  subprobe,
+			Recorded:  time.Now().UTC(),
+		}
+		err = DB.RecordHeartbeat(h)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to record heartbeat: %v", err),
+				http.StatusInternalServerError)
+			return
+		}
+		probe.NotifyHeartbeat(h)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
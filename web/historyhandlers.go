@@ -0,0 +1,101 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func MonitorsVersion(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, version, ok := parseMonitorVersion(w, p)
+		if !ok {
+			return
+		}
+
+		renderMonitorVersion(DB, w, id, version, nil)
+	}
+}
+
+func MonitorsRestore(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id, version, ok := parseMonitorVersion(w, p)
+		if !ok {
+			return
+		}
+
+		errs, err := history.RestoreMonitor(DB, id, version, auth.UserName(req))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to restore monitor: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		if errs != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			renderMonitorVersion(DB, w, id, version, errs)
+			return
+		}
+
+		setFlash(w, "saveStatus", []byte("restored"))
+		http.Redirect(w, req, fmt.Sprintf("/monitors/%d", id), http.StatusSeeOther)
+	}
+}
+
+func parseMonitorVersion(w http.ResponseWriter, p httprouter.Params) (db.MonitorID, int32, bool) {
+	id, err := strconv.Atoi(p.ByName("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Monitor not found: %s", p.ByName("id")),
+			http.StatusNotFound)
+		return 0, 0, false
+	}
+
+	version, err := strconv.ParseInt(p.ByName("version"), 10, 32)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Version not found: %s", p.ByName("version")),
+			http.StatusNotFound)
+		return 0, 0, false
+	}
+
+	return db.MonitorID(id), int32(version), true
+}
+
+func renderMonitorVersion(DB *db.DB, w http.ResponseWriter, id db.MonitorID, version int32, errs []string) {
+	var (
+		monitor *vm.Monitor
+		change  *history.Change
+	)
+	err := DB.Tx(func(tx *db.Tx) (err error) {
+		monitor, err = vm.NewMonitor(tx, id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		change, err = history.MonitorChange(tx, id, version)
+		return errors.Trace(err)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to retrieve monitor version: %s", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+	if change == nil {
+		http.Error(w, fmt.Sprintf("Version %d of monitor %d not found", version, id),
+			http.StatusNotFound)
+		return
+	}
+
+	err = render(w, renderables.NewMonitorVersion(monitor, change, errs))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to retrieve monitor version: %s", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+}
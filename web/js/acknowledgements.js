@@ -0,0 +1,64 @@
+$(document).ready(function() {
+  acknowledgementsEdit.init();
+});
+
+var acknowledgementsEdit = function() {
+  var a = {};
+  var ackedByKey = 'revere.ackedBy';
+
+  a.init = function() {
+    initAckedBy();
+    initForm();
+    initUnack();
+  };
+
+  // Remembers who acknowledged last so people don't have to retype their name.
+  var initAckedBy = function() {
+    var $ackedBy = $('.js-acked-by');
+    if (!$ackedBy.val() && window.localStorage) {
+      $ackedBy.val(window.localStorage.getItem(ackedByKey) || '');
+    }
+  };
+
+  var initForm = function() {
+    $('#js-ack-form').submit(function(e) {
+      e.preventDefault();
+      var data = $(this).find(':input').serializeObject();
+      if (window.localStorage) {
+        window.localStorage.setItem(ackedByKey, data.AckedBy);
+      }
+      $.ajax({
+        method: 'POST',
+        url: window.location.pathname,
+        data: JSON.stringify(data),
+        contentType: 'application/json; charset=UTF-8'
+      }).success(function(d) {
+        if (d.errors) {
+          return revere.showErrors(d.errors);
+        }
+        window.location.replace(d.redirect);
+      }).fail(function(jqXHR) {
+        revere.showErrors([jqXHR.responseText]);
+      });
+    });
+  };
+
+  var initUnack = function() {
+    $('#js-unack').click(function(e) {
+      e.preventDefault();
+      $.ajax({
+        method: 'DELETE',
+        url: window.location.pathname
+      }).success(function(d) {
+        if (d.errors) {
+          return revere.showErrors(d.errors);
+        }
+        window.location.replace(d.redirect);
+      }).fail(function(jqXHR) {
+        revere.showErrors([jqXHR.responseText]);
+      });
+    });
+  };
+
+  return a;
+}();
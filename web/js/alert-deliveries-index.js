@@ -0,0 +1,30 @@
+$(document).ready(function() {
+  alertDeliveriesIndex.init();
+});
+
+var alertDeliveriesIndex = function() {
+  var adi = {};
+
+  adi.init = function() {
+    $('.js-retry-delivery').click(function() {
+      updateDelivery($(this), 'POST', '/retry');
+    });
+    $('.js-discard-delivery').click(function() {
+      updateDelivery($(this), 'DELETE', '');
+    });
+  };
+
+  var updateDelivery = function($button, method, path) {
+    var id = $button.closest('.js-alert-delivery').data('id');
+    $.ajax({
+      url: '/deliveries/' + id + path,
+      method: method
+    }).done(function() {
+      window.location.reload();
+    }).fail(function(xhr) {
+      revere.showErrors([xhr.responseText]);
+    });
+  };
+
+  return adi;
+}();
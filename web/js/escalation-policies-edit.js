@@ -0,0 +1,53 @@
+$(document).ready(function() {
+  escalationPoliciesEdit.init();
+});
+
+var escalationPoliciesEdit = function() {
+  var epe = {};
+
+  epe.init = function() {
+    escalationStepsEdit.init();
+    initForm();
+  };
+
+  var initForm = function() {
+    $('#js-escalation-policy-form').submit(function(e) {
+      e.preventDefault();
+      var $form = $(this);
+      var url = $form.attr('action');
+
+      data = $.extend(
+        getPolicyData(),
+        {'Steps': escalationStepsEdit.getData()},
+        {'MonitorIDs': getSelectedIds('.js-escalation-monitors')},
+        {'LabelIDs': getSelectedIds('.js-escalation-labels')}
+      );
+
+      $.ajax({
+        url: url,
+        method: 'POST',
+        data: JSON.stringify(data),
+        contentType: 'application/json; charset=UTF-8'
+      }).success(function(response) {
+        if (response.errors) {
+          return revere.showErrors(response.errors);
+        }
+        window.location.replace(response.redirect);
+      }).fail(function(jqXHR, textStatus, errorThrown) {
+        revere.showErrors([jqXHR.responseText || textStatus]);
+      });
+    });
+  };
+
+  var getPolicyData = function() {
+    return $('#js-escalation-policy-info').find(':input').serializeObject();
+  };
+
+  var getSelectedIds = function(selector) {
+    return $.map($(selector).val() || [], function(id) {
+      return parseInt(id);
+    });
+  };
+
+  return epe;
+}();
@@ -0,0 +1,25 @@
+var escalationStepsEdit = function() {
+  var ese = {};
+
+  ese.init = function() {
+    triggersEdit.init();
+  };
+
+  ese.getData = function() {
+    var data = [];
+    $.each($('.js-trigger').not(':first'), function() {
+      var trigger = triggerEdit.getData(this),
+        step = {
+          Delay: trigger.Delay,
+          DelayType: trigger.DelayType,
+          EscalationPolicyID: parseInt($('input[name=EscalationPolicyID]').val())
+        };
+      delete trigger.Delay;
+      delete trigger.DelayType;
+      data.push($.extend(step, {Trigger: trigger}));
+    });
+    return data;
+  };
+
+  return ese;
+}();
@@ -0,0 +1,42 @@
+$(document).ready(function() {
+  composite.init();
+});
+
+var composite = function() {
+  var c = {};
+
+  c.init = function() {
+    initRules();
+    addSerializeFn();
+  };
+
+  var initRules = function() {
+    var $baseRule = $('.js-new-composite-rule').first();
+
+    $('#js-add-composite-rule').click(function(e) {
+      e.preventDefault();
+      var $newRule = $baseRule.clone();
+      $newRule.removeClass('js-new-composite-rule hidden').addClass('js-composite-rule');
+      $newRule.insertBefore($baseRule);
+    });
+
+    $(document.body).on('click', '.js-remove-composite-rule', function(e) {
+      e.preventDefault();
+      $(this).parents('.js-composite-rule').remove();
+    });
+  };
+
+  var addSerializeFn = function() {
+    probes.addSerializeFn($('#js-composite-probe-type').val(), function(probe) {
+      var inputs = probe.find(':input').not(probe.find('.js-composite-rule :input, .js-new-composite-rule :input')).serializeObject(),
+        rules = [];
+      probe.find('.js-composite-rule').each(function() {
+        rules.push($(this).find(':input').serializeObject());
+      });
+
+      return JSON.stringify($.extend(inputs, {"Rules": rules}));
+    });
+  };
+
+  return c;
+}();
@@ -91,6 +91,10 @@ var graphitePreview = function() {
     } else {
         targetExpression = gtFields['Expression']
     }
+    if (gtFields['Mode'] === 'anomaly') {
+      // Anomaly thresholds are deviation multiples, not values to draw.
+      return [getDataTargetExpression(targetExpression, gtFields['TriggerIf'])];
+    }
     return [
       getDataTargetExpression(targetExpression, gtFields['TriggerIf']),
       getThresholdTargetExpression(gtFields['Warning'], 'warning', 'orange'),
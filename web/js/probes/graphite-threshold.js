@@ -7,20 +7,61 @@ var graphiteThreshold = function() {
 
   g.init = function() {
     addSerializeFn();
+    initModeToggles();
+    initThresholdOverrides();
+  };
+
+  var initThresholdOverrides = function() {
+    var $baseOverride = $('.js-new-threshold-override').first();
+
+    $('#js-add-threshold-override').click(function(e) {
+      e.preventDefault();
+      var $newOverride = $baseOverride.clone();
+      $newOverride.removeClass('js-new-threshold-override hidden').addClass('js-threshold-override');
+      $newOverride.insertBefore($baseOverride);
+    });
+
+    $(document.body).on('click', '.js-remove-threshold-override', function(e) {
+      e.preventDefault();
+      $(this).parents('.js-threshold-override').remove();
+    });
+  };
+
+  var serializeThresholds = function($inputs) {
+    return $inputs.filter(function() {
+      return $(this).val() != "";
+    }).serializeObject();
+  };
+
+  var initModeToggles = function() {
+    $('#js-graphite-mode').change(function() {
+      $('.js-anomaly-settings').toggleClass('hidden', $(this).val() !== 'anomaly');
+    });
+    $('#js-anomaly-baseline').change(function() {
+      $('.js-anomaly-shift').toggleClass('hidden', $(this).val() !== 'shift');
+      $('.js-anomaly-window').toggleClass('hidden', $(this).val() !== 'rolling');
+    });
   };
 
   var addSerializeFn = function() {
     probes.addSerializeFn($('#js-graphite-threshold-probe-type').val(), function(probe) {
-      var inputs = probe.find(':input:not(.js-threshold)').serializeObject();
-      probe.find(':input.js-threshold').each(function() {
-          if ($(this).val() == "") {
-              $(this).remove();
-          }
-      });
-      var thresholds = probe.find(':input.js-threshold').serializeObject(),
+      var $overrideInputs = probe.find('.js-threshold-override :input, .js-new-threshold-override :input'),
+        inputs = probe.find(':input:not(.js-threshold)').not($overrideInputs).serializeObject(),
+        thresholds = serializeThresholds(probe.find(':input.js-threshold')),
+        overrides = [],
         id = parseInt(probe.find('select[name="URL"] :selected').first().data('id'));
 
-      return JSON.stringify($.extend(inputs, {"Thresholds": thresholds, "ResourceID": id}));
+      probe.find('.js-threshold-override').each(function() {
+        var override = $(this).find(':input:not(.js-override-threshold)').serializeObject();
+        override['Thresholds'] = serializeThresholds($(this).find(':input.js-override-threshold'));
+        overrides.push(override);
+      });
+
+      return JSON.stringify($.extend(inputs, {
+        "Thresholds": thresholds,
+        "ThresholdOverrides": overrides,
+        "ResourceID": id
+      }));
     });
   };
 
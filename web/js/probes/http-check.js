@@ -0,0 +1,34 @@
+$(document).ready(function() {
+  httpCheck.init();
+});
+
+var httpCheck = function() {
+  var h = {};
+
+  h.init = function() {
+    addSerializeFn();
+  };
+
+  var thresholdClasses = ['js-latency-threshold', 'js-cert-expiry-threshold'];
+
+  var serializeThresholds = function(probe, cls) {
+    probe.find(':input.' + cls).each(function() {
+      if ($(this).val() == "") {
+        $(this).remove();
+      }
+    });
+    return probe.find(':input.' + cls).serializeObject();
+  };
+
+  var addSerializeFn = function() {
+    probes.addSerializeFn($('#js-http-check-probe-type').val(), function(probe) {
+      var inputs = probe.find(':input:not(.' + thresholdClasses.join(', .') + ')').serializeObject();
+      return JSON.stringify($.extend(inputs, {
+        "LatencyThresholds": serializeThresholds(probe, thresholdClasses[0]),
+        "CertExpiryThresholds": serializeThresholds(probe, thresholdClasses[1])
+      }));
+    });
+  };
+
+  return h;
+}();
@@ -0,0 +1,34 @@
+$(document).ready(function() {
+  reachability.init();
+});
+
+var reachability = function() {
+  var r = {};
+
+  r.init = function() {
+    addSerializeFn();
+  };
+
+  var thresholdClasses = ['js-latency-threshold', 'js-failure-threshold'];
+
+  var serializeThresholds = function(probe, cls) {
+    probe.find(':input.' + cls).each(function() {
+      if ($(this).val() == "") {
+        $(this).remove();
+      }
+    });
+    return probe.find(':input.' + cls).serializeObject();
+  };
+
+  var addSerializeFn = function() {
+    probes.addSerializeFn($('#js-reachability-probe-type').val(), function(probe) {
+      var inputs = probe.find(':input:not(.' + thresholdClasses.join(', .') + ')').serializeObject();
+      return JSON.stringify($.extend(inputs, {
+        "LatencyThresholds": serializeThresholds(probe, thresholdClasses[0]),
+        "FailureThresholds": serializeThresholds(probe, thresholdClasses[1])
+      }));
+    });
+  };
+
+  return r;
+}();
@@ -0,0 +1,105 @@
+$(document).ready(function() {
+  schedulesEdit.init();
+});
+
+var schedulesEdit = function() {
+  var se = {};
+
+  se.init = function() {
+    initLayers();
+    initOverrides();
+    initForm();
+  };
+
+  var initLayers = function() {
+    $('#js-add-layer').click(function(e) {
+      e.preventDefault();
+      $('.js-layer-template').children().clone().appendTo('#js-layers');
+    });
+
+    $(document.body).on('click', '.js-remove-layer', function(e) {
+      e.preventDefault();
+      $(this).parents('.js-layer').remove();
+    });
+
+    $(document.body).on('click', '.js-add-member', function(e) {
+      e.preventDefault();
+      $('.js-member-template').children().clone()
+        .appendTo($(this).parents('.js-layer').find('.js-members'));
+    });
+
+    $(document.body).on('click', '.js-remove-member', function(e) {
+      e.preventDefault();
+      // Overrides only have one person, so removing them removes the override.
+      var $override = $(this).parents('.js-override');
+      if ($override.length) {
+        $override.remove();
+      } else {
+        $(this).parents('.js-member').remove();
+      }
+    });
+  };
+
+  var initOverrides = function() {
+    $('#js-add-override').click(function(e) {
+      e.preventDefault();
+      $('.js-override-template').children().clone().appendTo('#js-overrides');
+    });
+  };
+
+  var initForm = function() {
+    $('#js-schedule-form').submit(function(e) {
+      e.preventDefault();
+      var $form = $(this);
+      var url = $form.attr('action');
+
+      data = $.extend(
+        $('#js-schedule-info').find(':input').serializeObject(),
+        {'Layers': getLayers()},
+        {'Overrides': getOverrides()}
+      );
+
+      $.ajax({
+        url: url,
+        method: 'POST',
+        data: JSON.stringify(data),
+        contentType: 'application/json; charset=UTF-8'
+      }).success(function(response) {
+        if (response.errors) {
+          return revere.showErrors(response.errors);
+        }
+        window.location.replace(response.redirect);
+      }).fail(function(jqXHR, textStatus, errorThrown) {
+        revere.showErrors([jqXHR.responseText || textStatus]);
+      });
+    });
+  };
+
+  var getLayers = function() {
+    return $('#js-layers .js-layer').map(function() {
+      var $layer = $(this),
+        layer = $layer.find('.js-layer-info :input').serializeObject();
+      layer.Start = toUtc(layer.Start);
+      layer.Members = $layer.find('.js-member').map(function() {
+        return $(this).find(':input').serializeObject();
+      }).get();
+      return layer;
+    }).get();
+  };
+
+  var getOverrides = function() {
+    return $('#js-overrides .js-override').map(function() {
+      var override = $(this).find(':input').serializeObject();
+      override.Start = toUtc(override.Start);
+      override.End = toUtc(override.End);
+      return override;
+    }).get();
+  };
+
+  // Schedule times are entered in UTC, so they only need a zone added.
+  var toUtc = function(datetime) {
+    return datetime ? datetime + ':00Z' : '0001-01-01T00:00:00Z';
+  };
+
+  return se;
+}();
@@ -0,0 +1,21 @@
+$(document).ready(function() {
+  settings.addSerializeFn(pagerDuty.getData);
+});
+
+
+var pagerDuty = function() {
+  var pd = {};
+
+  pd.getData = function() {
+    var data = [];
+    $.each($('.js-pagerduty'), function() {
+      var serialized = $(this).find(':input.required').serializeObject();
+      var json = $(this).find(':input.json').serializeObject();
+      $.extend(serialized, {'SettingParams': JSON.stringify(json)});
+      data.push(serialized);
+    });
+    return data;
+  };
+
+  return pd;
+}();
@@ -10,7 +10,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
 	"github.com/julienschmidt/httprouter"
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
 	"github.com/yext/revere/web/vm"
 	"github.com/yext/revere/web/vm/renderables"
 )
@@ -149,14 +151,17 @@ func LabelsSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p http
 
 		err = DB.Tx(func(tx *db.Tx) error {
 			err := l.Save(tx)
-			return errors.Trace(err)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return history.Record(tx, l, auth.UserName(req))
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Unable to save label: %s", err.Error()),
 				http.StatusInternalServerError)
 			return
 		}
-		logSave(l, body.Bytes(), req.URL.String())
+		logSave(l, body.Bytes(), req)
 
 		redirect, err := json.Marshal(map[string]string{"redirect": fmt.Sprintf("/labels/%d", l.LabelID)})
 		if err != nil {
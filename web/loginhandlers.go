@@ -0,0 +1,113 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/auth"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func Login(a *auth.Auth) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		next := localPath(req.FormValue("next"))
+		switch a.Mode() {
+		case auth.Local:
+			err := render(w, renderables.NewLogin(next, ""))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Unable to load login page: %s", err.Error()),
+					http.StatusInternalServerError)
+			}
+		case auth.OIDC:
+			err := a.StartOIDCLogin(w, req, next)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Unable to log in: %s", err.Error()),
+					http.StatusInternalServerError)
+			}
+		default:
+			// There is nothing to log in to.
+			http.Redirect(w, req, next, http.StatusFound)
+		}
+	}
+}
+
+func LoginSave(a *auth.Auth) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		if a.Mode() != auth.Local {
+			http.NotFound(w, req)
+			return
+		}
+
+		next := localPath(req.FormValue("next"))
+		u, err := a.Login(req.FormValue("Username"), req.FormValue("Password"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to log in: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		if u == nil {
+			log.WithField("User", req.FormValue("Username")).Warn("Failed login.")
+			w.WriteHeader(http.StatusUnauthorized)
+			err = render(w, renderables.NewLogin(next, "Wrong username or password."))
+			if err != nil {
+				log.WithError(err).Error("Unable to load login page.")
+			}
+			return
+		}
+
+		finishLogin(a, w, req, u, next)
+	}
+}
+
+func LoginCallback(a *auth.Auth) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		if a.Mode() != auth.OIDC {
+			http.NotFound(w, req)
+			return
+		}
+
+		u, next, err := a.FinishOIDCLogin(w, req)
+		if err != nil {
+			log.WithError(err).Warn("Failed login.")
+			http.Error(w, fmt.Sprintf("Unable to log in: %s", err.Error()), http.StatusUnauthorized)
+			return
+		}
+
+		finishLogin(a, w, req, u, localPath(next))
+	}
+}
+
+func Logout(a *auth.Auth) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		a.EndSession(w)
+		http.Redirect(w, req, "/", http.StatusFound)
+	}
+}
+
+func finishLogin(a *auth.Auth, w http.ResponseWriter, req *http.Request, u *auth.User, next string) {
+	err := a.StartSession(w, u)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to log in: %s", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"User": u.Name,
+		"Role": u.Role,
+	}).Info("Logged in.")
+	http.Redirect(w, req, next, http.StatusSeeOther)
+}
+
+// localPath returns next if it is a path on this server, so that logging in
+// cannot be used to redirect to other sites, or / otherwise.
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
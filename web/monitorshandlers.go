@@ -9,7 +9,9 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
 	"github.com/yext/revere/web/vm"
 	"github.com/yext/revere/web/vm/renderables"
 
@@ -78,9 +80,16 @@ func MonitorsView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p ht
 			return
 		}
 
-		var monitor *vm.Monitor
+		var (
+			monitor  *vm.Monitor
+			versions []*history.Version
+		)
 		err := DB.Tx(func(tx *db.Tx) (err error) {
 			monitor, err = loadMonitorViewModel(tx, id)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			versions, err = history.MonitorVersions(tx, monitor.MonitorID)
 			return errors.Trace(err)
 		})
 		if err != nil {
@@ -94,7 +103,7 @@ func MonitorsView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p ht
 			log.Errorf("Unable to load flash cookie for monitor: %s", err.Error())
 		}
 
-		renderable := renderables.NewMonitorView(monitor, saveStatus)
+		renderable := renderables.NewMonitorView(monitor, versions, saveStatus)
 		err = render(w, renderable)
 
 		if err != nil {
@@ -190,14 +199,18 @@ func MonitorsSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p ht
 		}
 
 		err = DB.Tx(func(tx *db.Tx) error {
-			return m.Save(tx)
+			err := m.Save(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return history.Record(tx, m, auth.UserName(req))
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Unable to save monitor: %s", err.Error()),
 				http.StatusInternalServerError)
 			return
 		}
-		logSave(m, body.Bytes(), req.URL.String())
+		logSave(m, body.Bytes(), req)
 
 		redirect, err := json.Marshal(map[string]string{"redirect": fmt.Sprintf("/monitors/%d", m.MonitorID)})
 		if err != nil {
@@ -100,7 +100,7 @@ func ResourcesSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ h
 		for i, r := range rs {
 			ri[i] = r
 		}
-		logSaveArray(ri, body.Bytes(), req.URL.String())
+		logSaveArray(ri, body.Bytes(), req)
 
 		setFlash(w, "saveStatus", []byte("updated"))
 	}
@@ -0,0 +1,170 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/juju/errors"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/web/vm"
+	"github.com/yext/revere/web/vm/renderables"
+)
+
+func SchedulesIndex(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		var schedules []*vm.Schedule
+		err := DB.Tx(func(tx *db.Tx) error {
+			var err error
+			schedules, err = vm.AllSchedules(tx)
+			return errors.Trace(err)
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedules: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewSchedulesIndex(schedules)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedules: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func SchedulesView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id := p.ByName("id")
+		if id == "new" {
+			http.Redirect(w, req, "/schedules/new/edit", http.StatusMovedPermanently)
+			return
+		}
+
+		schedule, err := loadScheduleViewModel(DB, id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		saveStatus, err := getFlash(w, req, "saveStatus")
+		if err != nil {
+			log.Errorf("Unable to load flash cookie for schedule: %s", err.Error())
+		}
+
+		renderable := renderables.NewScheduleView(schedule, saveStatus)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func SchedulesEdit(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		id := p.ByName("id")
+		if id == "" {
+			http.Error(w, "Schedule not found", http.StatusNotFound)
+			return
+		}
+
+		schedule, err := loadScheduleViewModel(DB, id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewScheduleEdit(schedule)
+		err = render(w, renderable)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func SchedulesSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		var s *vm.Schedule
+		body := new(bytes.Buffer)
+		_, err := body.ReadFrom(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		err = json.Unmarshal(body.Bytes(), &s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+
+		errs := s.Validate()
+		if !s.IsCreate() && !DB.IsExistingSchedule(s.ScheduleID) {
+			errs = append(errs, fmt.Sprintf("Invalid schedule: %d", s.ScheduleID))
+		}
+		if len(errs) > 0 {
+			writeJsonResponse(w, "save schedule", map[string]interface{}{"errors": errs})
+			return
+		}
+
+		var saveStatus string
+		if s.IsCreate() {
+			saveStatus = "created"
+		} else {
+			saveStatus = "updated"
+		}
+
+		err = DB.Tx(func(tx *db.Tx) error {
+			return errors.Trace(s.Save(tx))
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to save schedule: %s", err.Error()),
+				http.StatusInternalServerError)
+			return
+		}
+		logSave(s, body.Bytes(), req)
+
+		setFlash(w, "saveStatus", []byte(saveStatus))
+
+		writeJsonResponse(w, "save schedule", map[string]interface{}{
+			"redirect": fmt.Sprintf("/schedules/%d", s.ScheduleID),
+		})
+	}
+}
+
+func loadScheduleViewModel(DB *db.DB, unparsedId string) (*vm.Schedule, error) {
+	if unparsedId == "new" {
+		return vm.BlankSchedule(), nil
+	}
+
+	id, err := strconv.Atoi(unparsedId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var schedule *vm.Schedule
+	err = DB.Tx(func(tx *db.Tx) error {
+		var err error
+		schedule, err = vm.NewSchedule(tx, db.ScheduleID(id))
+		return errors.Trace(err)
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return schedule, nil
+}
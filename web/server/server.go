@@ -6,9 +6,12 @@ import (
 	"strconv"
 
 	"github.com/braintree/manners"
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/boxes"
 	"github.com/yext/revere/env"
+	"github.com/yext/revere/metrics"
 	"github.com/yext/revere/web"
+	"github.com/yext/revere/web/api"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/julienschmidt/httprouter"
@@ -28,36 +31,94 @@ func New(env *env.Env) *WebServer {
 	jsFiles := boxes.JS()
 	favicon := boxes.Favicon()
 
+	a := env.Auth
+	if !a.Enabled() {
+		log.Warn("Authentication is off, so anyone who can reach Revere can change it.")
+	}
+	viewer := a.Require(auth.Viewer)
+	editor := a.Require(auth.Editor)
+	admin := a.Require(auth.Admin)
+
 	router := httprouter.New()
-	router.GET("/", web.ActiveIssues(env.DB))
-	router.GET("/resources", web.ResourcesIndex(env.DB))
-	router.GET("/resources/probe/:probeType", web.LoadValidResources(env.DB))
-	router.POST("/resources", web.ResourcesSave(env.DB))
-	router.GET("/resourcetype/:id", web.LoadResourceTemplate(env.DB))
-	router.GET("/monitors", web.MonitorsIndex(env.DB))
-	router.GET("/monitors/:id", web.MonitorsView(env.DB))
-	router.GET("/monitors/:id/edit", web.MonitorsEdit(env.DB))
-	router.POST("/monitors/:id/edit", web.MonitorsSave(env.DB))
-	router.GET("/monitors/:id/subprobes", web.SubprobesIndex(env.DB))
-	router.GET("/monitors/:id/subprobes/:subprobeId", web.SubprobesView(env.DB))
-	router.DELETE("/monitors/:id/subprobes/:subprobeId/delete", web.DeleteSubprobe(env.DB));
-	router.GET("/monitors/:id/probe/edit/:probeType", web.LoadProbeTemplate(env.DB))
-	router.GET("/monitors/:id/target/edit/:targetType", web.LoadTargetTemplate)
-	router.GET("/silences", web.SilencesIndex(env.DB))
-	router.GET("/silences/:id", web.SilencesView(env.DB))
-	router.GET("/silences/:id/edit", web.SilencesEdit(env.DB))
-	router.POST("/silences/:id/edit", web.SilencesSave(env.DB))
-	router.GET("/labels", web.LabelsIndex(env.DB))
-	router.GET("/labels/:id", web.LabelsView(env.DB))
-	router.GET("/labels/:id/edit", web.LabelsEdit(env.DB))
-	router.POST("/labels/:id/edit", web.LabelsSave(env.DB))
-	router.GET("/settings", web.SettingsIndex(env.DB))
-	router.POST("/settings", web.SettingsSave(env.DB))
-	router.GET("/redirectToSilence", web.RedirectToSilence(env.DB))
+	router.GET("/login", web.Login(a))
+	router.POST("/login", web.LoginSave(a))
+	router.GET("/login/callback", web.LoginCallback(a))
+	router.GET("/logout", web.Logout(a))
+	router.GET("/", viewer(web.ActiveIssues(env.DB)))
+	router.GET("/resources", admin(web.ResourcesIndex(env.DB)))
+	router.GET("/resources/probe/:probeType", viewer(web.LoadValidResources(env.DB)))
+	router.POST("/resources", admin(web.ResourcesSave(env.DB)))
+	router.GET("/resourcetype/:id", viewer(web.LoadResourceTemplate(env.DB)))
+	router.GET("/monitors", viewer(web.MonitorsIndex(env.DB)))
+	router.GET("/monitors/:id", viewer(web.MonitorsView(env.DB)))
+	router.GET("/monitors/:id/edit", editor(web.MonitorsEdit(env.DB)))
+	router.POST("/monitors/:id/edit", editor(web.MonitorsSave(env.DB)))
+	router.GET("/monitors/:id/history/:version", viewer(web.MonitorsVersion(env.DB)))
+	router.POST("/monitors/:id/history/:version/restore", editor(web.MonitorsRestore(env.DB)))
+	router.GET("/monitors/:id/subprobes", viewer(web.SubprobesIndex(env.DB)))
+	router.GET("/monitors/:id/subprobes/:subprobeId", viewer(web.SubprobesView(env.DB)))
+	router.DELETE("/monitors/:id/subprobes/:subprobeId/delete", editor(web.DeleteSubprobe(env.DB)))
+	router.GET("/monitors/:id/subprobes/:subprobeId/ack", editor(web.AcknowledgementsEdit(env.DB)))
+	router.POST("/monitors/:id/subprobes/:subprobeId/ack", editor(web.AcknowledgementsSave(env.DB)))
+	router.DELETE("/monitors/:id/subprobes/:subprobeId/ack", editor(web.AcknowledgementsDelete(env.DB)))
+	router.GET("/monitors/:id/probe/edit/:probeType", viewer(web.LoadProbeTemplate(env.DB)))
+	router.GET("/monitors/:id/target/edit/:targetType", viewer(web.LoadTargetTemplate))
+	router.GET("/silences", viewer(web.SilencesIndex(env.DB)))
+	router.GET("/silences/:id", viewer(web.SilencesView(env.DB)))
+	router.GET("/silences/:id/edit", editor(web.SilencesEdit(env.DB)))
+	router.POST("/silences/:id/edit", editor(web.SilencesSave(env.DB)))
+	router.GET("/labels", viewer(web.LabelsIndex(env.DB)))
+	router.GET("/labels/:id", viewer(web.LabelsView(env.DB)))
+	router.GET("/labels/:id/edit", editor(web.LabelsEdit(env.DB)))
+	router.POST("/labels/:id/edit", editor(web.LabelsSave(env.DB)))
+	router.GET("/escalations", viewer(web.EscalationPoliciesIndex(env.DB)))
+	router.GET("/escalations/:id", viewer(web.EscalationPoliciesView(env.DB)))
+	router.GET("/escalations/:id/edit", editor(web.EscalationPoliciesEdit(env.DB)))
+	router.POST("/escalations/:id/edit", editor(web.EscalationPoliciesSave(env.DB)))
+	router.GET("/schedules", viewer(web.SchedulesIndex(env.DB)))
+	router.GET("/schedules/:id", viewer(web.SchedulesView(env.DB)))
+	router.GET("/schedules/:id/edit", editor(web.SchedulesEdit(env.DB)))
+	router.POST("/schedules/:id/edit", editor(web.SchedulesSave(env.DB)))
+	router.GET("/settings", admin(web.SettingsIndex(env.DB)))
+	router.POST("/settings", admin(web.SettingsSave(env.DB)))
+	router.GET("/alerts", viewer(web.AlertsIndex(env.DB)))
+	router.GET("/deliveries", viewer(web.AlertDeliveriesIndex(env.DB)))
+	router.POST("/deliveries/:id/retry", editor(web.AlertDeliveriesRetry(env.DB)))
+	router.DELETE("/deliveries/:id", editor(web.AlertDeliveriesDiscard(env.DB)))
+	router.GET("/redirectToSilence", viewer(web.RedirectToSilence(env.DB)))
+	// Heartbeats come from the jobs being monitored, which do not log in.
+	router.POST("/heartbeat/:monitor/:subprobe", web.RecordHeartbeat(env.DB))
+
+	router.GET(api.Prefix+"/monitors", viewer(api.MonitorsIndex(env.DB)))
+	router.POST(api.Prefix+"/monitors", editor(api.MonitorsCreate(env.DB)))
+	router.GET(api.Prefix+"/monitors/:id", viewer(api.MonitorsView(env.DB)))
+	router.PUT(api.Prefix+"/monitors/:id", editor(api.MonitorsUpdate(env.DB)))
+	router.DELETE(api.Prefix+"/monitors/:id", editor(api.MonitorsDelete(env.DB)))
+	router.GET(api.Prefix+"/labels", viewer(api.LabelsIndex(env.DB)))
+	router.POST(api.Prefix+"/labels", editor(api.LabelsCreate(env.DB)))
+	router.GET(api.Prefix+"/labels/:id", viewer(api.LabelsView(env.DB)))
+	router.PUT(api.Prefix+"/labels/:id", editor(api.LabelsUpdate(env.DB)))
+	router.DELETE(api.Prefix+"/labels/:id", editor(api.LabelsDelete(env.DB)))
+	router.GET(api.Prefix+"/silences", viewer(api.SilencesIndex(env.DB)))
+	router.POST(api.Prefix+"/silences", editor(api.SilencesCreate(env.DB)))
+	router.GET(api.Prefix+"/silences/:id", viewer(api.SilencesView(env.DB)))
+	router.PUT(api.Prefix+"/silences/:id", editor(api.SilencesUpdate(env.DB)))
+	router.DELETE(api.Prefix+"/silences/:id", editor(api.SilencesDelete(env.DB)))
+	router.GET(api.Prefix+"/resources", admin(api.ResourcesIndex(env.DB)))
+	router.POST(api.Prefix+"/resources", admin(api.ResourcesCreate(env.DB)))
+	router.GET(api.Prefix+"/resources/:id", admin(api.ResourcesView(env.DB)))
+	router.PUT(api.Prefix+"/resources/:id", admin(api.ResourcesUpdate(env.DB)))
+	router.DELETE(api.Prefix+"/resources/:id", admin(api.ResourcesDelete(env.DB)))
+	router.GET(api.Prefix+"/settings", admin(api.SettingsIndex(env.DB)))
+	router.PUT(api.Prefix+"/settings/:settingType", admin(api.SettingsUpdate(env.DB)))
 
 	router.ServeFiles("/static/css/*filepath", cssFiles.HTTPBox())
 	router.ServeFiles("/static/js/*filepath", jsFiles.HTTPBox())
 	router.Handler("GET", "/favicon.ico", http.FileServer(favicon.HTTPBox()))
+	metricsHandler := metrics.Handler()
+	router.GET("/metrics", viewer(func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		metricsHandler.ServeHTTP(w, req)
+	}))
 
 	return &WebServer{
 		Env:     env,
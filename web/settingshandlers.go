@@ -8,7 +8,9 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/juju/errors"
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
 	"github.com/yext/revere/setting"
 	"github.com/yext/revere/web/vm"
 	"github.com/yext/revere/web/vm/renderables"
@@ -80,6 +82,10 @@ func SettingsSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ ht
 				if err != nil {
 					return errors.Trace(err)
 				}
+				err = history.Record(tx, s, auth.UserName(req))
+				if err != nil {
+					return errors.Trace(err)
+				}
 			}
 			return nil
 		})
@@ -92,7 +98,7 @@ func SettingsSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, _ ht
 		for i, s := range ss {
 			si[i] = s
 		}
-		logSaveArray(si, body.Bytes(), req.URL.String())
+		logSaveArray(si, body.Bytes(), req)
 
 		setFlash(w, "saveStatus", []byte("updated"))
 	}
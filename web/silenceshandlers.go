@@ -13,7 +13,9 @@ import (
 	"github.com/juju/errors"
 	"github.com/julienschmidt/httprouter"
 
+	"github.com/yext/revere/auth"
 	"github.com/yext/revere/db"
+	"github.com/yext/revere/history"
 	"github.com/yext/revere/web/vm"
 	"github.com/yext/revere/web/vm/renderables"
 )
@@ -151,13 +153,17 @@ func SilencesSave(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p ht
 		}
 
 		err = DB.Tx(func(tx *db.Tx) error {
-			return s.Save(tx)
+			err := s.Save(tx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return history.Record(tx, s, auth.UserName(req))
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Unable to save silence: %s", err.Error()), http.StatusInternalServerError)
 			return
 		}
-		logSave(s, body.Bytes(), req.URL.String())
+		logSave(s, body.Bytes(), req)
 
 		setFlash(w, "saveStatus", []byte(saveStatus))
 
@@ -92,7 +92,13 @@ func SubprobesView(DB *db.DB) func(w http.ResponseWriter, req *http.Request, p h
 			return
 		}
 
-		renderable := renderables.NewSubprobeView(probe, subprobe, readings)
+		alerts, err := vm.AllAlertsForSubprobe(DB, db.SubprobeID(id))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Unable to retrieve alerts: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+
+		renderable := renderables.NewSubprobeView(probe, subprobe, readings, alerts)
 		err = render(w, renderable)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Unable to retrieve subprobe: %s", err.Error()),
@@ -0,0 +1,132 @@
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+)
+
+// Acknowledgement records who is handling a subprobe's problem. Duration is
+// the number of hours the acknowledgement lasts when it is saved.
+type Acknowledgement struct {
+	SubprobeID db.SubprobeID
+	MonitorID  db.MonitorID
+	AckedBy    string
+	Comment    string
+	Duration   int64
+	State      state.State
+	Acked      time.Time
+	Expires    time.Time
+}
+
+const (
+	defaultAckDuration = 4
+	maxAckDuration     = 72
+	maxAckedByLength   = 100
+)
+
+func (*Acknowledgement) ComponentName() string {
+	return "Acknowledgement"
+}
+
+func (a *Acknowledgement) Id() int64 {
+	return int64(a.SubprobeID)
+}
+
+// NewAcknowledgement loads the active acknowledgement of a subprobe, or
+// returns nil if there is none.
+func NewAcknowledgement(DB *db.DB, id db.SubprobeID) (*Acknowledgement, error) {
+	ack, err := DB.LoadActiveAcknowledgement(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ack == nil {
+		return nil, nil
+	}
+
+	return newAcknowledgementFromDB(ack), nil
+}
+
+func BlankAcknowledgement(s *Subprobe) *Acknowledgement {
+	return &Acknowledgement{
+		SubprobeID: s.SubprobeID,
+		MonitorID:  s.MonitorID,
+		Duration:   defaultAckDuration,
+	}
+}
+
+func newAcknowledgementFromDB(ack *db.Acknowledgement) *Acknowledgement {
+	return &Acknowledgement{
+		SubprobeID: ack.SubprobeID,
+		MonitorID:  ack.MonitorID,
+		AckedBy:    ack.AckedBy,
+		Comment:    ack.Comment,
+		Duration:   int64(ack.Expires.Sub(ack.Acked) / time.Hour),
+		State:      ack.State,
+		Acked:      ack.Acked,
+		Expires:    ack.Expires,
+	}
+}
+
+// populateAcknowledgements attaches the active acknowledgements to the
+// subprobes they belong to.
+func populateAcknowledgements(tx *db.Tx, subprobes []*Subprobe) error {
+	acks, err := tx.LoadActiveAcknowledgements()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	byID := make(map[db.SubprobeID]*Acknowledgement, len(acks))
+	for i := range acks {
+		byID[acks[i].SubprobeID] = newAcknowledgementFromDB(&acks[i])
+	}
+	for _, s := range subprobes {
+		s.Ack = byID[s.SubprobeID]
+	}
+	return nil
+}
+
+// Validate checks that a can acknowledge s in its current state.
+func (a *Acknowledgement) Validate(s *Subprobe) (errs []string) {
+	if a.AckedBy == "" {
+		errs = append(errs, "Your name is required.")
+	} else if len(a.AckedBy) > maxAckedByLength {
+		errs = append(errs, fmt.Sprintf("Name cannot be longer than %d characters.", maxAckedByLength))
+	}
+
+	if a.Duration < 1 || a.Duration > maxAckDuration {
+		errs = append(errs, fmt.Sprintf("Duration must be between 1 and %d hours.", maxAckDuration))
+	}
+
+	if s.Status.State == state.Normal {
+		errs = append(errs, "Only subprobes that are not normal can be acknowledged.")
+	}
+	return
+}
+
+// Save acknowledges s in its current state, replacing any earlier
+// acknowledgement.
+func (a *Acknowledgement) Save(DB *db.DB, s *Subprobe) error {
+	a.SubprobeID = s.SubprobeID
+	a.MonitorID = s.MonitorID
+	a.State = s.Status.State
+	a.Acked = time.Now().UTC()
+	a.Expires = a.Acked.Add(time.Duration(a.Duration) * time.Hour)
+
+	return errors.Trace(DB.SaveAcknowledgement(&db.Acknowledgement{
+		SubprobeID: a.SubprobeID,
+		MonitorID:  a.MonitorID,
+		AckedBy:    a.AckedBy,
+		Comment:    a.Comment,
+		State:      a.State,
+		Acked:      a.Acked,
+		Expires:    a.Expires,
+	}))
+}
+
+func DeleteAcknowledgement(DB *db.DB, id db.SubprobeID) error {
+	return errors.Trace(DB.DeleteAcknowledgement(id))
+}
@@ -0,0 +1,48 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yext/revere/state"
+)
+
+func criticalSubprobe() *Subprobe {
+	s := new(Subprobe)
+	s.SubprobeID = 1
+	s.MonitorID = 1
+	s.Status.State = state.Critical
+	return s
+}
+
+func TestValidAcknowledgement(t *testing.T) {
+	s := criticalSubprobe()
+	a := BlankAcknowledgement(s)
+	a.AckedBy = "oncall"
+	if errs := a.Validate(s); len(errs) > 0 {
+		t.Errorf("Expected valid acknowledgement, got errors: %v\n", errs)
+	}
+}
+
+func TestInvalidAcknowledgements(t *testing.T) {
+	tests := []struct {
+		desc   string
+		modify func(*Acknowledgement, *Subprobe)
+	}{
+		{"missing name", func(a *Acknowledgement, _ *Subprobe) { a.AckedBy = "" }},
+		{"long name", func(a *Acknowledgement, _ *Subprobe) { a.AckedBy = strings.Repeat("a", 101) }},
+		{"zero duration", func(a *Acknowledgement, _ *Subprobe) { a.Duration = 0 }},
+		{"long duration", func(a *Acknowledgement, _ *Subprobe) { a.Duration = maxAckDuration + 1 }},
+		{"normal subprobe", func(_ *Acknowledgement, s *Subprobe) { s.Status.State = state.Normal }},
+	}
+
+	for _, test := range tests {
+		s := criticalSubprobe()
+		a := BlankAcknowledgement(s)
+		a.AckedBy = "oncall"
+		test.modify(a, s)
+		if errs := a.Validate(s); len(errs) != 1 {
+			t.Errorf("Expected one error for %s, got: %v\n", test.desc, errs)
+		}
+	}
+}
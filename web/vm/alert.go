@@ -0,0 +1,154 @@
+package vm
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+)
+
+const (
+	alertFilterTimeFormat = "2006-01-02 15:04"
+
+	maxAlertsShown         = 500
+	maxSubprobeAlertsShown = 20
+)
+
+// Alert is a record of an alert sent, or that failed to send, to a trigger's
+// target.
+type Alert struct {
+	AlertID      db.AlertID
+	MonitorID    db.MonitorID
+	MonitorName  string
+	SubprobeID   db.SubprobeID
+	SubprobeName string
+	TriggerID    db.TriggerID
+	TargetType   string
+	Recipients   string
+	OldState     state.State
+	State        state.State
+	Recorded     time.Time
+	Sent         time.Time
+	Success      bool
+	Error        string
+}
+
+// AlertFilter holds the filters for the alerts index. Zero fields don't
+// filter. From and To are UTC times in alertFilterTimeFormat.
+type AlertFilter struct {
+	MonitorID  db.MonitorID
+	SubprobeID db.SubprobeID
+	LabelID    db.LabelID
+	TargetType db.TargetType
+	From       string
+	To         string
+}
+
+func (a *Alert) Id() int64 {
+	return int64(a.AlertID)
+}
+
+func FilteredAlerts(DB *db.DB, f *AlertFilter) ([]*Alert, error) {
+	filter, err := f.toDBFilter()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	filter.Limit = maxAlertsShown
+
+	return loadAlerts(DB, filter)
+}
+
+func AllAlertsForSubprobe(DB *db.DB, id db.SubprobeID) ([]*Alert, error) {
+	return loadAlerts(DB, db.AlertFilter{SubprobeID: id, Limit: maxSubprobeAlertsShown})
+}
+
+func loadAlerts(DB *db.DB, filter db.AlertFilter) ([]*Alert, error) {
+	dbAlerts, err := DB.LoadAlerts(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	targetTypeNames := make(map[db.TargetType]string)
+	for _, tt := range target.AllTargets() {
+		targetTypeNames[tt.Id()] = tt.Name()
+	}
+
+	alerts := make([]*Alert, len(dbAlerts))
+	for i, a := range dbAlerts {
+		alerts[i] = &Alert{
+			AlertID:      a.AlertID,
+			MonitorID:    a.MonitorID,
+			MonitorName:  a.MonitorName,
+			SubprobeID:   a.SubprobeID,
+			SubprobeName: a.SubprobeName,
+			TriggerID:    a.TriggerID,
+			TargetType:   targetTypeNames[a.TargetType],
+			Recipients:   a.Recipients,
+			OldState:     a.OldState,
+			State:        a.State,
+			Recorded:     a.Recorded,
+			Sent:         a.Sent,
+			Success:      a.Success,
+			Error:        a.Error,
+		}
+	}
+	return alerts, nil
+}
+
+// SetHtmlParams sets the filter from the alerts index's query string.
+func (f *AlertFilter) SetHtmlParams(values url.Values) error {
+	ids := []struct {
+		param string
+		set   func(int64)
+	}{
+		{"monitor", func(id int64) { f.MonitorID = db.MonitorID(id) }},
+		{"subprobe", func(id int64) { f.SubprobeID = db.SubprobeID(id) }},
+		{"label", func(id int64) { f.LabelID = db.LabelID(id) }},
+		{"target", func(id int64) { f.TargetType = db.TargetType(id) }},
+	}
+	for _, id := range ids {
+		v := values.Get(id.param)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.Errorf("Invalid %s: %s", id.param, v)
+		}
+		id.set(parsed)
+	}
+
+	f.From = values.Get("from")
+	f.To = values.Get("to")
+	_, err := f.toDBFilter()
+	return errors.Trace(err)
+}
+
+func (f *AlertFilter) toDBFilter() (db.AlertFilter, error) {
+	filter := db.AlertFilter{
+		MonitorID:  f.MonitorID,
+		SubprobeID: f.SubprobeID,
+		LabelID:    f.LabelID,
+		TargetType: f.TargetType,
+	}
+
+	var err error
+	if f.From != "" {
+		filter.From, err = time.Parse(alertFilterTimeFormat, f.From)
+		if err != nil {
+			return filter, errors.Errorf("Invalid from time: %s. Should be like %s.", f.From, alertFilterTimeFormat)
+		}
+	}
+	if f.To != "" {
+		filter.To, err = time.Parse(alertFilterTimeFormat, f.To)
+		if err != nil {
+			return filter, errors.Errorf("Invalid to time: %s. Should be like %s.", f.To, alertFilterTimeFormat)
+		}
+	}
+	return filter, nil
+}
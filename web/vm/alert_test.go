@@ -0,0 +1,53 @@
+package vm
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/yext/revere/db"
+)
+
+func TestAlertFilterSetHtmlParams(t *testing.T) {
+	values := url.Values{
+		"monitor": {"3"},
+		"label":   {"4"},
+		"target":  {"2"},
+		"from":    {"2016-06-01 12:00"},
+		"to":      {""},
+	}
+
+	f := new(AlertFilter)
+	if err := f.SetHtmlParams(values); err != nil {
+		t.Fatalf("Unexpected error setting alert filter: %s\n", err.Error())
+	}
+
+	filter, err := f.toDBFilter()
+	if err != nil {
+		t.Fatalf("Unexpected error converting alert filter: %s\n", err.Error())
+	}
+	expected := db.AlertFilter{
+		MonitorID:  3,
+		LabelID:    4,
+		TargetType: 2,
+		From:       time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if filter != expected {
+		t.Errorf("Expected alert filter %+v, got %+v\n", expected, filter)
+	}
+}
+
+func TestInvalidAlertFilter(t *testing.T) {
+	invalid := []url.Values{
+		{"monitor": {"abc"}},
+		{"target": {"1.5"}},
+		{"from": {"2016-06-01"}},
+		{"to": {"yesterday"}},
+	}
+	for _, values := range invalid {
+		f := new(AlertFilter)
+		if err := f.SetHtmlParams(values); err == nil {
+			t.Errorf("Expected error for alert filter params: %v\n", values)
+		}
+	}
+}
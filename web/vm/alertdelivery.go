@@ -0,0 +1,88 @@
+package vm
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/juju/errors"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/state"
+	"github.com/yext/revere/target"
+)
+
+// AlertDelivery is a queued alert that has not been sent yet, either because
+// it is waiting to be retried or because it has run out of retries.
+type AlertDelivery struct {
+	AlertDeliveryID db.AlertDeliveryID
+	MonitorID       db.MonitorID
+	MonitorName     string
+	SubprobeID      db.SubprobeID
+	SubprobeName    string
+	TargetType      string
+	State           state.State
+	Recorded        time.Time
+	Attempts        int32
+	Created         time.Time
+	NextAttempt     time.Time
+	LastError       string
+	Dead            bool
+
+	// GroupedAlerts is how many more alerts are being sent with this one.
+	GroupedAlerts int
+}
+
+func (d *AlertDelivery) Id() int64 {
+	return int64(d.AlertDeliveryID)
+}
+
+func AllAlertDeliveries(DB *db.DB) ([]*AlertDelivery, error) {
+	dbDeliveries, err := DB.LoadAlertDeliveries()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	targetTypeNames := make(map[db.TargetType]string)
+	for _, tt := range target.AllTargets() {
+		targetTypeNames[tt.Id()] = tt.Name()
+	}
+
+	deliveries := make([]*AlertDelivery, len(dbDeliveries))
+	for i, d := range dbDeliveries {
+		deliveries[i] = &AlertDelivery{
+			AlertDeliveryID: d.AlertDeliveryID,
+			MonitorID:       d.MonitorID,
+			MonitorName:     d.MonitorName,
+			SubprobeID:      d.SubprobeID,
+			SubprobeName:    d.SubprobeName,
+			TargetType:      targetTypeNames[d.TargetType],
+			Attempts:        d.Attempts,
+			Created:         d.Created,
+			NextAttempt:     d.NextAttempt,
+			LastError:       d.LastError,
+			Dead:            d.Dead,
+		}
+
+		// A delivery whose alert cannot be read is still listed, so
+		// that it can be discarded.
+		if a, err := target.LoadAlert(d.Alert); err == nil {
+			deliveries[i].State = a.NewState
+			deliveries[i].Recorded = a.Recorded
+		}
+
+		var grouped []types.JSONText
+		if err := d.GroupedAlerts.Unmarshal(&grouped); err == nil {
+			deliveries[i].GroupedAlerts = len(grouped)
+		}
+	}
+	return deliveries, nil
+}
+
+// RetryAlertDelivery gives a dead delivery a fresh set of retries, starting
+// immediately.
+func RetryAlertDelivery(DB *db.DB, id db.AlertDeliveryID) error {
+	return errors.Trace(DB.RequeueAlertDelivery(id, time.Now()))
+}
+
+func DiscardAlertDelivery(DB *db.DB, id db.AlertDeliveryID) error {
+	return errors.Trace(DB.DeleteAlertDelivery(id))
+}
@@ -17,6 +17,10 @@ func MonitorViewBcs(mn string, id int64) []Breadcrumb {
 	return append(MonitorIndexBcs(), Breadcrumb{mn, fmt.Sprintf("/monitors/%d", id)})
 }
 
+func MonitorVersionBcs(mn string, id int64, version int32) []Breadcrumb {
+	return append(MonitorViewBcs(mn, id), Breadcrumb{fmt.Sprintf("v%d", version), fmt.Sprintf("/monitors/%d/history/%d", id, version)})
+}
+
 func SubprobeIndexBcs(mn string, id int64) []Breadcrumb {
 	return append(MonitorViewBcs(mn, id), Breadcrumb{"Subprobe", fmt.Sprintf("/monitors/%d/subprobes", id)})
 }
@@ -25,6 +29,10 @@ func SubprobeViewBcs(s *Subprobe) []Breadcrumb {
 	return append(SubprobeIndexBcs(s.MonitorName, int64(s.MonitorID)), Breadcrumb{s.Name, fmt.Sprintf("/monitors/%d/subprobes/%d", s.MonitorID, s.SubprobeID)})
 }
 
+func AcknowledgementBcs(s *Subprobe) []Breadcrumb {
+	return append(SubprobeViewBcs(s), Breadcrumb{"Acknowledge", fmt.Sprintf("/monitors/%d/subprobes/%d/ack", s.MonitorID, s.SubprobeID)})
+}
+
 func SilencesIndexBcs() []Breadcrumb {
 	return []Breadcrumb{Breadcrumb{"Silences", "/silences"}}
 }
@@ -41,6 +49,30 @@ func LabelViewBcs(mn string, id int64) []Breadcrumb {
 	return append(LabelIndexBcs(), Breadcrumb{mn, fmt.Sprintf("/labels/%d", id)})
 }
 
+func EscalationPoliciesIndexBcs() []Breadcrumb {
+	return []Breadcrumb{Breadcrumb{"Escalations", "/escalations"}}
+}
+
+func EscalationPolicyViewBcs(n string, id int64) []Breadcrumb {
+	return append(EscalationPoliciesIndexBcs(), Breadcrumb{n, fmt.Sprintf("/escalations/%d", id)})
+}
+
+func SchedulesIndexBcs() []Breadcrumb {
+	return []Breadcrumb{Breadcrumb{"Schedules", "/schedules"}}
+}
+
+func ScheduleViewBcs(n string, id int64) []Breadcrumb {
+	return append(SchedulesIndexBcs(), Breadcrumb{n, fmt.Sprintf("/schedules/%d", id)})
+}
+
+func AlertDeliveriesIndexBcs() []Breadcrumb {
+	return []Breadcrumb{Breadcrumb{"Alert Deliveries", "/deliveries"}}
+}
+
+func AlertsIndexBcs() []Breadcrumb {
+	return []Breadcrumb{Breadcrumb{"Alerts", "/alerts"}}
+}
+
 func IsLastBc(a []Breadcrumb, i int) bool {
 	return i == len(a)-1
 }
@@ -0,0 +1,181 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/yext/revere/db"
+)
+
+type EscalationPolicy struct {
+	EscalationPolicyID db.EscalationPolicyID
+	Name               string
+	Description        string
+	Steps              []*EscalationStep
+	MonitorIDs         []db.MonitorID
+	LabelIDs           []db.LabelID
+}
+
+func (*EscalationPolicy) ComponentName() string {
+	return "EscalationPolicy"
+}
+
+func (ep *EscalationPolicy) Id() int64 {
+	return int64(ep.EscalationPolicyID)
+}
+
+func NewEscalationPolicy(tx *db.Tx, id db.EscalationPolicyID) (*EscalationPolicy, error) {
+	policy, err := tx.LoadEscalationPolicy(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if policy == nil {
+		return nil, errors.Errorf("Escalation policy not found: %d", id)
+	}
+
+	ep := newEscalationPolicyFromDB(policy)
+
+	err = ep.loadComponents(tx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return ep, nil
+}
+
+func newEscalationPolicyFromDB(policy *db.EscalationPolicy) *EscalationPolicy {
+	return &EscalationPolicy{
+		EscalationPolicyID: policy.EscalationPolicyID,
+		Name:               policy.Name,
+		Description:        policy.Description,
+	}
+}
+
+func BlankEscalationPolicy() *EscalationPolicy {
+	return &EscalationPolicy{
+		Steps: blankEscalationSteps(),
+	}
+}
+
+func AllEscalationPolicies(tx *db.Tx) ([]*EscalationPolicy, error) {
+	policies, err := tx.LoadEscalationPolicies()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	eps := make([]*EscalationPolicy, len(policies))
+	for i, policy := range policies {
+		eps[i] = newEscalationPolicyFromDB(policy)
+	}
+	return eps, nil
+}
+
+func (ep *EscalationPolicy) loadComponents(tx *db.Tx) error {
+	var err error
+	ep.Steps, err = newEscalationSteps(tx, ep.EscalationPolicyID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ep.MonitorIDs, err = tx.LoadMonitorIDsForEscalationPolicy(ep.EscalationPolicyID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ep.LabelIDs, err = tx.LoadLabelIDsForEscalationPolicy(ep.EscalationPolicyID)
+	return errors.Trace(err)
+}
+
+// HasMonitor returns whether the policy applies directly to a monitor.
+func (ep *EscalationPolicy) HasMonitor(id db.MonitorID) bool {
+	for _, monitorID := range ep.MonitorIDs {
+		if monitorID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLabel returns whether the policy applies to the monitors with a label.
+func (ep *EscalationPolicy) HasLabel(id db.LabelID) bool {
+	for _, labelID := range ep.LabelIDs {
+		if labelID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (ep *EscalationPolicy) Validate(DB *db.DB) (errs []string) {
+	if ep.Name == "" {
+		errs = append(errs, fmt.Sprintf("Escalation policy name is required"))
+	}
+
+	for _, s := range ep.Steps {
+		if !isDelete(s) {
+			errs = append(errs, s.validate()...)
+		}
+	}
+
+	for _, id := range ep.MonitorIDs {
+		if !DB.IsExistingMonitor(id) {
+			errs = append(errs, fmt.Sprintf("Invalid monitor: %d", id))
+		}
+	}
+
+	for _, id := range ep.LabelIDs {
+		if !DB.IsExistingLabel(id) {
+			errs = append(errs, fmt.Sprintf("Invalid label: %d", id))
+		}
+	}
+	return
+}
+
+func (ep *EscalationPolicy) IsCreate() bool {
+	return ep.Id() == 0
+}
+
+func (ep *EscalationPolicy) Save(tx *db.Tx) error {
+	policy := ep.toDBEscalationPolicy()
+
+	var err error
+	if isCreate(ep) {
+		ep.EscalationPolicyID, err = tx.CreateEscalationPolicy(policy)
+		for _, step := range ep.Steps {
+			step.setEscalationPolicyID(ep.EscalationPolicyID)
+		}
+	} else {
+		// Monitors the policy no longer applies to have to be reloaded too.
+		err = tx.TouchMonitorsForEscalationPolicy(ep.EscalationPolicyID)
+		if err == nil {
+			err = tx.UpdateEscalationPolicy(policy)
+		}
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, s := range ep.Steps {
+		err = s.save(tx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	err = tx.SetEscalationPolicyMonitors(ep.EscalationPolicyID, ep.MonitorIDs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = tx.SetEscalationPolicyLabels(ep.EscalationPolicyID, ep.LabelIDs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return errors.Trace(tx.TouchMonitorsForEscalationPolicy(ep.EscalationPolicyID))
+}
+
+func (ep *EscalationPolicy) toDBEscalationPolicy() *db.EscalationPolicy {
+	return &db.EscalationPolicy{
+		EscalationPolicyID: ep.EscalationPolicyID,
+		Name:               ep.Name,
+		Description:        ep.Description,
+	}
+}
@@ -0,0 +1,55 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/yext/revere/db"
+)
+
+func validEscalationStep() *EscalationStep {
+	s := BlankEscalationStep()
+	s.Trigger = validTrigger()
+	s.Delay = 15
+	s.DelayType = "minute"
+	return s
+}
+
+func TestValidEscalationStepDelays(t *testing.T) {
+	s := validEscalationStep()
+	if errs := s.validate(); errs != nil {
+		t.Errorf("Unexpected errors for escalation step: %v\n", errs)
+	}
+
+	s.Delay = 0
+	s.DelayType = ""
+	if errs := s.validate(); errs != nil {
+		t.Errorf("Unexpected errors for immediate escalation step: %v\n", errs)
+	}
+}
+
+func TestInvalidEscalationStepDelays(t *testing.T) {
+	s := validEscalationStep()
+	s.Delay = -1
+	if errs := s.validate(); len(errs) != 1 {
+		t.Errorf("Expected one error for negative delay, got: %v\n", errs)
+	}
+
+	s.Delay = 5
+	s.DelayType = "fortnight"
+	if errs := s.validate(); len(errs) != 1 {
+		t.Errorf("Expected one error for unknown delay type, got: %v\n", errs)
+	}
+}
+
+func TestEscalationPolicyAttachments(t *testing.T) {
+	ep := BlankEscalationPolicy()
+	ep.MonitorIDs = []db.MonitorID{1, 3}
+	ep.LabelIDs = []db.LabelID{2}
+
+	if !ep.HasMonitor(3) || ep.HasMonitor(2) {
+		t.Errorf("Wrong monitors for escalation policy with monitors %v\n", ep.MonitorIDs)
+	}
+	if !ep.HasLabel(2) || ep.HasLabel(1) {
+		t.Errorf("Wrong labels for escalation policy with labels %v\n", ep.LabelIDs)
+	}
+}
@@ -0,0 +1,98 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/util"
+)
+
+type EscalationStep struct {
+	Trigger            *Trigger
+	EscalationPolicyID db.EscalationPolicyID
+	Delay              int64
+	DelayType          string
+}
+
+func newEscalationSteps(tx *db.Tx, id db.EscalationPolicyID) ([]*EscalationStep, error) {
+	steps, err := tx.LoadStepsForEscalationPolicy(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ess := make([]*EscalationStep, len(steps))
+	for i, step := range steps {
+		t, err := newTriggerFromModel(step.Trigger)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		delay, delayType := util.GetPeriodAndType(step.DelayMilli)
+		ess[i] = &EscalationStep{
+			Trigger:            t,
+			EscalationPolicyID: step.EscalationPolicyID,
+			Delay:              delay,
+			DelayType:          delayType,
+		}
+	}
+
+	return ess, nil
+}
+
+func BlankEscalationStep() *EscalationStep {
+	return &EscalationStep{
+		Trigger: BlankTrigger(),
+	}
+}
+
+func blankEscalationSteps() []*EscalationStep {
+	return []*EscalationStep{}
+}
+
+func (es *EscalationStep) Id() int64 {
+	return es.Trigger.Id()
+}
+
+func (es *EscalationStep) IsCreate() bool {
+	return es.Id() == 0
+}
+
+func (es *EscalationStep) IsDelete() bool {
+	return es.Trigger.Delete
+}
+
+func (es *EscalationStep) validate() (errs []string) {
+	errs = append(errs, es.Trigger.validate()...)
+
+	if es.Delay < 0 || (es.Delay > 0 && util.GetMs(es.Delay, es.DelayType) == 0) {
+		errs = append(errs, fmt.Sprintf("Invalid escalation delay: %d %s", es.Delay, es.DelayType))
+	}
+	return
+}
+
+func (es *EscalationStep) save(tx *db.Tx) error {
+	trigger, err := es.Trigger.toDBTrigger()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	step := db.EscalationStep{
+		EscalationPolicyID: es.EscalationPolicyID,
+		DelayMilli:         util.GetMs(es.Delay, es.DelayType),
+		Trigger:            trigger,
+	}
+	if isCreate(es) {
+		var id db.TriggerID
+		id, err = tx.CreateEscalationStep(step)
+		es.Trigger.setId(id)
+	} else if isDelete(es) {
+		err = tx.DeleteEscalationStep(step.TriggerID)
+	} else {
+		err = tx.UpdateEscalationStep(step)
+	}
+
+	return errors.Trace(err)
+}
+
+func (es *EscalationStep) setEscalationPolicyID(id db.EscalationPolicyID) {
+	es.EscalationPolicyID = id
+}
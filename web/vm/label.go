@@ -141,6 +141,10 @@ func (l *Label) Save(tx *db.Tx) error {
 	return nil
 }
 
+func DeleteLabel(tx *db.Tx, id db.LabelID) error {
+	return errors.Trace(tx.DeleteLabel(id))
+}
+
 func (l *Label) toDBLabel() *db.Label {
 	return &db.Label{
 		LabelID:     l.LabelID,
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/yext/revere/db"
 	"github.com/yext/revere/probe"
+	"github.com/yext/revere/util"
 )
 
 type Monitor struct {
@@ -22,6 +23,15 @@ type Monitor struct {
 	Version     int32
 	// TODO(fchen): changed and Archived need to match
 	Archived *time.Time
+
+	SustainReadings   int32
+	SustainPeriod     int64
+	SustainPeriodType string
+
+	FlapTransitions int32
+	FlapWindow      int64
+	FlapWindowType  string
+
 	Probe    probe.VM
 	Triggers []*MonitorTrigger
 	Labels   []*MonitorLabel
@@ -63,6 +73,8 @@ func NewMonitor(tx *db.Tx, id db.MonitorID) (*Monitor, error) {
 
 func newMonitorFromDB(monitor *db.Monitor, tx *db.Tx) (*Monitor, error) {
 	var err error
+	sustainPeriod, sustainPeriodType := util.GetPeriodAndType(monitor.SustainMilli)
+	flapWindow, flapWindowType := util.GetPeriodAndType(monitor.FlapWindowMilli)
 	m := &Monitor{
 		MonitorID:   monitor.MonitorID,
 		Name:        monitor.Name,
@@ -77,6 +89,14 @@ func newMonitorFromDB(monitor *db.Monitor, tx *db.Tx) (*Monitor, error) {
 		Probe:       nil,
 		Triggers:    nil,
 		Labels:      nil,
+
+		SustainReadings:   monitor.SustainReadings,
+		SustainPeriod:     sustainPeriod,
+		SustainPeriodType: sustainPeriodType,
+
+		FlapTransitions: monitor.FlapTransitions,
+		FlapWindow:      flapWindow,
+		FlapWindowType:  flapWindowType,
 	}
 	m.Probe, err = probe.LoadFromDB(monitor.ProbeType, string(monitor.Probe), tx)
 	if err != nil {
@@ -190,8 +210,23 @@ func (m *Monitor) Validate(DB *db.DB) (errs []string) {
 	m.Probe, err = probe.LoadFromParams(m.ProbeType, m.ProbeParams)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("Unable to load probe for monitor: %s", m.ProbeParams))
+	} else {
+		errs = append(errs, m.Probe.Validate()...)
+	}
+
+	if m.SustainReadings < 0 {
+		errs = append(errs, fmt.Sprintf("Invalid number of readings to sustain: %d", m.SustainReadings))
+	}
+	if m.SustainPeriod < 0 || (m.SustainPeriod > 0 && util.GetMs(m.SustainPeriod, m.SustainPeriodType) == 0) {
+		errs = append(errs, fmt.Sprintf("Invalid period to sustain: %d %s", m.SustainPeriod, m.SustainPeriodType))
+	}
+
+	if m.FlapTransitions < 0 {
+		errs = append(errs, fmt.Sprintf("Invalid number of state changes for flapping: %d", m.FlapTransitions))
+	}
+	if m.FlapTransitions > 0 && util.GetMs(m.FlapWindow, m.FlapWindowType) <= 0 {
+		errs = append(errs, fmt.Sprintf("Invalid flap detection window: %d %s", m.FlapWindow, m.FlapWindowType))
 	}
-	errs = append(errs, m.Probe.Validate()...)
 
 	for _, mt := range m.Triggers {
 		errs = append(errs, mt.validate(DB)...)
@@ -246,6 +281,21 @@ func (m *Monitor) Save(tx *db.Tx) error {
 	return nil
 }
 
+// ArchiveMonitor archives a monitor, which stops it from being run.
+func ArchiveMonitor(tx *db.Tx, id db.MonitorID) error {
+	monitor, err := tx.LoadMonitor(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if monitor == nil {
+		return errors.Errorf("Monitor not found: %d", id)
+	}
+
+	now := time.Now().UTC()
+	monitor.Archived = &now
+	return errors.Trace(tx.UpdateMonitor(monitor))
+}
+
 func (m *Monitor) toDBMonitor() (*db.Monitor, error) {
 	probeJSON, err := m.Probe.SerializeForDB()
 	if err != nil {
@@ -265,5 +315,11 @@ func (m *Monitor) toDBMonitor() (*db.Monitor, error) {
 		Changed:  m.Changed,
 		Version:  m.Version,
 		Archived: m.Archived,
+
+		SustainReadings: m.SustainReadings,
+		SustainMilli:    util.GetMs(m.SustainPeriod, m.SustainPeriodType),
+
+		FlapTransitions: m.FlapTransitions,
+		FlapWindowMilli: util.GetMs(m.FlapWindow, m.FlapWindowType),
 	}, nil
 }
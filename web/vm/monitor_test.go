@@ -61,3 +61,60 @@ func TestValidTriggerSubprobe(t *testing.T) {
 		}
 	}
 }
+
+func TestValidMonitorSustain(t *testing.T) {
+	monitor := validMonitor()
+	monitor.Triggers = nil
+	testDB := new(db.DB)
+	monitor.SustainReadings = 3
+	monitor.SustainPeriod = 5
+	monitor.SustainPeriodType = "minute"
+	if errs := monitor.Validate(testDB); errs != nil {
+		t.Errorf("Unexpected errors for valid sustain settings: %v\n", errs)
+	}
+}
+
+func TestInvalidMonitorSustain(t *testing.T) {
+	monitor := validMonitor()
+	monitor.Triggers = nil
+	testDB := new(db.DB)
+	monitor.SustainReadings = -1
+	if errs := monitor.Validate(testDB); errs == nil {
+		t.Errorf("Expected error for invalid sustain readings: %d\n", monitor.SustainReadings)
+	}
+
+	monitor.SustainReadings = 0
+	monitor.SustainPeriod = 5
+	monitor.SustainPeriodType = "fortnight"
+	if errs := monitor.Validate(testDB); errs == nil {
+		t.Errorf("Expected error for invalid sustain period type: %s\n", monitor.SustainPeriodType)
+	}
+}
+
+func TestValidMonitorFlapDetection(t *testing.T) {
+	monitor := validMonitor()
+	monitor.Triggers = nil
+	testDB := new(db.DB)
+	monitor.FlapTransitions = 4
+	monitor.FlapWindow = 30
+	monitor.FlapWindowType = "minute"
+	if errs := monitor.Validate(testDB); errs != nil {
+		t.Errorf("Unexpected errors for valid flap detection settings: %v\n", errs)
+	}
+}
+
+func TestInvalidMonitorFlapDetection(t *testing.T) {
+	monitor := validMonitor()
+	monitor.Triggers = nil
+	testDB := new(db.DB)
+	monitor.FlapTransitions = -1
+	if errs := monitor.Validate(testDB); errs == nil {
+		t.Errorf("Expected error for invalid flap transitions: %d\n", monitor.FlapTransitions)
+	}
+
+	monitor.FlapTransitions = 4
+	monitor.FlapWindow = 0
+	if errs := monitor.Validate(testDB); errs == nil {
+		t.Error("Expected error for flap detection with no window")
+	}
+}
@@ -0,0 +1,56 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type AcknowledgementEdit struct {
+	ack      *vm.Acknowledgement
+	subprobe *vm.Subprobe
+	subs     []Renderable
+}
+
+func NewAcknowledgementEdit(a *vm.Acknowledgement, s *vm.Subprobe) *AcknowledgementEdit {
+	ae := AcknowledgementEdit{}
+	ae.ack = a
+	ae.subprobe = s
+
+	return &ae
+}
+
+func (ae *AcknowledgementEdit) name() string {
+	return "Acknowledgement"
+}
+
+func (ae *AcknowledgementEdit) template() string {
+	return "acknowledgements-edit.html"
+}
+
+func (ae *AcknowledgementEdit) data() interface{} {
+	return map[string]interface{}{
+		"Acknowledgement": ae.ack,
+		"Subprobe":        ae.subprobe,
+	}
+}
+
+func (ae *AcknowledgementEdit) scripts() []string {
+	return []string{
+		"acknowledgements.js",
+	}
+}
+
+func (ae *AcknowledgementEdit) breadcrumbs() []vm.Breadcrumb {
+	return vm.AcknowledgementBcs(ae.subprobe)
+}
+
+func (ae *AcknowledgementEdit) subRenderables() []Renderable {
+	return nil
+}
+
+func (ae *AcknowledgementEdit) renderPropagate() (*renderResult, error) {
+	return renderPropagate(ae)
+}
+
+func (ae *AcknowledgementEdit) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -0,0 +1,62 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type AlertDeliveriesIndex struct {
+	deliveries []*vm.AlertDelivery
+	subs       []Renderable
+}
+
+func NewAlertDeliveriesIndex(ds []*vm.AlertDelivery) *AlertDeliveriesIndex {
+	adi := new(AlertDeliveriesIndex)
+	adi.deliveries = ds
+
+	return adi
+}
+
+func (adi *AlertDeliveriesIndex) name() string {
+	return "AlertDeliveriesIndex"
+}
+
+func (adi *AlertDeliveriesIndex) template() string {
+	return "alert-deliveries-index.html"
+}
+
+func (adi *AlertDeliveriesIndex) data() interface{} {
+	var dead, pending []*vm.AlertDelivery
+	for _, d := range adi.deliveries {
+		if d.Dead {
+			dead = append(dead, d)
+		} else {
+			pending = append(pending, d)
+		}
+	}
+	return map[string]interface{}{
+		"Dead":    dead,
+		"Pending": pending,
+	}
+}
+
+func (adi *AlertDeliveriesIndex) scripts() []string {
+	return []string{
+		"alert-deliveries-index.js",
+	}
+}
+
+func (adi *AlertDeliveriesIndex) breadcrumbs() []vm.Breadcrumb {
+	return vm.AlertDeliveriesIndexBcs()
+}
+
+func (adi *AlertDeliveriesIndex) subRenderables() []Renderable {
+	return nil
+}
+
+func (adi *AlertDeliveriesIndex) renderPropagate() (*renderResult, error) {
+	return renderPropagate(adi)
+}
+
+func (adi *AlertDeliveriesIndex) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
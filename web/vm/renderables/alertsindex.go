@@ -0,0 +1,54 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type AlertsIndex struct {
+	alerts   []*vm.Alert
+	filter   *vm.AlertFilter
+	monitors []*vm.Monitor
+	labels   []*vm.Label
+	subs     []Renderable
+}
+
+func NewAlertsIndex(as []*vm.Alert, f *vm.AlertFilter, ms []*vm.Monitor, ls []*vm.Label) *AlertsIndex {
+	return &AlertsIndex{as, f, ms, ls, nil}
+}
+
+func (ai *AlertsIndex) name() string {
+	return "AlertsIndex"
+}
+
+func (ai *AlertsIndex) template() string {
+	return "alerts-index.html"
+}
+
+func (ai *AlertsIndex) data() interface{} {
+	return map[string]interface{}{
+		"Alerts":   ai.alerts,
+		"Filter":   ai.filter,
+		"Monitors": ai.monitors,
+		"Labels":   ai.labels,
+	}
+}
+
+func (ai *AlertsIndex) scripts() []string {
+	return nil
+}
+
+func (ai *AlertsIndex) breadcrumbs() []vm.Breadcrumb {
+	return vm.AlertsIndexBcs()
+}
+
+func (ai *AlertsIndex) subRenderables() []Renderable {
+	return nil
+}
+
+func (ai *AlertsIndex) renderPropagate() (*renderResult, error) {
+	return renderPropagate(ai)
+}
+
+func (ai *AlertsIndex) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -0,0 +1,48 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationPoliciesIndex struct {
+	policies []*vm.EscalationPolicy
+	subs     []Renderable
+}
+
+func NewEscalationPoliciesIndex(eps []*vm.EscalationPolicy) *EscalationPoliciesIndex {
+	epi := new(EscalationPoliciesIndex)
+	epi.policies = eps
+	return epi
+}
+
+func (epi *EscalationPoliciesIndex) name() string {
+	return "EscalationPoliciesIndex"
+}
+
+func (epi *EscalationPoliciesIndex) template() string {
+	return "escalation-policies-index.html"
+}
+
+func (epi *EscalationPoliciesIndex) data() interface{} {
+	return epi.policies
+}
+
+func (epi *EscalationPoliciesIndex) scripts() []string {
+	return nil
+}
+
+func (epi *EscalationPoliciesIndex) breadcrumbs() []vm.Breadcrumb {
+	return vm.EscalationPoliciesIndexBcs()
+}
+
+func (epi *EscalationPoliciesIndex) subRenderables() []Renderable {
+	return nil
+}
+
+func (epi *EscalationPoliciesIndex) renderPropagate() (*renderResult, error) {
+	return renderPropagate(epi)
+}
+
+func (epi *EscalationPoliciesIndex) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataArray(parent, child)
+}
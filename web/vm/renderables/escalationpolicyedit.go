@@ -0,0 +1,61 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationPolicyEdit struct {
+	policy   *vm.EscalationPolicy
+	monitors []*vm.Monitor
+	labels   []*vm.Label
+	subs     []Renderable
+}
+
+func NewEscalationPolicyEdit(ep *vm.EscalationPolicy, ms []*vm.Monitor, ls []*vm.Label) *EscalationPolicyEdit {
+	epe := EscalationPolicyEdit{}
+	epe.policy = ep
+	epe.monitors = ms
+	epe.labels = ls
+	epe.subs = []Renderable{
+		NewEscalationStepsEdit(ep.Steps),
+	}
+	return &epe
+}
+
+func (epe *EscalationPolicyEdit) name() string {
+	return "EscalationPolicy"
+}
+
+func (epe *EscalationPolicyEdit) template() string {
+	return "escalation-policies-edit.html"
+}
+
+func (epe *EscalationPolicyEdit) data() interface{} {
+	return map[string]interface{}{
+		"EscalationPolicy": epe.policy,
+		"Monitors":         epe.monitors,
+		"Labels":           epe.labels,
+	}
+}
+
+func (epe *EscalationPolicyEdit) scripts() []string {
+	return []string{
+		"escalation-policies-edit.js",
+	}
+}
+
+func (epe *EscalationPolicyEdit) breadcrumbs() []vm.Breadcrumb {
+	return vm.EscalationPolicyViewBcs(epe.policy.Name, epe.policy.Id())
+}
+
+func (epe *EscalationPolicyEdit) subRenderables() []Renderable {
+	return epe.subs
+}
+
+func (epe *EscalationPolicyEdit) renderPropagate() (*renderResult, error) {
+	return renderPropagate(epe)
+}
+
+func (epe *EscalationPolicyEdit) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
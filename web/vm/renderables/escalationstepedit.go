@@ -0,0 +1,53 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationStepEdit struct {
+	step *vm.EscalationStep
+	subs []Renderable
+}
+
+func NewEscalationStepEdit(t *vm.EscalationStep) *EscalationStepEdit {
+	te := EscalationStepEdit{}
+	te.step = t
+	te.subs = []Renderable{
+		NewTargetEdit(t.Trigger.Target),
+	}
+	return &te
+}
+
+func (te *EscalationStepEdit) name() string {
+	return "EscalationStep"
+}
+
+func (te *EscalationStepEdit) template() string {
+	return "partials/escalation-step-edit.html"
+}
+
+func (te *EscalationStepEdit) data() interface{} {
+	return te.step
+}
+
+func (te *EscalationStepEdit) scripts() []string {
+	return []string{
+		"trigger-edit.js",
+	}
+}
+
+func (te *EscalationStepEdit) breadcrumbs() []vm.Breadcrumb {
+	return nil
+}
+
+func (te *EscalationStepEdit) subRenderables() []Renderable {
+	return te.subs
+}
+
+func (te *EscalationStepEdit) renderPropagate() (*renderResult, error) {
+	return renderPropagate(te)
+}
+
+func (te *EscalationStepEdit) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -0,0 +1,56 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationStepsEdit struct {
+	subs []Renderable
+}
+
+func NewEscalationStepsEdit(ts []*vm.EscalationStep) *EscalationStepsEdit {
+	tse := EscalationStepsEdit{}
+	tse.subs = []Renderable{
+		NewEscalationStepEdit(vm.BlankEscalationStep()),
+	}
+	for _, step := range ts {
+		tse.subs = append(tse.subs, NewEscalationStepEdit(step))
+	}
+
+	return &tse
+}
+
+func (tse *EscalationStepsEdit) name() string {
+	return "Steps"
+}
+
+func (tse *EscalationStepsEdit) template() string {
+	return "partials/escalation-steps-edit.html"
+}
+
+func (tse *EscalationStepsEdit) data() interface{} {
+	return nil
+}
+
+func (tse *EscalationStepsEdit) scripts() []string {
+	return []string{
+		"escalation-steps-edit.js",
+		"triggers-edit.js",
+	}
+}
+
+func (tse *EscalationStepsEdit) breadcrumbs() []vm.Breadcrumb {
+	return nil
+}
+
+func (tse *EscalationStepsEdit) subRenderables() []Renderable {
+	return tse.subs
+}
+
+func (tse *EscalationStepsEdit) renderPropagate() (*renderResult, error) {
+	return renderPropagate(tse)
+}
+
+func (tse *EscalationStepsEdit) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataArray(parent, child)
+}
@@ -0,0 +1,50 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationStepsView struct {
+	subs []Renderable
+}
+
+func NewEscalationStepsView(ts []*vm.EscalationStep) *EscalationStepsView {
+	tsv := EscalationStepsView{}
+	for _, step := range ts {
+		tsv.subs = append(tsv.subs, NewEscalationStepView(step))
+	}
+
+	return &tsv
+}
+
+func (tsv *EscalationStepsView) name() string {
+	return "Steps"
+}
+
+func (tsv *EscalationStepsView) template() string {
+	return "partials/triggers-view.html"
+}
+
+func (tsv *EscalationStepsView) data() interface{} {
+	return nil
+}
+
+func (tsv *EscalationStepsView) scripts() []string {
+	return nil
+}
+
+func (tsv *EscalationStepsView) breadcrumbs() []vm.Breadcrumb {
+	return nil
+}
+
+func (tsv *EscalationStepsView) subRenderables() []Renderable {
+	return tsv.subs
+}
+
+func (tsv *EscalationStepsView) renderPropagate() (*renderResult, error) {
+	return renderPropagate(tsv)
+}
+
+func (tsv *EscalationStepsView) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataArray(parent, child)
+}
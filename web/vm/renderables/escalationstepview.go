@@ -0,0 +1,51 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type EscalationStepView struct {
+	step *vm.EscalationStep
+	subs []Renderable
+}
+
+func NewEscalationStepView(t *vm.EscalationStep) *EscalationStepView {
+	tv := EscalationStepView{}
+	tv.step = t
+	tv.subs = []Renderable{
+		NewTargetView(t.Trigger.Target),
+	}
+	return &tv
+}
+
+func (tv *EscalationStepView) name() string {
+	return "EscalationStep"
+}
+
+func (tv *EscalationStepView) template() string {
+	return "partials/trigger-view.html"
+}
+
+func (tv *EscalationStepView) data() interface{} {
+	return tv.step
+}
+
+func (tv *EscalationStepView) scripts() []string {
+	return nil
+}
+
+func (tv *EscalationStepView) breadcrumbs() []vm.Breadcrumb {
+	return nil
+}
+
+func (tv *EscalationStepView) subRenderables() []Renderable {
+	return tv.subs
+}
+
+func (tv *EscalationStepView) renderPropagate() (*renderResult, error) {
+	return renderPropagate(tv)
+}
+
+func (tv *EscalationStepView) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -0,0 +1,52 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type Login struct {
+	next string
+	err  string
+}
+
+func NewLogin(next string, err string) *Login {
+	l := new(Login)
+	l.next = next
+	l.err = err
+	return l
+}
+
+func (l *Login) name() string {
+	return "Login"
+}
+
+func (l *Login) template() string {
+	return "login.html"
+}
+
+func (l *Login) data() interface{} {
+	return map[string]interface{}{
+		"Next":  l.next,
+		"Error": l.err,
+	}
+}
+
+func (l *Login) scripts() []string {
+	return nil
+}
+
+func (l *Login) breadcrumbs() []vm.Breadcrumb {
+	return nil
+}
+
+func (l *Login) subRenderables() []Renderable {
+	return nil
+}
+
+func (l *Login) renderPropagate() (*renderResult, error) {
+	return renderPropagate(l)
+}
+
+func (l *Login) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
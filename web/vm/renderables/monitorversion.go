@@ -0,0 +1,56 @@
+package renderables
+
+import (
+	"github.com/yext/revere/history"
+	"github.com/yext/revere/web/vm"
+)
+
+type MonitorVersion struct {
+	monitor *vm.Monitor
+	change  *history.Change
+	errs    []string
+}
+
+func NewMonitorVersion(m *vm.Monitor, c *history.Change, errs []string) *MonitorVersion {
+	mv := MonitorVersion{}
+	mv.monitor = m
+	mv.change = c
+	mv.errs = errs
+	return &mv
+}
+
+func (mv *MonitorVersion) name() string {
+	return "MonitorVersion"
+}
+
+func (mv *MonitorVersion) template() string {
+	return "monitors-version.html"
+}
+
+func (mv *MonitorVersion) data() interface{} {
+	return map[string]interface{}{
+		"Monitor": mv.monitor,
+		"Change":  mv.change,
+		"Errors":  mv.errs,
+	}
+}
+
+func (mv *MonitorVersion) scripts() []string {
+	return nil
+}
+
+func (mv *MonitorVersion) breadcrumbs() []vm.Breadcrumb {
+	return vm.MonitorVersionBcs(mv.monitor.Name, mv.monitor.Id(), mv.change.Version.Version)
+}
+
+func (mv *MonitorVersion) subRenderables() []Renderable {
+	return nil
+}
+
+func (mv *MonitorVersion) renderPropagate() (*renderResult, error) {
+	return renderPropagate(mv)
+}
+
+func (mv *MonitorVersion) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -1,18 +1,21 @@
 package renderables
 
 import (
+	"github.com/yext/revere/history"
 	"github.com/yext/revere/web/vm"
 )
 
 type MonitorView struct {
 	monitor    *vm.Monitor
+	versions   []*history.Version
 	subs       []Renderable
 	saveStatus string
 }
 
-func NewMonitorView(m *vm.Monitor, saveStatus []byte) *MonitorView {
+func NewMonitorView(m *vm.Monitor, versions []*history.Version, saveStatus []byte) *MonitorView {
 	mv := MonitorView{}
 	mv.monitor = m
+	mv.versions = versions
 	mv.subs = []Renderable{
 		NewProbeView(m.Probe),
 		NewMonitorTriggersView(m.Triggers),
@@ -33,6 +36,7 @@ func (mv *MonitorView) template() string {
 func (mv *MonitorView) data() interface{} {
 	return map[string]interface{}{
 		"Monitor":    mv.monitor,
+		"Versions":   mv.versions,
 		"SaveStatus": mv.saveStatus,
 	}
 }
@@ -0,0 +1,54 @@
+package renderables
+
+import (
+	"github.com/yext/revere/web/vm"
+)
+
+type ScheduleEdit struct {
+	schedule *vm.Schedule
+	subs     []Renderable
+}
+
+func NewScheduleEdit(s *vm.Schedule) *ScheduleEdit {
+	se := ScheduleEdit{}
+	se.schedule = s
+	return &se
+}
+
+func (se *ScheduleEdit) name() string {
+	return "Schedule"
+}
+
+func (se *ScheduleEdit) template() string {
+	return "schedules-edit.html"
+}
+
+func (se *ScheduleEdit) data() interface{} {
+	return map[string]interface{}{
+		"Schedule":      se.schedule,
+		"BlankLayer":    vm.BlankScheduleLayer(),
+		"BlankOverride": vm.BlankScheduleOverride(),
+	}
+}
+
+func (se *ScheduleEdit) scripts() []string {
+	return []string{
+		"schedules-edit.js",
+	}
+}
+
+func (se *ScheduleEdit) breadcrumbs() []vm.Breadcrumb {
+	return vm.ScheduleViewBcs(se.schedule.Name, se.schedule.Id())
+}
+
+func (se *ScheduleEdit) subRenderables() []Renderable {
+	return se.subs
+}
+
+func (se *ScheduleEdit) renderPropagate() (*renderResult, error) {
+	return renderPropagate(se)
+}
+
+func (se *ScheduleEdit) aggregatePipelineData(parent *renderResult, child *renderResult) {
+	aggregatePipelineDataMap(parent, child)
+}
@@ -8,15 +8,17 @@ import (
 type SubprobeView struct {
 	subprobe *vm.Subprobe
 	readings []*vm.Reading
+	alerts   []*vm.Alert
 	probe    probe.VM
 	subs     []Renderable
 }
 
-func NewSubprobeView(p probe.VM, s *vm.Subprobe, rs []*vm.Reading) *SubprobeView {
+func NewSubprobeView(p probe.VM, s *vm.Subprobe, rs []*vm.Reading, as []*vm.Alert) *SubprobeView {
 	sv := SubprobeView{}
 	sv.subprobe = s
 	sv.probe = p
 	sv.readings = rs
+	sv.alerts = as
 	pp := NewProbePreview(p)
 	sv.subs = []Renderable{pp}
 	return &sv
@@ -34,6 +36,7 @@ func (sv *SubprobeView) data() interface{} {
 	return map[string]interface{}{
 		"Subprobe":      sv.subprobe,
 		"Readings":      sv.readings,
+		"Alerts":        sv.alerts,
 		"PreviewParams": sv.probe.SerializeForFrontend(),
 	}
 }
@@ -14,9 +14,11 @@ type TargetView struct {
 	subs      []Renderable
 }
 
+// NewTargetView shows t with its secrets redacted, as view pages are open to
+// viewers.
 func NewTargetView(t target.VM) *TargetView {
 	tv := TargetView{}
-	tv.viewmodel = t
+	tv.viewmodel = target.Redact(t)
 	tv.subs = []Renderable{}
 	return &tv
 }
@@ -0,0 +1,318 @@
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/yext/revere/db"
+	"github.com/yext/revere/schedule"
+	"github.com/yext/revere/util"
+)
+
+// Schedule is an on-call schedule. Later layers take precedence over earlier
+// ones, and overrides take precedence over all layers.
+type Schedule struct {
+	ScheduleID  db.ScheduleID
+	Name        string
+	Description string
+	Layers      []*ScheduleLayer
+	Overrides   []*ScheduleOverride
+}
+
+// ScheduleLayer is a rotation through Members that hands off every Shift
+// ShiftType, starting at Start.
+type ScheduleLayer struct {
+	Start     time.Time
+	Shift     int64
+	ShiftType string
+	Members   []schedule.Person
+}
+
+// ScheduleOverride puts someone on call between Start and End.
+type ScheduleOverride struct {
+	schedule.Person
+	Start time.Time
+	End   time.Time
+}
+
+const (
+	maxScheduleNameLength = 30
+)
+
+func (*Schedule) ComponentName() string {
+	return "Schedule"
+}
+
+func (s *Schedule) Id() int64 {
+	return int64(s.ScheduleID)
+}
+
+func NewSchedule(tx *db.Tx, id db.ScheduleID) (*Schedule, error) {
+	s, err := tx.LoadSchedule(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s == nil {
+		return nil, errors.Errorf("Schedule not found: %d", id)
+	}
+
+	sched := newScheduleFromDB(s)
+	err = sched.loadComponents(tx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return sched, nil
+}
+
+func newScheduleFromDB(s *db.Schedule) *Schedule {
+	return &Schedule{
+		ScheduleID:  s.ScheduleID,
+		Name:        s.Name,
+		Description: s.Description,
+	}
+}
+
+func BlankSchedule() *Schedule {
+	return &Schedule{
+		Layers:    []*ScheduleLayer{},
+		Overrides: []*ScheduleOverride{},
+	}
+}
+
+// BlankScheduleLayer returns a daily rotation with one empty member.
+func BlankScheduleLayer() *ScheduleLayer {
+	return &ScheduleLayer{
+		Shift:     1,
+		ShiftType: "day",
+		Members:   []schedule.Person{{}},
+	}
+}
+
+func BlankScheduleOverride() *ScheduleOverride {
+	return &ScheduleOverride{}
+}
+
+// AllSchedules loads every schedule with its layers and overrides, so that
+// who is on call can be shown.
+func AllSchedules(tx *db.Tx) ([]*Schedule, error) {
+	dbSchedules, err := tx.LoadSchedules()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	schedules := make([]*Schedule, len(dbSchedules))
+	for i, s := range dbSchedules {
+		schedules[i] = newScheduleFromDB(s)
+		err = schedules[i].loadComponents(tx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return schedules, nil
+}
+
+func (s *Schedule) loadComponents(tx *db.Tx) error {
+	layers, err := tx.LoadScheduleLayers(s.ScheduleID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.Layers = make([]*ScheduleLayer, len(layers))
+	for i, l := range layers {
+		members, err := schedule.LoadMembers(l.Members)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		shift, shiftType := util.GetPeriodAndType(l.ShiftMilli)
+		s.Layers[i] = &ScheduleLayer{
+			Start:     l.Start,
+			Shift:     shift,
+			ShiftType: shiftType,
+			Members:   members,
+		}
+	}
+
+	overrides, err := tx.LoadScheduleOverrides(s.ScheduleID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.Overrides = make([]*ScheduleOverride, len(overrides))
+	for i, o := range overrides {
+		s.Overrides[i] = &ScheduleOverride{
+			Person: schedule.Person{
+				Name:        o.Name,
+				Email:       o.Email,
+				SlackHandle: o.SlackHandle,
+			},
+			Start: o.Start,
+			End:   o.End,
+		}
+	}
+	return nil
+}
+
+func (s *Schedule) toSchedule() *schedule.Schedule {
+	sched := &schedule.Schedule{
+		ScheduleID: s.ScheduleID,
+		Name:       s.Name,
+		Layers:     make([]schedule.Layer, len(s.Layers)),
+		Overrides:  make([]schedule.Override, len(s.Overrides)),
+	}
+	for i, l := range s.Layers {
+		sched.Layers[i] = l.toLayer()
+	}
+	for i, o := range s.Overrides {
+		sched.Overrides[i] = schedule.Override{
+			Person: o.Person,
+			Start:  o.Start,
+			End:    o.End,
+		}
+	}
+	return sched
+}
+
+// OnCallNow returns who is currently on call, or nil if nobody is.
+func (s *Schedule) OnCallNow() *schedule.Person {
+	return s.toSchedule().OnCall(time.Now().UTC())
+}
+
+// UpcomingOverrides returns the overrides that have not ended yet.
+func (s *Schedule) UpcomingOverrides() []*ScheduleOverride {
+	now := time.Now().UTC()
+
+	var upcoming []*ScheduleOverride
+	for _, o := range s.Overrides {
+		if o.End.After(now) {
+			upcoming = append(upcoming, o)
+		}
+	}
+	return upcoming
+}
+
+func (l *ScheduleLayer) toLayer() schedule.Layer {
+	return schedule.Layer{
+		Start:   l.Start,
+		Shift:   time.Duration(util.GetMs(l.Shift, l.ShiftType)) * time.Millisecond,
+		Members: l.Members,
+	}
+}
+
+// OnCallNow returns which member of the layer is currently on call, or nil if
+// none is.
+func (l *ScheduleLayer) OnCallNow() *schedule.Person {
+	layer := l.toLayer()
+	return layer.OnCall(time.Now().UTC())
+}
+
+// NextHandoff returns when the layer next hands off to another member.
+func (l *ScheduleLayer) NextHandoff() time.Time {
+	layer := l.toLayer()
+	return layer.NextHandoff(time.Now().UTC())
+}
+
+func (s *Schedule) Validate() (errs []string) {
+	if s.Name == "" {
+		errs = append(errs, "Schedule name is required.")
+	} else if len(s.Name) > maxScheduleNameLength {
+		errs = append(errs, fmt.Sprintf("Schedule name cannot be longer than %d characters.", maxScheduleNameLength))
+	}
+
+	for i, l := range s.Layers {
+		errs = append(errs, l.validate(i+1)...)
+	}
+
+	for _, o := range s.Overrides {
+		errs = append(errs, o.validate()...)
+	}
+	return
+}
+
+func (l *ScheduleLayer) validate(n int) (errs []string) {
+	if l.Start.IsZero() {
+		errs = append(errs, fmt.Sprintf("Layer %d needs a start time.", n))
+	}
+	if l.Shift <= 0 || util.GetMs(l.Shift, l.ShiftType) == 0 {
+		errs = append(errs, fmt.Sprintf("Invalid shift length for layer %d: %d %s", n, l.Shift, l.ShiftType))
+	}
+	if len(l.Members) == 0 {
+		errs = append(errs, fmt.Sprintf("Layer %d needs at least one member.", n))
+	}
+	for _, p := range l.Members {
+		errs = append(errs, validatePerson(p, fmt.Sprintf("layer %d", n))...)
+	}
+	return
+}
+
+func (o *ScheduleOverride) validate() (errs []string) {
+	errs = append(errs, validatePerson(o.Person, "an override")...)
+	if !o.End.After(o.Start) {
+		errs = append(errs, fmt.Sprintf("Override for %s must end after it starts.", o.Name))
+	}
+	return
+}
+
+// validatePerson checks that p has a name and a way to be alerted.
+func validatePerson(p schedule.Person, in string) (errs []string) {
+	if p.Name == "" {
+		errs = append(errs, fmt.Sprintf("Every person in %s needs a name.", in))
+	}
+	if p.Email == "" && p.SlackHandle == "" {
+		errs = append(errs, fmt.Sprintf("%s in %s needs an email address or Slack handle.", p.Name, in))
+	}
+	return
+}
+
+func (s *Schedule) IsCreate() bool {
+	return s.Id() == 0
+}
+
+func (s *Schedule) Save(tx *db.Tx) error {
+	sched := &db.Schedule{
+		ScheduleID:  s.ScheduleID,
+		Name:        s.Name,
+		Description: s.Description,
+	}
+
+	var err error
+	if isCreate(s) {
+		s.ScheduleID, err = tx.CreateSchedule(sched)
+	} else {
+		err = tx.UpdateSchedule(sched)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	layers := make([]db.ScheduleLayer, len(s.Layers))
+	for i, l := range s.Layers {
+		members, err := schedule.SerializeMembers(l.Members)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		layers[i] = db.ScheduleLayer{
+			Position:   int32(i),
+			Start:      l.Start.UTC(),
+			ShiftMilli: util.GetMs(l.Shift, l.ShiftType),
+			Members:    members,
+		}
+	}
+	err = tx.SetScheduleLayers(s.ScheduleID, layers)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	overrides := make([]db.ScheduleOverride, len(s.Overrides))
+	for i, o := range s.Overrides {
+		overrides[i] = db.ScheduleOverride{
+			Name:        o.Name,
+			Email:       o.Email,
+			SlackHandle: o.SlackHandle,
+			Start:       o.Start.UTC(),
+			End:         o.End.UTC(),
+		}
+	}
+	return errors.Trace(tx.SetScheduleOverrides(s.ScheduleID, overrides))
+}
@@ -0,0 +1,54 @@
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yext/revere/schedule"
+)
+
+func validSchedule() *Schedule {
+	s := BlankSchedule()
+	s.Name = "Ops"
+	s.Layers = []*ScheduleLayer{{
+		Start:     time.Date(2016, time.June, 6, 9, 0, 0, 0, time.UTC),
+		Shift:     7,
+		ShiftType: "day",
+		Members: []schedule.Person{
+			{Name: "Alice", Email: "alice@example.com"},
+			{Name: "Bob", SlackHandle: "bob"},
+		},
+	}}
+	s.Overrides = []*ScheduleOverride{{
+		Person: schedule.Person{Name: "Carol", Email: "carol@example.com"},
+		Start:  time.Date(2016, time.June, 8, 9, 0, 0, 0, time.UTC),
+		End:    time.Date(2016, time.June, 9, 9, 0, 0, 0, time.UTC),
+	}}
+	return s
+}
+
+func TestValidSchedule(t *testing.T) {
+	if errs := validSchedule().Validate(); errs != nil {
+		t.Errorf("Unexpected errors for schedule: %v\n", errs)
+	}
+}
+
+func TestInvalidSchedules(t *testing.T) {
+	cases := map[string]func(s *Schedule){
+		"missing name":         func(s *Schedule) { s.Name = "" },
+		"missing layer start":  func(s *Schedule) { s.Layers[0].Start = time.Time{} },
+		"zero shift":           func(s *Schedule) { s.Layers[0].Shift = 0 },
+		"unknown shift type":   func(s *Schedule) { s.Layers[0].ShiftType = "fortnight" },
+		"no members":           func(s *Schedule) { s.Layers[0].Members = nil },
+		"member without name":  func(s *Schedule) { s.Layers[0].Members[0].Name = "" },
+		"member without email": func(s *Schedule) { s.Layers[0].Members[0].Email = "" },
+		"override ends early":  func(s *Schedule) { s.Overrides[0].End = s.Overrides[0].Start },
+	}
+	for name, invalidate := range cases {
+		s := validSchedule()
+		invalidate(s)
+		if errs := s.Validate(); len(errs) != 1 {
+			t.Errorf("Expected one error for schedule with %s, got: %v\n", name, errs)
+		}
+	}
+}
@@ -218,3 +218,7 @@ func (s *Silence) Save(tx *db.Tx) error {
 		return errors.Trace(err)
 	}
 }
+
+func DeleteSilence(tx *db.Tx, id db.SilenceID) error {
+	return errors.Trace(tx.DeleteMonitorSilence(id))
+}
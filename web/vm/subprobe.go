@@ -19,6 +19,15 @@ type SubprobeStatus struct {
 	EnteredState    time.Time
 	FmtEnteredState string
 	LastNormal      time.Time
+
+	// PendingState is the state the subprobe will change to once it has
+	// been sustained long enough. There is no pending change if
+	// PendingReadings is 0.
+	PendingState    state.State
+	PendingReadings int32
+	PendingSince    *time.Time
+
+	Flapping bool
 }
 
 type Subprobe struct {
@@ -28,6 +37,9 @@ type Subprobe struct {
 	Name        string
 	Archived    *time.Time
 	Status      SubprobeStatus
+
+	// Ack is the subprobe's active acknowledgement, if any.
+	Ack *Acknowledgement
 }
 
 func (s *Subprobe) Id() int64 {
@@ -43,7 +55,13 @@ func NewSubprobe(DB *db.DB, id db.SubprobeID) (*Subprobe, error) {
 		return nil, errors.Errorf("Subprobe not found: %d", id)
 	}
 
-	return newSubprobeWithStatusFromDB(s), nil
+	subprobe := newSubprobeWithStatusFromDB(s)
+	subprobe.Ack, err = NewAcknowledgement(DB, id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return subprobe, nil
 }
 
 func newSubprobeFromDB(s *db.Subprobe) *Subprobe {
@@ -76,6 +94,10 @@ func newSubprobeWithStatusFromDB(s *db.SubprobeWithStatusInfo) *Subprobe {
 		LastNormal:   s.LastNormal,
 		FmtEnteredState: durationfmt.MostSigUnit().Format(
 			time.Now().UTC().Sub(s.EnteredState)),
+		PendingState:    s.PendingState,
+		PendingReadings: s.PendingReadings,
+		PendingSince:    s.PendingSince,
+		Flapping:        s.Flapping,
 	}
 
 	return &Subprobe{
@@ -119,7 +141,8 @@ func AllAbnormalSubprobes(tx *db.Tx) ([]*Subprobe, error) {
 		return nil, err
 	}
 
-	return newSubprobesWithStatusFromDB(ss), nil
+	subprobes := newSubprobesWithStatusFromDB(ss)
+	return subprobes, populateAcknowledgements(tx, subprobes)
 }
 
 func AllAbnormalSubprobesForLabel(tx *db.Tx, id db.LabelID) ([]*Subprobe, error) {
@@ -128,7 +151,8 @@ func AllAbnormalSubprobesForLabel(tx *db.Tx, id db.LabelID) ([]*Subprobe, error)
 		return nil, err
 	}
 
-	return newSubprobesWithStatusFromDB(ss), nil
+	subprobes := newSubprobesWithStatusFromDB(ss)
+	return subprobes, populateAcknowledgements(tx, subprobes)
 }
 
 func AllMonitorLabelsForSubprobes(tx *db.Tx, subprobes []*Subprobe) (map[db.MonitorID][]*MonitorLabel, error) {
@@ -23,6 +23,14 @@ type Trigger struct {
 	TriggerOnExit bool
 	Target        target.VM
 	Delete        bool
+
+	// GroupWindow is how long alerts are held to be sent together, and
+	// Digest is how often alerts no worse than Warning are sent together
+	// instead. Either is off if zero.
+	GroupWindow     int64
+	GroupWindowType string
+	Digest          int64
+	DigestType      string
 }
 
 func newTriggerFromModel(trigger *db.Trigger) (*Trigger, error) {
@@ -32,6 +40,8 @@ func newTriggerFromModel(trigger *db.Trigger) (*Trigger, error) {
 	}
 
 	period, periodType := util.GetPeriodAndType(int64(trigger.PeriodMilli))
+	groupWindow, groupWindowType := util.GetPeriodAndType(int64(trigger.GroupMilli))
+	digest, digestType := util.GetPeriodAndType(int64(trigger.DigestMilli))
 
 	return &Trigger{
 		TriggerID:     trigger.TriggerID,
@@ -42,6 +52,11 @@ func newTriggerFromModel(trigger *db.Trigger) (*Trigger, error) {
 		TargetParams:  "",
 		TriggerOnExit: trigger.TriggerOnExit,
 		Target:        target,
+
+		GroupWindow:     groupWindow,
+		GroupWindowType: groupWindowType,
+		Digest:          digest,
+		DigestType:      digestType,
 	}, nil
 }
 
@@ -73,6 +88,14 @@ func (t *Trigger) validate() (errs []string) {
 		errs = append(errs, fmt.Sprintf("Invalid period for trigger: %d %s", t.Period, t.PeriodType))
 	}
 
+	if t.GroupWindow < 0 || (t.GroupWindow > 0 && util.GetMs(t.GroupWindow, t.GroupWindowType) == 0) {
+		errs = append(errs, fmt.Sprintf("Invalid grouping window for trigger: %d %s", t.GroupWindow, t.GroupWindowType))
+	}
+
+	if t.Digest < 0 || (t.Digest > 0 && util.GetMs(t.Digest, t.DigestType) == 0) {
+		errs = append(errs, fmt.Sprintf("Invalid digest period for trigger: %d %s", t.Digest, t.DigestType))
+	}
+
 	return
 }
 
@@ -98,5 +121,7 @@ func (t *Trigger) toDBTrigger() (*db.Trigger, error) {
 		PeriodMilli:   int32(util.GetMs(int64(t.Period), t.PeriodType)),
 		TargetType:    t.TargetType,
 		Target:        types.JSONText(triggerJSON),
+		GroupMilli:    int32(util.GetMs(t.GroupWindow, t.GroupWindowType)),
+		DigestMilli:   int32(util.GetMs(t.Digest, t.DigestType)),
 	}, nil
 }
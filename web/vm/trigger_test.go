@@ -63,3 +63,26 @@ func TestValidTriggerPeriod(t *testing.T) {
 		}
 	}
 }
+
+func TestTriggerGrouping(t *testing.T) {
+	trigger := validTrigger()
+	trigger.GroupWindow = 30
+	trigger.GroupWindowType = "second"
+	trigger.Digest = 1
+	trigger.DigestType = "hour"
+	if errs := trigger.validate(); errs != nil {
+		t.Errorf("Unexpected errors for grouping and digest: %v\n", errs)
+	}
+
+	trigger.GroupWindowType = ""
+	if errs := trigger.validate(); errs == nil {
+		t.Error("Expected error for grouping window without a period type")
+	}
+
+	trigger = validTrigger()
+	trigger.Digest = -1
+	trigger.DigestType = "hour"
+	if errs := trigger.validate(); errs == nil {
+		t.Error("Expected error for negative digest period")
+	}
+}